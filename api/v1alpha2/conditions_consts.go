@@ -42,21 +42,50 @@ const (
 	// CAPIVersionIncompatibilityReason documents that the provider version is incompatible with operator.
 	CAPIVersionIncompatibilityReason = "CAPIVersionIncompatibility"
 
+	// VersionPolicyResolutionErrorReason documents that the provider version policy could not be resolved
+	// against the available releases.
+	VersionPolicyResolutionErrorReason = "VersionPolicyResolutionError"
+
+	// OCIVerificationFailedReason documents that the OCI artifact doesn't match the pinned digest,
+	// or that its signature can't be verified.
+	OCIVerificationFailedReason = "OCIVerificationFailed"
+
+	// ChecksumVerificationFailedReason documents that the checksum of the provider manifests
+	// doesn't match the expected one.
+	ChecksumVerificationFailedReason = "ChecksumVerificationFailed"
+
 	// ComponentsFetchErrorReason documents that an error occurred fetching the components.
 	ComponentsFetchErrorReason = "ComponentsFetchError"
 
+	// MissingVariablesReason documents that variables required by the components are not set in the provider
+	// configuration secret.
+	MissingVariablesReason = "MissingVariables"
+
 	// ComponentsCustomizationErrorReason documents that an error occurred customizing the components.
 	ComponentsCustomizationErrorReason = "ComponentsCustomizationError"
 
 	// ComponentsPatchErrorReason documents that an error occurred patching the components.
 	ComponentsPatchErrorReason = "ComponentsPatchError"
 
+	// ComponentsKustomizationErrorReason documents that an error occurred rendering the kustomization over the components.
+	ComponentsKustomizationErrorReason = "ComponentsKustomizationError"
+
 	// ComponentsImageOverrideErrorReason documents that an error occurred overriding the components image.
 	ComponentsImageOverrideErrorReason = "ComponentsImageOverrideError"
 
+	// PreviewErrorReason documents that an error occurred computing or storing the changes preview.
+	PreviewErrorReason = "PreviewError"
+
 	// ComponentsUpgradeErrorReason documents that an error occurred while upgrading the components.
 	ComponentsUpgradeErrorReason = "ComponentsUpgradeError"
 
+	// ComponentsRollbackErrorReason documents that an error occurred while rolling back a failed upgrade.
+	ComponentsRollbackErrorReason = "ComponentsRollbackError"
+
+	// ComponentsUpgradeRolledBackReason documents that the upgrade failed and the provider was rolled back
+	// to the previously installed version.
+	ComponentsUpgradeRolledBackReason = "ComponentsUpgradeRolledBack"
+
 	// OldComponentsDeletionErrorReason documents that an error occurred deleting the old components prior to upgrading.
 	OldComponentsDeletionErrorReason = "OldComponentsDeletionError"
 
@@ -72,8 +101,18 @@ const (
 	// DeploymentAvailableReason documents that the provider deployment is available.
 	DeploymentAvailableReason = "DeploymentAvailable"
 
+	// WaitingForMaintenanceWindowReason documents that the provider upgrade is waiting for the maintenance window.
+	WaitingForMaintenanceWindowReason = "WaitingForMaintenanceWindow"
+
+	// InvalidMaintenanceWindowReason documents that the provider maintenance window is configured incorrectly.
+	InvalidMaintenanceWindowReason = "InvalidMaintenanceWindow"
+
 	// UnsupportedProviderDowngradeReason documents that the provider downgrade is not supported.
 	UnsupportedProviderDowngradeReason = "UnsupportedProviderDowngradeReason"
+
+	// IncompatibleDowngradeContractReason documents that the contract of the downgrade target version
+	// doesn't match the contract of the installed core provider.
+	IncompatibleDowngradeContractReason = "IncompatibleDowngradeContract"
 )
 
 const (
@@ -82,4 +121,86 @@ const (
 
 	// ProviderUpgradedCondition documents a Provider that has been recently upgraded.
 	ProviderUpgradedCondition string = "ProviderUpgraded"
+
+	// UpgradePendingCondition documents a Provider version change which is held until the maintenance window opens.
+	UpgradePendingCondition string = "UpgradePending"
+
+	// UpgradeCompletedCondition documents a ProviderUpgradePlan which upgraded all its providers.
+	UpgradeCompletedCondition string = "UpgradeCompleted"
+
+	// OCIArtifactUpToDateCondition documents whether the OCI reference of a Provider still resolves to the
+	// artifact its manifests were fetched from.
+	OCIArtifactUpToDateCondition string = "OCIArtifactUpToDate"
+
+	// VariablesResolvedCondition documents whether all the variables required by the components of the Provider
+	// version are set.
+	VariablesResolvedCondition string = "VariablesResolved"
+
+	// ComponentsDriftedCondition documents whether the installed components of a Provider were changed or deleted
+	// outside the operator, and differ from the cached manifests.
+	ComponentsDriftedCondition string = "ComponentsDrifted"
+
+	// PreviewApprovedCondition documents whether the changes preview of a Provider is approved and can be rolled out.
+	PreviewApprovedCondition string = "PreviewApproved"
+)
+
+const (
+	// OCIArtifactUpToDateReason documents that the OCI reference resolves to the fetched artifact.
+	OCIArtifactUpToDateReason = "OCIArtifactUpToDate"
+
+	// OCITagDriftedReason documents that the OCI tag was re-pushed and resolves to a different artifact
+	// than the one the provider manifests were fetched from.
+	OCITagDriftedReason = "OCITagDrifted"
+
+	// OCIDriftCheckFailedReason documents that the OCI reference couldn't be resolved to detect drift.
+	OCIDriftCheckFailedReason = "OCIDriftCheckFailed"
+)
+
+const (
+	// UpgradePlanInvalidReason documents that the ProviderUpgradePlan targets can't be resolved or validated.
+	UpgradePlanInvalidReason = "UpgradePlanInvalid"
+
+	// UpgradeInProgressReason documents that the ProviderUpgradePlan is upgrading its providers.
+	UpgradeInProgressReason = "UpgradeInProgress"
+
+	// ProviderUpgradeFailedReason documents that a provider of the ProviderUpgradePlan failed to upgrade.
+	ProviderUpgradeFailedReason = "ProviderUpgradeFailed"
+
+	// ProviderUpgradeTimeoutReason documents that a provider of the ProviderUpgradePlan wasn't upgraded
+	// within the step timeout.
+	ProviderUpgradeTimeoutReason = "ProviderUpgradeTimeout"
+
+	// UpgradeCompletedReason documents that all the providers of the ProviderUpgradePlan are upgraded.
+	UpgradeCompletedReason = "UpgradeCompleted"
+)
+
+const (
+	// VariablesResolvedReason documents that all the variables required by the components are set.
+	VariablesResolvedReason = "VariablesResolved"
+)
+
+const (
+	// ComponentsInSyncReason documents that the installed components match the cached manifests.
+	ComponentsInSyncReason = "ComponentsInSync"
+
+	// ComponentsDriftDetectedReason documents that installed components differ from the cached manifests.
+	ComponentsDriftDetectedReason = "ComponentsDriftDetected"
+
+	// ComponentsDriftRemediatedReason documents that the drifted components were re-applied from the cached manifests.
+	ComponentsDriftRemediatedReason = "ComponentsDriftRemediated"
+
+	// ComponentsDriftCheckFailedReason documents that the installed components couldn't be compared with the
+	// cached manifests.
+	ComponentsDriftCheckFailedReason = "ComponentsDriftCheckFailed"
+)
+
+const (
+	// WaitingForPreviewApprovalReason documents that the changes preview is waiting to be approved.
+	WaitingForPreviewApprovalReason = "WaitingForPreviewApproval"
+
+	// PreviewApprovedReason documents that the changes preview was approved.
+	PreviewApprovedReason = "PreviewApproved"
+
+	// NoChangesToPreviewReason documents that the rendered manifests don't change any installed component.
+	NoChangesToPreviewReason = "NoChangesToPreview"
 )
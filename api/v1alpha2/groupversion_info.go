@@ -55,5 +55,7 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		}
 	}
 
+	scheme.AddKnownTypes(GroupVersion, &ProviderUpgradePlan{}, &ProviderUpgradePlanList{})
+
 	return nil
 }
@@ -31,9 +31,18 @@ const (
 	CompressedAnnotation = "provider.cluster.x-k8s.io/compressed"
 	TrueValue            = "true"
 
+	// AllowDowngradeAnnotation allows to downgrade the provider to an older minor or major version when set to "true".
+	// The provider components are deleted and the target version is installed.
+	AllowDowngradeAnnotation = "provider.cluster.x-k8s.io/allow-downgrade"
+
+	// ApprovedPreviewAnnotation approves the changes preview of the provider with the hash set as value.
+	// The provider is installed or upgraded only when it matches the hash of the current preview.
+	ApprovedPreviewAnnotation = "provider.cluster.x-k8s.io/approved-preview"
+
 	MetadataConfigMapKey            = "metadata"
 	ComponentsConfigMapKey          = "components"
 	AdditionalManifestsConfigMapKey = "manifests"
+	KustomizationConfigMapKey       = "kustomization.yaml"
 )
 
 // ProviderSpec is the desired state of the Provider.
@@ -91,11 +100,140 @@ type ProviderSpec struct {
 	// +optional
 	Patches []*Patch `json:"patches,omitempty"`
 
+	// Kustomization is reference to configmap that contains a kustomization run over the rendered provider
+	// manifests, after the patches. The kustomization is stored under the `kustomization.yaml` key, and the
+	// files it references, like patches, components or transformer configurations, under their file name.
+	// The provider manifests are the first resources of the kustomization. If namespace is not specified,
+	// the namespace of the provider will be used.
+	// +optional
+	Kustomization *ConfigmapReference `json:"kustomization,omitempty"`
+
+	// TemplateProcessor is the processor substituting the variables of the provider components, with the values
+	// from the ConfigSecret. Defaults to Simple, the clusterctl processor for ${VAR} and ${VAR:=default} variables.
+	// Envsubst supports the bash-like envsubst syntax, and only requires the variables whose value is used.
+	// GoTemplate renders the components as a Go template, with the variables as fields like {{ .VAR }},
+	// the sprig functions, except env, expandenv and getHostByName, and the required function.
+	// +optional
+	TemplateProcessor TemplateProcessor `json:"templateProcessor,omitempty"`
+
 	// AdditionalDeployments is a map of additional deployments that the provider
 	// should manage. The key is the name of the deployment and the value is the
 	// DeploymentSpec.
 	// +optional
 	AdditionalDeployments map[string]AdditionalDeployments `json:"additionalDeployments,omitempty"`
+
+	// VersionPolicy configures automatic updates of the provider version. The policy is resolved against
+	// the releases available in the provider repository, and the provider is installed at the resolved
+	// release, recorded in the status. Version is left unchanged, and only patch releases of its minor
+	// version are rolled out automatically as long as it satisfies the policy.
+	// Not supported with OCI or ConfigMap selector fetch configurations.
+	// +optional
+	VersionPolicy *VersionPolicy `json:"versionPolicy,omitempty"`
+
+	// MaintenanceWindow restricts provider upgrades to recurring time windows. Version changes are accepted
+	// at any time, but held with the UpgradePending condition until the window opens.
+	// Initial installations are not restricted.
+	// +optional
+	MaintenanceWindow *MaintenanceWindow `json:"maintenanceWindow,omitempty"`
+
+	// UpgradeRollback configures the automatic rollback to the previously installed provider version
+	// when an upgrade fails. If not set, failed upgrades are not rolled back.
+	// +optional
+	UpgradeRollback *UpgradeRollbackSpec `json:"upgradeRollback,omitempty"`
+
+	// DriftPolicy defines how changes made outside the operator to the installed provider components are handled,
+	// when drift detection is enabled on the operator. Defaults to Report, which lists the drifted components in the
+	// ComponentsDrifted condition. Remediate also re-applies the drifted components from the cache with server-side
+	// apply, and Ignore disables drift detection for the provider.
+	// +optional
+	DriftPolicy DriftPolicy `json:"driftPolicy,omitempty"`
+
+	// PreviewChanges holds the installation and the upgrades of the provider until their changes are approved.
+	// The rendered manifests and their server-side dry-run diff against the installed components are stored
+	// in the preview Secret, and the changes are rolled out once the provider.cluster.x-k8s.io/approved-preview
+	// annotation is set to the preview hash. Changes which don't modify any component are not held.
+	// +optional
+	PreviewChanges bool `json:"previewChanges,omitempty"`
+}
+
+// TemplateProcessor is the processor substituting the variables of the provider components.
+// +kubebuilder:validation:Enum=Simple;Envsubst;GoTemplate
+type TemplateProcessor string
+
+const (
+	// SimpleTemplateProcessor substitutes ${VAR} and ${VAR:=default} variables, like clusterctl.
+	SimpleTemplateProcessor TemplateProcessor = "Simple"
+
+	// EnvsubstTemplateProcessor substitutes variables with the bash-like envsubst syntax.
+	EnvsubstTemplateProcessor TemplateProcessor = "Envsubst"
+
+	// GoTemplateProcessor renders the components as a Go template.
+	GoTemplateProcessor TemplateProcessor = "GoTemplate"
+)
+
+// DriftPolicy defines how drift of the installed provider components is handled.
+// +kubebuilder:validation:Enum=Report;Remediate;Ignore
+type DriftPolicy string
+
+const (
+	// ReportDriftPolicy reports the drifted components in the ComponentsDrifted condition.
+	ReportDriftPolicy DriftPolicy = "Report"
+
+	// RemediateDriftPolicy reports and re-applies the drifted components.
+	RemediateDriftPolicy DriftPolicy = "Remediate"
+
+	// IgnoreDriftPolicy disables drift detection.
+	IgnoreDriftPolicy DriftPolicy = "Ignore"
+)
+
+// VersionPolicy defines how the provider version is selected from the available releases.
+// +kubebuilder:validation:XValidation:rule="has(self.constraint) != (has(self.latestPatch) && self.latestPatch)",message="Exactly one of 'constraint' or 'latestPatch' must be set"
+type VersionPolicy struct {
+	// Constraint is a semantic version range the provider version must satisfy,
+	// for example "~v2.7", "^v1.9.0" or ">=v1.9 <v1.10". Ranges can be combined with "||".
+	// The syntax is the one of the Masterminds semver library.
+	// The latest release satisfying the constraint is selected.
+	// +optional
+	Constraint string `json:"constraint,omitempty"`
+
+	// LatestPatch selects the latest patch release of the minor version set in Version.
+	// +optional
+	LatestPatch bool `json:"latestPatch,omitempty"`
+
+	// Interval is the interval the policy is re-evaluated at. Defaults to 1 hour.
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+}
+
+// MaintenanceWindow defines a recurring time window provider upgrades are allowed in.
+type MaintenanceWindow struct {
+	// Schedule is a cron expression with five fields (minute, hour, day of month, month, day of week),
+	// defining when the window opens. For example, "0 2 * * 6" opens the window every Saturday at 02:00.
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+
+	// Duration is how long the window stays open.
+	Duration metav1.Duration `json:"duration"`
+
+	// TimeZone is the IANA time zone name the schedule is evaluated in, for example "Europe/Berlin".
+	// Defaults to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// UpgradeRollbackSpec defines when a failed provider upgrade is rolled back.
+type UpgradeRollbackSpec struct {
+	// Enabled enables the automatic rollback. The provider is rolled back to the previously installed
+	// version, using the manifests cached for it, if the upgrade fails or the provider deployments
+	// don't become Available within the timeout.
+	// The upgrade is not retried until the provider spec is changed.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Timeout is the time the provider deployments have to become Available after the upgrade.
+	// Defaults to 10 minutes.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
 }
 
 // Patch defines a generic patch to be applied to provider manifests.
@@ -268,7 +406,10 @@ type ContainerSpec struct {
 }
 
 // FetchConfiguration determines the way to fetch the components and metadata for the provider.
-// +kubebuilder:validation:XValidation:rule="[has(self.oci), has(self.url), has(self.selector)].exists_one(x,x)", message="Must specify one and only one of {oci, url, selector}"
+// +kubebuilder:validation:XValidation:rule="[has(self.oci), has(self.url), has(self.selector), has(self.http), has(self.git)].exists_one(x,x)", message="Must specify one and only one of {oci, url, selector, http, git}"
+// +kubebuilder:validation:XValidation:rule="has(self.oci) || !(has(self.ociDigest) || has(self.ociVerification) || has(self.ociDriftCheckInterval) || has(self.ociAuth))", message="ociDigest, ociVerification, ociDriftCheckInterval and ociAuth can only be set with oci"
+// +kubebuilder:validation:XValidation:rule="has(self.url) || has(self.http) || !has(self.checksums)", message="checksums can only be set with url or http"
+// +kubebuilder:validation:XValidation:rule="!has(self.selector) || !has(self.fallbacks)", message="fallbacks cannot be set with selector"
 type FetchConfiguration struct {
 	// OCI configurations to be used for fetching the provider’s components and metadata from an OCI artifact.
 	OCIConfiguration `json:",inline"`
@@ -287,6 +428,135 @@ type FetchConfiguration struct {
 	// add a label like the following: provider.cluster.x-k8s.io/version=v1.4.3
 	// +optional
 	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+
+	// HTTP to be used for fetching the provider’s components and metadata from a plain HTTP(S) file server
+	// or an S3-compatible bucket.
+	// +optional
+	HTTP *HTTPConfiguration `json:"http,omitempty"`
+
+	// Git to be used for building the provider’s components from a directory of a git repository,
+	// for example to run a fork of a provider without publishing releases.
+	// +optional
+	Git *GitConfiguration `json:"git,omitempty"`
+
+	// Checksums configures the verification of the provider’s components and metadata fetched from URL or HTTP.
+	// The manifests are rejected if their sha256 checksum doesn't match the expected one. Manifests
+	// downloaded before the checksums were changed are downloaded and verified again.
+	// +optional
+	Checksums *ManifestChecksums `json:"checksums,omitempty"`
+
+	// Fallbacks are the sources the provider’s components and metadata are fetched from, in order, when fetching
+	// them from the source above fails, for example because of rate limiting. The source the manifests were
+	// fetched from is recorded in the provider.cluster.x-k8s.io/source annotation of the manifests ConfigMap.
+	// Manifests failing the checksum or the OCI artifact verification are not fetched from the next sources.
+	// +kubebuilder:validation:MaxItems=10
+	// +optional
+	Fallbacks []FetchSource `json:"fallbacks,omitempty"`
+}
+
+// FetchSource defines a source the provider’s components and metadata can be fetched from.
+// +kubebuilder:validation:XValidation:rule="[has(self.oci), has(self.url), has(self.http), has(self.git)].exists_one(x,x)", message="Must specify one and only one of {oci, url, http, git}"
+// +kubebuilder:validation:XValidation:rule="has(self.oci) || !(has(self.ociDigest) || has(self.ociVerification) || has(self.ociDriftCheckInterval) || has(self.ociAuth))", message="ociDigest, ociVerification, ociDriftCheckInterval and ociAuth can only be set with oci"
+// +kubebuilder:validation:XValidation:rule="has(self.url) || has(self.http) || !has(self.checksums)", message="checksums can only be set with url or http"
+type FetchSource struct {
+	// OCI configurations to be used for fetching the provider’s components and metadata from an OCI artifact.
+	OCIConfiguration `json:",inline"`
+
+	// URL to be used for fetching the provider’s components and metadata from a remote Github repository.
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// HTTP to be used for fetching the provider’s components and metadata from a plain HTTP(S) file server
+	// or an S3-compatible bucket.
+	// +optional
+	HTTP *HTTPConfiguration `json:"http,omitempty"`
+
+	// Git to be used for building the provider’s components from a directory of a git repository.
+	// +optional
+	Git *GitConfiguration `json:"git,omitempty"`
+
+	// Checksums configures the verification of the provider’s components and metadata fetched from URL or HTTP.
+	// +optional
+	Checksums *ManifestChecksums `json:"checksums,omitempty"`
+}
+
+// HTTPConfiguration defines a HTTP(S) file server or an S3-compatible bucket hosting the provider manifests.
+// The files of each version are expected under "<url>/<version>/", for example
+// "https://artifacts.example.com/providers/azure/v1.9.3/metadata.yaml".
+type HTTPConfiguration struct {
+	// URL is the base URL the provider versions are hosted under. For an S3-compatible endpoint, the URL is
+	// expected in the path-style format "<endpoint>/<bucket>/<prefix>".
+	// +kubebuilder:validation:Pattern=`^https?://`
+	// +required
+	URL string `json:"url"`
+
+	// S3 indicates that the URL points to an S3-compatible bucket. The requests are signed with the
+	// S3_ACCESS_KEY_ID, S3_SECRET_ACCESS_KEY, S3_SESSION_TOKEN and S3_REGION variables of the config secret,
+	// and the versions are listed from the bucket prefixes.
+	// Otherwise, the requests are authenticated with the HTTP_USERNAME and HTTP_PASSWORD, or HTTP_BEARER_TOKEN
+	// variables, and the versions are listed from the directory index page.
+	// +optional
+	S3 bool `json:"s3,omitempty"`
+
+	// CASecretRef is a Secret with the PEM encoded CA bundle, under the "ca.crt" key, the server
+	// certificate is verified with in addition to the system roots.
+	// Secrets without a namespace are looked up in the provider namespace.
+	// +optional
+	CASecretRef *SecretReference `json:"caSecretRef,omitempty"`
+
+	// ClientCertSecretRef is a "kubernetes.io/tls" Secret with the client certificate and key presented
+	// to the server for mutual TLS.
+	// Secrets without a namespace are looked up in the provider namespace.
+	// +optional
+	ClientCertSecretRef *SecretReference `json:"clientCertSecretRef,omitempty"`
+}
+
+// GitConfiguration defines a git repository the provider components are built from.
+type GitConfiguration struct {
+	// URL is the HTTP(S) URL of the git repository, for example https://github.com/my-org/cluster-api-provider-azure.git.
+	// The repository is fetched with the git smart HTTP protocol, authenticated with the GIT_USERNAME and
+	// GIT_PASSWORD variables of the config secret, if set.
+	// +kubebuilder:validation:Pattern=`^https?://`
+	// +required
+	URL string `json:"url"`
+
+	// Ref is the branch, the tag or the full commit hash to build the components from.
+	// Defaults to the provider version.
+	// +optional
+	Ref string `json:"ref,omitempty"`
+
+	// Path is the directory of the repository with a kustomization, or with plain manifest files,
+	// the components are rendered from. The kustomization is built as with `kustomize build`, and must only
+	// refer to files of the repository.
+	// +kubebuilder:default="config/default"
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// MetadataPath is the path of the metadata file in the repository.
+	// +kubebuilder:default="metadata.yaml"
+	// +optional
+	MetadataPath string `json:"metadataPath,omitempty"`
+}
+
+// ManifestChecksums defines the expected sha256 checksums of the provider manifests.
+// Checksums set in the spec take precedence over the ones listed in the checksum file.
+// +kubebuilder:validation:XValidation:rule="has(self.components) || has(self.metadata) || has(self.file)", message="At least one of {components, metadata, file} must be set"
+type ManifestChecksums struct {
+	// Components is the expected sha256 checksum of the components file, as a hex string.
+	// +kubebuilder:validation:Pattern=`^[a-f0-9]{64}$`
+	// +optional
+	Components string `json:"components,omitempty"`
+
+	// Metadata is the expected sha256 checksum of the metadata.yaml file, as a hex string.
+	// +kubebuilder:validation:Pattern=`^[a-f0-9]{64}$`
+	// +optional
+	Metadata string `json:"metadata,omitempty"`
+
+	// File is the name of a checksum file published with the release, for example "checksums.txt".
+	// The file is expected in the sha256sum format, with one "<checksum>  <file name>" entry per line,
+	// and must list both the components and the metadata files.
+	// +optional
+	File string `json:"file,omitempty"`
 }
 
 type OCIConfiguration struct {
@@ -295,6 +565,61 @@ type OCIConfiguration struct {
 	// If the providerSpec.Version is missing, latest provider version from clusterctl defaults is used.
 	// +optional
 	OCI string `json:"oci,omitempty"`
+
+	// OCIDigest pins the OCI artifact to a manifest digest, for example "sha256:4b9d...". The artifact is
+	// rejected if the OCI reference resolves to a different digest.
+	// +kubebuilder:validation:Pattern=`^sha256:[a-f0-9]{64}$`
+	// +optional
+	OCIDigest string `json:"ociDigest,omitempty"`
+
+	// OCIVerification configures the verification of the OCI artifact signature.
+	// The artifact is rejected if it is not signed with the configured key.
+	// +optional
+	OCIVerification *OCIVerification `json:"ociVerification,omitempty"`
+
+	// OCIDriftCheckInterval is the interval the OCI reference is resolved at, to detect that a tag was re-pushed
+	// after the provider manifests were fetched. The drift is reported by the OCIArtifactUpToDate condition.
+	// If not set, the OCI reference is not re-checked.
+	// +optional
+	OCIDriftCheckInterval *metav1.Duration `json:"ociDriftCheckInterval,omitempty"`
+
+	// OCIAuth configures the credentials and the TLS settings used to connect to the OCI registry.
+	// +optional
+	OCIAuth *OCIAuth `json:"ociAuth,omitempty"`
+}
+
+// OCIAuth defines the credentials and the TLS settings used to connect to an OCI registry.
+// Secrets without a namespace are looked up in the provider namespace.
+type OCIAuth struct {
+	// DockerConfigSecretRef is a "kubernetes.io/dockerconfigjson" Secret with per-registry credentials.
+	// The credentials of the artifact registry take precedence over the OCI_* variables of the config secret.
+	// +optional
+	DockerConfigSecretRef *SecretReference `json:"dockerConfigSecretRef,omitempty"`
+
+	// CASecretRef is a Secret with the PEM encoded CA bundle, under the "ca.crt" key, the registry
+	// certificate is verified with in addition to the system roots.
+	// +optional
+	CASecretRef *SecretReference `json:"caSecretRef,omitempty"`
+
+	// ClientCertSecretRef is a "kubernetes.io/tls" Secret with the client certificate and key presented
+	// to the registry for mutual TLS.
+	// +optional
+	ClientCertSecretRef *SecretReference `json:"clientCertSecretRef,omitempty"`
+}
+
+// OCIVerification defines how the signature of an OCI artifact is verified.
+// Only the legacy cosign signature format is supported: a simple signing payload, whose signature is
+// stored in the "dev.cosignproject.cosign/signature" annotation of a manifest tagged "sha256-<digest>.sig"
+// in the repository of the artifact, as pushed by "cosign sign --key" without "--new-bundle-format".
+// Signatures attached with the OCI 1.1 referrers API and Sigstore bundles are not supported.
+type OCIVerification struct {
+	// PublicKeySecretRef is the Secret containing the PEM encoded public key (ECDSA, RSA or Ed25519)
+	// the artifact is signed with. If the namespace is not set, the provider namespace is used.
+	PublicKeySecretRef SecretReference `json:"publicKeySecretRef"`
+
+	// PublicKeySecretKey is the key of the public key in the Secret. Defaults to "cosign.pub".
+	// +optional
+	PublicKeySecretKey string `json:"publicKeySecretKey,omitempty"`
 }
 
 // ProviderStatus defines the observed state of the Provider.
@@ -315,4 +640,172 @@ type ProviderStatus struct {
 	// InstalledVersion is the version of the provider that is installed.
 	// +optional
 	InstalledVersion *string `json:"installedVersion,omitempty"`
+
+	// LastVersionPolicyCheck is the last time the version policy was resolved against the provider releases.
+	// +optional
+	LastVersionPolicyCheck *metav1.Time `json:"lastVersionPolicyCheck,omitempty"`
+
+	// ResolvedVersion is the release selected by the version policy, which the provider is installed at
+	// instead of the version set in the spec.
+	// +optional
+	ResolvedVersion *string `json:"resolvedVersion,omitempty"`
+
+	// PendingUpgrade is the upgrade waiting for the provider deployments to become Available.
+	// It is only set if the upgrade rollback is enabled.
+	// +optional
+	PendingUpgrade *PendingUpgradeStatus `json:"pendingUpgrade,omitempty"`
+
+	// LastRollback is the last rollback of a failed provider upgrade.
+	// +optional
+	LastRollback *RollbackStatus `json:"lastRollback,omitempty"`
+
+	// History contains the most recent provider installations and upgrades, oldest first.
+	// +optional
+	// +kubebuilder:validation:MaxItems=10
+	History []ProviderHistoryEntry `json:"history,omitempty"`
+
+	// VerifiedChecksums are the sha256 checksums of the provider manifests verified at download.
+	// +optional
+	VerifiedChecksums *VerifiedChecksums `json:"verifiedChecksums,omitempty"`
+
+	// OCIArtifact is the OCI artifact the provider manifests were fetched from.
+	// +optional
+	OCIArtifact *OCIArtifactStatus `json:"ociArtifact,omitempty"`
+
+	// Preview is the last changes preview of the provider, when PreviewChanges is enabled.
+	// +optional
+	Preview *PreviewStatus `json:"preview,omitempty"`
+}
+
+// PreviewStatus is a preview of the changes to the provider components.
+type PreviewStatus struct {
+	// Hash is the hash of the rendered manifests. The preview is approved by setting the
+	// provider.cluster.x-k8s.io/approved-preview annotation to it.
+	Hash string `json:"hash"`
+
+	// Version is the provider version of the rendered manifests.
+	Version string `json:"version"`
+
+	// SecretName is the name of the Secret storing the rendered manifests and the diff,
+	// in the provider namespace.
+	SecretName string `json:"secretName"`
+
+	// CreatedComponents is the number of components created by the changes.
+	// +optional
+	CreatedComponents int32 `json:"createdComponents,omitempty"`
+
+	// ChangedComponents is the number of installed components modified by the changes.
+	// +optional
+	ChangedComponents int32 `json:"changedComponents,omitempty"`
+
+	// DeletedComponents is the number of installed components deleted by the changes.
+	// +optional
+	DeletedComponents int32 `json:"deletedComponents,omitempty"`
+}
+
+// OCIArtifactStatus defines the OCI artifact the provider manifests were fetched from.
+type OCIArtifactStatus struct {
+	// Reference is the OCI reference the artifact was fetched with.
+	Reference string `json:"reference"`
+
+	// Digest is the manifest digest the reference resolved to when the artifact was fetched.
+	Digest string `json:"digest"`
+
+	// LastDriftCheck is the last time the reference was resolved to detect drift.
+	// +optional
+	LastDriftCheck *metav1.Time `json:"lastDriftCheck,omitempty"`
+}
+
+// VerifiedChecksums contains the sha256 checksums of the verified provider manifests.
+type VerifiedChecksums struct {
+	// Version is the provider version the manifests were downloaded for.
+	Version string `json:"version"`
+
+	// Components is the sha256 checksum of the components file.
+	Components string `json:"components"`
+
+	// Metadata is the sha256 checksum of the metadata.yaml file.
+	Metadata string `json:"metadata"`
+}
+
+// ProviderHistoryOutcome is the outcome of a provider installation or upgrade.
+// +kubebuilder:validation:Enum=Installed;Upgraded;Downgraded;UpgradeFailed;RolledBack
+type ProviderHistoryOutcome string
+
+const (
+	// ProviderHistoryInstalled means that the provider components were installed.
+	ProviderHistoryInstalled ProviderHistoryOutcome = "Installed"
+
+	// ProviderHistoryUpgraded means that the provider components were upgraded.
+	ProviderHistoryUpgraded ProviderHistoryOutcome = "Upgraded"
+
+	// ProviderHistoryDowngraded means that the provider components were downgraded.
+	ProviderHistoryDowngraded ProviderHistoryOutcome = "Downgraded"
+
+	// ProviderHistoryUpgradeFailed means that the provider components failed to upgrade.
+	ProviderHistoryUpgradeFailed ProviderHistoryOutcome = "UpgradeFailed"
+
+	// ProviderHistoryRolledBack means that a failed upgrade was rolled back to the previous version.
+	ProviderHistoryRolledBack ProviderHistoryOutcome = "RolledBack"
+)
+
+// ProviderHistoryEntry records a provider installation or upgrade.
+type ProviderHistoryEntry struct {
+	// Version is the provider version the entry is recorded for.
+	Version string `json:"version"`
+
+	// Contract is the Cluster API contract of the provider version.
+	// +optional
+	Contract string `json:"contract,omitempty"`
+
+	// Source is the URL or OCI artifact the provider manifests were fetched from.
+	// OCI artifacts are recorded as reference@digest, e.g. registry.example.com/capi:v1.12.0@sha256:...
+	// +optional
+	Source string `json:"source,omitempty"`
+
+	// AppliedSpecHash is the hash of the provider spec and cached manifests which were applied.
+	// +optional
+	AppliedSpecHash string `json:"appliedSpecHash,omitempty"`
+
+	// Time is the time the entry was recorded.
+	Time metav1.Time `json:"time"`
+
+	// Outcome is the outcome of the installation or upgrade.
+	Outcome ProviderHistoryOutcome `json:"outcome"`
+
+	// Message contains details about the outcome.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// PendingUpgradeStatus defines an upgrade which is not verified yet.
+type PendingUpgradeStatus struct {
+	// PreviousVersion is the version installed before the upgrade.
+	PreviousVersion string `json:"previousVersion"`
+
+	// Version is the version the provider was upgraded to.
+	Version string `json:"version"`
+
+	// StartTime is the time the upgrade was applied.
+	StartTime metav1.Time `json:"startTime"`
+}
+
+// RollbackStatus defines a rollback of a failed provider upgrade.
+type RollbackStatus struct {
+	// FromVersion is the version the provider failed to upgrade to.
+	FromVersion string `json:"fromVersion"`
+
+	// ToVersion is the previously installed version the provider was rolled back to.
+	ToVersion string `json:"toVersion"`
+
+	// Message describes why the upgrade was rolled back.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// ObservedGeneration is the provider generation the rollback was performed for.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Time is the time the rollback was performed.
+	Time metav1.Time `json:"time"`
 }
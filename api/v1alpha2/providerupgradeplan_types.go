@@ -0,0 +1,171 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ProviderUpgradePlanSpec defines the desired state of ProviderUpgradePlan.
+// +kubebuilder:validation:XValidation:rule="has(self.contract) != has(self.providers)",message="Exactly one of 'contract' or 'providers' must be set"
+type ProviderUpgradePlanSpec struct {
+	// Contract is the API Version of Cluster API (contract) all the providers in the management cluster
+	// are upgraded to. Each provider is upgraded to its latest release implementing the contract,
+	// according to the release series of the provider metadata.
+	// +optional
+	Contract string `json:"contract,omitempty"`
+
+	// Providers is the list of providers and the versions they are upgraded to.
+	// +optional
+	// +kubebuilder:validation:MinItems=1
+	Providers []ProviderUpgradeTarget `json:"providers,omitempty"`
+
+	// StepTimeout is the time each provider has to be upgraded and become ready. The plan fails
+	// if a provider is not upgraded within the timeout. If not set, the plan waits indefinitely.
+	// +optional
+	StepTimeout *metav1.Duration `json:"stepTimeout,omitempty"`
+}
+
+// ProviderUpgradeTarget defines the version a provider is upgraded to.
+type ProviderUpgradeTarget struct {
+	// Type is the type of the provider.
+	// +kubebuilder:validation:Enum=core;bootstrap;control-plane;infrastructure;addon;ipam;runtimeextension
+	Type string `json:"type"`
+
+	// Name is the name of the provider, for example "aws".
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the provider. It is only required if several providers
+	// with the same type and name are installed.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Version is the version the provider is upgraded to.
+	// +kubebuilder:validation:MinLength=1
+	Version string `json:"version"`
+}
+
+// ProviderUpgradeStepPhase is the phase of a provider upgrade step.
+// +kubebuilder:validation:Enum=Pending;InProgress;Completed;Failed
+type ProviderUpgradeStepPhase string
+
+const (
+	// ProviderUpgradeStepPending means that the provider upgrade didn't start yet.
+	ProviderUpgradeStepPending ProviderUpgradeStepPhase = "Pending"
+
+	// ProviderUpgradeStepInProgress means that the provider version was updated, and the plan is
+	// waiting for the provider to be upgraded.
+	ProviderUpgradeStepInProgress ProviderUpgradeStepPhase = "InProgress"
+
+	// ProviderUpgradeStepCompleted means that the provider is upgraded and ready.
+	ProviderUpgradeStepCompleted ProviderUpgradeStepPhase = "Completed"
+
+	// ProviderUpgradeStepFailed means that the provider upgrade failed.
+	ProviderUpgradeStepFailed ProviderUpgradeStepPhase = "Failed"
+)
+
+// ProviderUpgradeStep defines the upgrade of a single provider in the plan.
+type ProviderUpgradeStep struct {
+	// Type is the type of the provider.
+	Type string `json:"type"`
+
+	// Name is the name of the provider.
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the provider.
+	Namespace string `json:"namespace"`
+
+	// FromVersion is the provider version before the upgrade.
+	// +optional
+	FromVersion string `json:"fromVersion,omitempty"`
+
+	// Version is the version the provider is upgraded to.
+	Version string `json:"version"`
+
+	// Phase is the phase of the provider upgrade.
+	Phase ProviderUpgradeStepPhase `json:"phase"`
+
+	// StartTime is the time the provider version was updated.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is the time the provider upgrade completed or failed.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Message contains details about the provider upgrade.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ProviderUpgradePlanStatus defines the observed state of ProviderUpgradePlan.
+type ProviderUpgradePlanStatus struct {
+	// Contract is the API Version of Cluster API (contract) the providers are upgraded to.
+	// +optional
+	Contract string `json:"contract,omitempty"`
+
+	// Steps are the provider upgrades of the plan, in the order they are applied.
+	// Providers which are already at the target version are not listed.
+	// +optional
+	Steps []ProviderUpgradeStep `json:"steps,omitempty"`
+
+	// Conditions define the current service state of the plan.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// ObservedGeneration is the latest generation observed by the controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=providerupgradeplans,shortName=capup,scope=Namespaced
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Contract",type="string",JSONPath=".status.contract"
+// +kubebuilder:printcolumn:name="Completed",type="string",JSONPath=".status.conditions[?(@.type=='UpgradeCompleted')].status"
+// +kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".status.conditions[?(@.type=='UpgradeCompleted')].reason"
+// +kubebuilder:storageversion
+
+// ProviderUpgradePlan is the Schema for the providerupgradeplans API. It upgrades several providers
+// of the management cluster together, one provider at a time, starting with the core provider.
+type ProviderUpgradePlan struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ProviderUpgradePlanSpec   `json:"spec,omitempty"`
+	Status ProviderUpgradePlanStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ProviderUpgradePlanList contains a list of ProviderUpgradePlan.
+type ProviderUpgradePlanList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ProviderUpgradePlan `json:"items"`
+}
+
+// GetConditions returns the conditions of the plan.
+func (p *ProviderUpgradePlan) GetConditions() []metav1.Condition {
+	return p.Status.Conditions
+}
+
+// SetConditions sets the conditions of the plan.
+func (p *ProviderUpgradePlan) SetConditions(conditions []metav1.Condition) {
+	p.Status.Conditions = conditions
+}
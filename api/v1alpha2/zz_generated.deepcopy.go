@@ -654,12 +654,34 @@ func (in *DeploymentSpec) DeepCopy() *DeploymentSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FetchConfiguration) DeepCopyInto(out *FetchConfiguration) {
 	*out = *in
-	out.OCIConfiguration = in.OCIConfiguration
+	in.OCIConfiguration.DeepCopyInto(&out.OCIConfiguration)
 	if in.Selector != nil {
 		in, out := &in.Selector, &out.Selector
 		*out = new(v1.LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(HTTPConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Git != nil {
+		in, out := &in.Git, &out.Git
+		*out = new(GitConfiguration)
+		**out = **in
+	}
+	if in.Checksums != nil {
+		in, out := &in.Checksums, &out.Checksums
+		*out = new(ManifestChecksums)
+		**out = **in
+	}
+	if in.Fallbacks != nil {
+		in, out := &in.Fallbacks, &out.Fallbacks
+		*out = make([]FetchSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FetchConfiguration.
@@ -672,6 +694,77 @@ func (in *FetchConfiguration) DeepCopy() *FetchConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FetchSource) DeepCopyInto(out *FetchSource) {
+	*out = *in
+	in.OCIConfiguration.DeepCopyInto(&out.OCIConfiguration)
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(HTTPConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Git != nil {
+		in, out := &in.Git, &out.Git
+		*out = new(GitConfiguration)
+		**out = **in
+	}
+	if in.Checksums != nil {
+		in, out := &in.Checksums, &out.Checksums
+		*out = new(ManifestChecksums)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FetchSource.
+func (in *FetchSource) DeepCopy() *FetchSource {
+	if in == nil {
+		return nil
+	}
+	out := new(FetchSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitConfiguration) DeepCopyInto(out *GitConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitConfiguration.
+func (in *GitConfiguration) DeepCopy() *GitConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(GitConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPConfiguration) DeepCopyInto(out *HTTPConfiguration) {
+	*out = *in
+	if in.CASecretRef != nil {
+		in, out := &in.CASecretRef, &out.CASecretRef
+		*out = new(SecretReference)
+		**out = **in
+	}
+	if in.ClientCertSecretRef != nil {
+		in, out := &in.ClientCertSecretRef, &out.ClientCertSecretRef
+		*out = new(SecretReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPConfiguration.
+func (in *HTTPConfiguration) DeepCopy() *HTTPConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IPAMProvider) DeepCopyInto(out *IPAMProvider) {
 	*out = *in
@@ -854,6 +947,22 @@ func (in *InfrastructureProviderStatus) DeepCopy() *InfrastructureProviderStatus
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ManagerSpec) DeepCopyInto(out *ManagerSpec) {
 	*out = *in
@@ -884,9 +993,88 @@ func (in *ManagerSpec) DeepCopy() *ManagerSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManifestChecksums) DeepCopyInto(out *ManifestChecksums) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManifestChecksums.
+func (in *ManifestChecksums) DeepCopy() *ManifestChecksums {
+	if in == nil {
+		return nil
+	}
+	out := new(ManifestChecksums)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OCIArtifactStatus) DeepCopyInto(out *OCIArtifactStatus) {
+	*out = *in
+	if in.LastDriftCheck != nil {
+		in, out := &in.LastDriftCheck, &out.LastDriftCheck
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OCIArtifactStatus.
+func (in *OCIArtifactStatus) DeepCopy() *OCIArtifactStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OCIArtifactStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OCIAuth) DeepCopyInto(out *OCIAuth) {
+	*out = *in
+	if in.DockerConfigSecretRef != nil {
+		in, out := &in.DockerConfigSecretRef, &out.DockerConfigSecretRef
+		*out = new(SecretReference)
+		**out = **in
+	}
+	if in.CASecretRef != nil {
+		in, out := &in.CASecretRef, &out.CASecretRef
+		*out = new(SecretReference)
+		**out = **in
+	}
+	if in.ClientCertSecretRef != nil {
+		in, out := &in.ClientCertSecretRef, &out.ClientCertSecretRef
+		*out = new(SecretReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OCIAuth.
+func (in *OCIAuth) DeepCopy() *OCIAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(OCIAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OCIConfiguration) DeepCopyInto(out *OCIConfiguration) {
 	*out = *in
+	if in.OCIVerification != nil {
+		in, out := &in.OCIVerification, &out.OCIVerification
+		*out = new(OCIVerification)
+		**out = **in
+	}
+	if in.OCIDriftCheckInterval != nil {
+		in, out := &in.OCIDriftCheckInterval, &out.OCIDriftCheckInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.OCIAuth != nil {
+		in, out := &in.OCIAuth, &out.OCIAuth
+		*out = new(OCIAuth)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OCIConfiguration.
@@ -899,6 +1087,22 @@ func (in *OCIConfiguration) DeepCopy() *OCIConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OCIVerification) DeepCopyInto(out *OCIVerification) {
+	*out = *in
+	out.PublicKeySecretRef = in.PublicKeySecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OCIVerification.
+func (in *OCIVerification) DeepCopy() *OCIVerification {
+	if in == nil {
+		return nil
+	}
+	out := new(OCIVerification)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Patch) DeepCopyInto(out *Patch) {
 	*out = *in
@@ -934,6 +1138,53 @@ func (in *PatchSelector) DeepCopy() *PatchSelector {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PendingUpgradeStatus) DeepCopyInto(out *PendingUpgradeStatus) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PendingUpgradeStatus.
+func (in *PendingUpgradeStatus) DeepCopy() *PendingUpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PendingUpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PreviewStatus) DeepCopyInto(out *PreviewStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreviewStatus.
+func (in *PreviewStatus) DeepCopy() *PreviewStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PreviewStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProviderHistoryEntry) DeepCopyInto(out *ProviderHistoryEntry) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderHistoryEntry.
+func (in *ProviderHistoryEntry) DeepCopy() *ProviderHistoryEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(ProviderHistoryEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProviderSpec) DeepCopyInto(out *ProviderSpec) {
 	*out = *in
@@ -978,6 +1229,11 @@ func (in *ProviderSpec) DeepCopyInto(out *ProviderSpec) {
 			}
 		}
 	}
+	if in.Kustomization != nil {
+		in, out := &in.Kustomization, &out.Kustomization
+		*out = new(ConfigmapReference)
+		**out = **in
+	}
 	if in.AdditionalDeployments != nil {
 		in, out := &in.AdditionalDeployments, &out.AdditionalDeployments
 		*out = make(map[string]AdditionalDeployments, len(*in))
@@ -985,6 +1241,21 @@ func (in *ProviderSpec) DeepCopyInto(out *ProviderSpec) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.VersionPolicy != nil {
+		in, out := &in.VersionPolicy, &out.VersionPolicy
+		*out = new(VersionPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaintenanceWindow != nil {
+		in, out := &in.MaintenanceWindow, &out.MaintenanceWindow
+		*out = new(MaintenanceWindow)
+		**out = **in
+	}
+	if in.UpgradeRollback != nil {
+		in, out := &in.UpgradeRollback, &out.UpgradeRollback
+		*out = new(UpgradeRollbackSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderSpec.
@@ -1017,6 +1288,47 @@ func (in *ProviderStatus) DeepCopyInto(out *ProviderStatus) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.LastVersionPolicyCheck != nil {
+		in, out := &in.LastVersionPolicyCheck, &out.LastVersionPolicyCheck
+		*out = (*in).DeepCopy()
+	}
+	if in.ResolvedVersion != nil {
+		in, out := &in.ResolvedVersion, &out.ResolvedVersion
+		*out = new(string)
+		**out = **in
+	}
+	if in.PendingUpgrade != nil {
+		in, out := &in.PendingUpgrade, &out.PendingUpgrade
+		*out = new(PendingUpgradeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastRollback != nil {
+		in, out := &in.LastRollback, &out.LastRollback
+		*out = new(RollbackStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]ProviderHistoryEntry, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VerifiedChecksums != nil {
+		in, out := &in.VerifiedChecksums, &out.VerifiedChecksums
+		*out = new(VerifiedChecksums)
+		**out = **in
+	}
+	if in.OCIArtifact != nil {
+		in, out := &in.OCIArtifact, &out.OCIArtifact
+		*out = new(OCIArtifactStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Preview != nil {
+		in, out := &in.Preview, &out.Preview
+		*out = new(PreviewStatus)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderStatus.
@@ -1029,6 +1341,173 @@ func (in *ProviderStatus) DeepCopy() *ProviderStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProviderUpgradePlan) DeepCopyInto(out *ProviderUpgradePlan) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderUpgradePlan.
+func (in *ProviderUpgradePlan) DeepCopy() *ProviderUpgradePlan {
+	if in == nil {
+		return nil
+	}
+	out := new(ProviderUpgradePlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProviderUpgradePlan) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProviderUpgradePlanList) DeepCopyInto(out *ProviderUpgradePlanList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ProviderUpgradePlan, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderUpgradePlanList.
+func (in *ProviderUpgradePlanList) DeepCopy() *ProviderUpgradePlanList {
+	if in == nil {
+		return nil
+	}
+	out := new(ProviderUpgradePlanList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProviderUpgradePlanList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProviderUpgradePlanSpec) DeepCopyInto(out *ProviderUpgradePlanSpec) {
+	*out = *in
+	if in.Providers != nil {
+		in, out := &in.Providers, &out.Providers
+		*out = make([]ProviderUpgradeTarget, len(*in))
+		copy(*out, *in)
+	}
+	if in.StepTimeout != nil {
+		in, out := &in.StepTimeout, &out.StepTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderUpgradePlanSpec.
+func (in *ProviderUpgradePlanSpec) DeepCopy() *ProviderUpgradePlanSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProviderUpgradePlanSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProviderUpgradePlanStatus) DeepCopyInto(out *ProviderUpgradePlanStatus) {
+	*out = *in
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]ProviderUpgradeStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderUpgradePlanStatus.
+func (in *ProviderUpgradePlanStatus) DeepCopy() *ProviderUpgradePlanStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ProviderUpgradePlanStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProviderUpgradeStep) DeepCopyInto(out *ProviderUpgradeStep) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderUpgradeStep.
+func (in *ProviderUpgradeStep) DeepCopy() *ProviderUpgradeStep {
+	if in == nil {
+		return nil
+	}
+	out := new(ProviderUpgradeStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProviderUpgradeTarget) DeepCopyInto(out *ProviderUpgradeTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderUpgradeTarget.
+func (in *ProviderUpgradeTarget) DeepCopy() *ProviderUpgradeTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(ProviderUpgradeTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RollbackStatus) DeepCopyInto(out *RollbackStatus) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RollbackStatus.
+func (in *RollbackStatus) DeepCopy() *RollbackStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RollbackStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RuntimeExtensionProvider) DeepCopyInto(out *RuntimeExtensionProvider) {
 	*out = *in
@@ -1134,3 +1613,58 @@ func (in *SecretReference) DeepCopy() *SecretReference {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeRollbackSpec) DeepCopyInto(out *UpgradeRollbackSpec) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeRollbackSpec.
+func (in *UpgradeRollbackSpec) DeepCopy() *UpgradeRollbackSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeRollbackSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VerifiedChecksums) DeepCopyInto(out *VerifiedChecksums) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerifiedChecksums.
+func (in *VerifiedChecksums) DeepCopy() *VerifiedChecksums {
+	if in == nil {
+		return nil
+	}
+	out := new(VerifiedChecksums)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VersionPolicy) DeepCopyInto(out *VersionPolicy) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VersionPolicy.
+func (in *VersionPolicy) DeepCopy() *VersionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(VersionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -71,6 +71,8 @@ var (
 	healthAddr                  string
 	watchConfigSecretChanges    bool
 	watchConfigMapChanges       bool
+	driftDetection              bool
+	driftCheckInterval          time.Duration
 	managerOptions              = flags.ManagerOptions{}
 )
 
@@ -107,6 +109,12 @@ func InitFlags(fs *pflag.FlagSet) {
 	fs.BoolVar(&watchConfigMapChanges, "watch-configmap", false,
 		"Watch for changes to ConfigMaps used by providers with fetchConfig.selector and reconcile all providers using them.")
 
+	fs.BoolVar(&driftDetection, "drift-detection", false,
+		"Compare the installed provider components with the cached manifests, and report or remediate the changes made outside the operator according to the provider driftPolicy.")
+
+	fs.DurationVar(&driftCheckInterval, "drift-check-interval", 10*time.Minute,
+		"The interval at which the installed provider components are compared with the cached manifests, when drift detection is enabled (e.g. 15m)")
+
 	fs.StringVar(&watchNamespace, "namespace", "",
 		"Namespace that the controller watches to reconcile cluster-api objects. If unspecified, the controller watches for cluster-api objects across all namespaces.")
 
@@ -321,10 +329,28 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager, watchConfigSecretCh
 		os.Exit(1)
 	}
 
+	if err := (&providercontroller.ProviderUpgradePlanReconciler{
+		Client: mgr.GetClient(),
+	}).SetupWithManager(mgr, concurrency(concurrencyNumber)); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ProviderUpgradePlan")
+		os.Exit(1)
+	}
+
 	if err := (&healtchcheckcontroller.ProviderHealthCheckReconciler{}).SetupWithManager(mgr, concurrency(concurrencyNumber)); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Healthcheck")
 		os.Exit(1)
 	}
+
+	if driftDetection {
+		if err := (&providercontroller.ProviderDriftReconciler{
+			Client:        mgr.GetClient(),
+			APIReader:     mgr.GetAPIReader(),
+			CheckInterval: driftCheckInterval,
+		}).SetupWithManager(mgr, concurrency(concurrencyNumber)); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "Drift")
+			os.Exit(1)
+		}
+	}
 }
 
 func setupWebhooks(mgr ctrl.Manager) {
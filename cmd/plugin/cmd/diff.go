@@ -0,0 +1,372 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
+	providercontroller "sigs.k8s.io/cluster-api-operator/internal/controller"
+	"sigs.k8s.io/cluster-api-operator/util"
+)
+
+// diffFoundExitCode is the exit code of the diff command with --exit-code,
+// when the rendered manifests change at least one component.
+const diffFoundExitCode = 2
+
+type diffOptions struct {
+	kubeconfig               string
+	kubeconfigContext        string
+	providerFile             string
+	coreProvider             string
+	bootstrapProvider        string
+	controlPlaneProvider     string
+	infrastructureProvider   string
+	ipamProvider             string
+	runtimeExtensionProvider string
+	addonProvider            string
+	source                   string
+	exitCode                 bool
+	registry                 ociRegistryFlags
+}
+
+var diffOpts = &diffOptions{}
+
+var diffCmd = &cobra.Command{
+	Use:     "diff",
+	GroupID: groupDebug,
+	Short:   "Diff the rendered manifests of a provider against the management cluster",
+	Long: LongDesc(`
+		Diff the manifests of a provider, rendered as the operator installs them, against the components
+		in the management cluster.
+
+		The provider is read from a file, or is an installed provider rendered at a new version. The objects
+		the provider refers to, like its configuration secret, are read from the management cluster.
+
+		The changes are computed with a server-side apply dry-run, and printed grouped by kind.`),
+	Example: Examples(`
+		# Diff a modified provider against the management cluster.
+		capioperator diff -f infrastructure-aws.yaml
+
+		# Diff a new version of the installed AWS infrastructure provider against the management cluster.
+		capioperator diff --infrastructure aws:v2.4.0
+
+		# Diff a new version of the installed core provider, published to an OCI artifact.
+		capioperator diff --core cluster-api:v1.10.0 -u ttl.sh/cluster-api`),
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runDiff()
+	},
+}
+
+func init() {
+	diffCmd.Flags().StringVar(&diffOpts.kubeconfig, "kubeconfig", "",
+		"Path to the kubeconfig file to use for accessing the management cluster. If unspecified, default discovery rules apply.")
+	diffCmd.Flags().StringVar(&diffOpts.kubeconfigContext, "kubeconfig-context", "",
+		"Context to be used within the kubeconfig file. If empty, current context will be used.")
+	diffCmd.Flags().StringVarP(&diffOpts.providerFile, "file", "f", "",
+		"Path to the provider resource to diff.")
+	diffCmd.Flags().StringVar(&diffOpts.coreProvider, "core", "",
+		"Installed core provider and version (e.g. cluster-api:v1.1.5) to diff.")
+	diffCmd.Flags().StringVarP(&diffOpts.infrastructureProvider, "infrastructure", "i", "",
+		"Installed infrastructure provider and version (e.g. aws:v2.0.1) to diff.")
+	diffCmd.Flags().StringVarP(&diffOpts.bootstrapProvider, "bootstrap", "b", "",
+		"Installed bootstrap provider and version (e.g. kubeadm:v1.1.5) to diff.")
+	diffCmd.Flags().StringVarP(&diffOpts.controlPlaneProvider, "control-plane", "c", "",
+		"Installed control plane provider and version (e.g. kubeadm:v1.1.5) to diff.")
+	diffCmd.Flags().StringVar(&diffOpts.ipamProvider, "ipam", "",
+		"Installed IPAM provider and version (e.g. infoblox:v0.0.1) to diff.")
+	diffCmd.Flags().StringVar(&diffOpts.runtimeExtensionProvider, "runtime-extension", "",
+		"Installed runtime extension provider and version (e.g. my-extension:v0.0.1) to diff.")
+	diffCmd.Flags().StringVar(&diffOpts.addonProvider, "addon", "",
+		"Installed add-on provider and version (e.g. helm:v0.1.0) to diff.")
+	diffCmd.Flags().StringVarP(&diffOpts.source, "source", "u", "",
+		"The OCI artifact, URL or local directory to fetch the provider manifests from, instead of the provider source.")
+	diffCmd.Flags().BoolVar(&diffOpts.exitCode, "exit-code", false,
+		fmt.Sprintf("Exit with code %d if the rendered manifests change any of the components.", diffFoundExitCode))
+	addOCIRegistryFlags(diffCmd, &diffOpts.registry)
+
+	RootCmd.AddCommand(diffCmd)
+}
+
+func runDiff() error {
+	ctx := context.Background()
+
+	cl, err := CreateKubeClient(diffOpts.kubeconfig, diffOpts.kubeconfigContext)
+	if err != nil {
+		return fmt.Errorf("cannot create a client: %w", err)
+	}
+
+	diffs, err := diffProviderComponents(ctx, cl, diffOpts)
+	if err != nil {
+		return err
+	}
+
+	printComponentDiffs(os.Stdout, diffs)
+
+	if diffOpts.exitCode && len(diffs) > 0 {
+		return &exitCodeError{code: diffFoundExitCode, message: "the rendered manifests change the components"}
+	}
+
+	return nil
+}
+
+// diffProviderComponents renders the manifests of the provider selected with the options, and returns the
+// changes they make to the components in the management cluster.
+func diffProviderComponents(ctx context.Context, cl ctrlclient.Client, opts *diffOptions) ([]providercontroller.ComponentDiff, error) {
+	provider, err := diffProvider(ctx, cl, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	objs, err := providerReferences(ctx, cl, provider, opts.source == "")
+	if err != nil {
+		return nil, err
+	}
+
+	manifests, err := renderProvider(ctx, provider, objs, opts.source, opts.registry)
+	if err != nil {
+		return nil, err
+	}
+
+	diffs, err := providercontroller.ComponentsDiff(ctx, cl, provider, manifests)
+	if err != nil {
+		return nil, fmt.Errorf("cannot diff the manifests of provider %s/%s: %w", provider.GetNamespace(), provider.GetName(), err)
+	}
+
+	return diffs, nil
+}
+
+// diffProvider returns the provider read from the file, or the installed provider set to the new version.
+func diffProvider(ctx context.Context, cl ctrlclient.Client, opts *diffOptions) (genericProvider, error) {
+	inputs := map[clusterctlv1.ProviderType]string{
+		clusterctlv1.CoreProviderType:             opts.coreProvider,
+		clusterctlv1.BootstrapProviderType:        opts.bootstrapProvider,
+		clusterctlv1.ControlPlaneProviderType:     opts.controlPlaneProvider,
+		clusterctlv1.InfrastructureProviderType:   opts.infrastructureProvider,
+		clusterctlv1.IPAMProviderType:             opts.ipamProvider,
+		clusterctlv1.RuntimeExtensionProviderType: opts.runtimeExtensionProvider,
+		clusterctlv1.AddonProviderType:            opts.addonProvider,
+	}
+
+	var (
+		providerType  clusterctlv1.ProviderType
+		providerInput string
+	)
+
+	for t, input := range inputs {
+		if input == "" {
+			continue
+		}
+
+		if providerInput != "" {
+			return nil, fmt.Errorf("only one provider can be specified")
+		}
+
+		providerType, providerInput = t, input
+	}
+
+	switch {
+	case opts.providerFile != "" && providerInput != "":
+		return nil, fmt.Errorf("a provider file can't be used in combination with an installed provider")
+	case opts.providerFile != "":
+		return readProviderFile(opts.providerFile)
+	case providerInput == "":
+		return nil, fmt.Errorf("either a provider file or an installed provider must be specified")
+	}
+
+	genericProviders, _, err := util.GetInstalledProviders(ctx, cl)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get installed providers: %w", err)
+	}
+
+	target, err := findUpgradeTarget(genericProviders, providerType, providerInput)
+	if err != nil {
+		return nil, err
+	}
+
+	provider, ok := target.provider.DeepCopyObject().(genericProvider)
+	if !ok {
+		return nil, fmt.Errorf("unexpected provider %T", target.provider)
+	}
+
+	// Listed providers have no type information, which the owner references of the rendered objects require.
+	gvk, err := apiutil.GVKForObject(provider, scheme)
+	if err != nil {
+		return nil, err
+	}
+
+	provider.GetObjectKind().SetGroupVersionKind(gvk)
+
+	// The provider is rendered at the requested version, rather than at the version selected by its version policy.
+	spec := provider.GetSpec()
+	spec.Version = target.version
+	spec.VersionPolicy = nil
+	provider.SetSpec(spec)
+
+	return provider, nil
+}
+
+// providerReferences returns the Secrets and the ConfigMaps the provider refers to, read from the management
+// cluster. If requested, the ConfigMaps with the manifests downloaded for the provider are returned too.
+func providerReferences(ctx context.Context, cl ctrlclient.Client, provider operatorv1.GenericProvider, withManifests bool) ([]ctrlclient.Object, error) {
+	spec := provider.GetSpec()
+	secrets := []ctrlclient.ObjectKey{}
+	configMaps := []ctrlclient.ObjectKey{}
+
+	if spec.ConfigSecret != nil {
+		secrets = append(secrets, ctrlclient.ObjectKey{Name: spec.ConfigSecret.Name, Namespace: cmp.Or(spec.ConfigSecret.Namespace, provider.GetNamespace())})
+	}
+
+	if spec.AdditionalManifestsRef != nil {
+		configMaps = append(configMaps, ctrlclient.ObjectKey{Name: spec.AdditionalManifestsRef.Name, Namespace: cmp.Or(spec.AdditionalManifestsRef.Namespace, provider.GetNamespace())})
+	}
+
+	if spec.Kustomization != nil {
+		configMaps = append(configMaps, ctrlclient.ObjectKey{Name: spec.Kustomization.Name, Namespace: cmp.Or(spec.Kustomization.Namespace, provider.GetNamespace())})
+	}
+
+	if spec.FetchConfig != nil {
+		secretRefs := []*operatorv1.SecretReference{}
+
+		if spec.FetchConfig.OCIAuth != nil {
+			secretRefs = append(secretRefs, spec.FetchConfig.OCIAuth.DockerConfigSecretRef, spec.FetchConfig.OCIAuth.CASecretRef, spec.FetchConfig.OCIAuth.ClientCertSecretRef)
+		}
+
+		if spec.FetchConfig.HTTP != nil {
+			secretRefs = append(secretRefs, spec.FetchConfig.HTTP.CASecretRef, spec.FetchConfig.HTTP.ClientCertSecretRef)
+		}
+
+		if spec.FetchConfig.OCIVerification != nil {
+			secretRefs = append(secretRefs, &spec.FetchConfig.OCIVerification.PublicKeySecretRef)
+		}
+
+		for _, ref := range secretRefs {
+			if ref == nil {
+				continue
+			}
+
+			key := ctrlclient.ObjectKey{Name: ref.Name, Namespace: cmp.Or(ref.Namespace, provider.GetNamespace())}
+			if !slices.Contains(secrets, key) {
+				secrets = append(secrets, key)
+			}
+		}
+	}
+
+	objs := []ctrlclient.Object{}
+
+	get := func(key ctrlclient.ObjectKey, obj ctrlclient.Object) error {
+		if err := cl.Get(ctx, key, obj); err != nil {
+			return fmt.Errorf("cannot get %s/%s referenced by provider %s/%s: %w", key.Namespace, key.Name, provider.GetNamespace(), provider.GetName(), err)
+		}
+
+		obj.SetResourceVersion("")
+		objs = append(objs, obj)
+
+		return nil
+	}
+
+	for _, key := range secrets {
+		if err := get(key, &corev1.Secret{}); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, key := range configMaps {
+		if err := get(key, &corev1.ConfigMap{}); err != nil {
+			return nil, err
+		}
+	}
+
+	if !withManifests {
+		return objs, nil
+	}
+
+	selector := &metav1.LabelSelector{MatchLabels: providercontroller.ProviderLabels(provider)}
+	if spec.FetchConfig != nil && spec.FetchConfig.Selector != nil {
+		selector = spec.FetchConfig.Selector
+	}
+
+	labelSelector, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	manifests := &corev1.ConfigMapList{}
+	if err := cl.List(ctx, manifests, ctrlclient.InNamespace(provider.GetNamespace()), ctrlclient.MatchingLabelsSelector{Selector: labelSelector}); err != nil {
+		return nil, fmt.Errorf("cannot list the manifests of provider %s/%s: %w", provider.GetNamespace(), provider.GetName(), err)
+	}
+
+	for i := range manifests.Items {
+		manifests.Items[i].SetResourceVersion("")
+		objs = append(objs, &manifests.Items[i])
+	}
+
+	return objs, nil
+}
+
+// printComponentDiffs prints the diffs grouped by kind, sorted by kind and by component name.
+func printComponentDiffs(w io.Writer, diffs []providercontroller.ComponentDiff) {
+	if len(diffs) == 0 {
+		fmt.Fprintln(w, "The rendered manifests don't change the components")
+		return
+	}
+
+	diffs = slices.Clone(diffs)
+	slices.SortStableFunc(diffs, func(a, b providercontroller.ComponentDiff) int {
+		return cmp.Or(
+			cmp.Compare(a.Manifest.GetKind(), b.Manifest.GetKind()),
+			cmp.Compare(providercontroller.ComponentName(a.Manifest), providercontroller.ComponentName(b.Manifest)),
+		)
+	})
+
+	for start := 0; start < len(diffs); {
+		kind := diffs[start].Manifest.GetKind()
+
+		end := start
+		created, deleted := 0, 0
+
+		for ; end < len(diffs) && diffs[end].Manifest.GetKind() == kind; end++ {
+			switch {
+			case diffs[end].Created:
+				created++
+			case diffs[end].Deleted:
+				deleted++
+			}
+		}
+
+		fmt.Fprintf(w, "# %s: %d created, %d changed, %d deleted\n", kind, created, end-start-created-deleted, deleted)
+
+		for _, diff := range diffs[start:end] {
+			fmt.Fprint(w, diff.Diff)
+		}
+
+		start = end
+	}
+}
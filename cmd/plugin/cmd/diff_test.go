@@ -0,0 +1,242 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
+	providercontroller "sigs.k8s.io/cluster-api-operator/internal/controller"
+)
+
+func TestDiffProviderComponents(t *testing.T) {
+	metadata := `apiVersion: clusterctl.cluster.x-k8s.io/v1alpha3
+kind: Metadata
+releaseSeries:
+- major: 1
+  minor: 9
+  contract: v1beta1
+`
+
+	components := `apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: capi-manager
+  namespace: capi-system
+  labels:
+    log-level: "${CAPI_LOG_LEVEL}"
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: Role
+metadata:
+  name: capi-manager
+  namespace: capi-system
+rules:
+- apiGroups: [""]
+  resources: ["secrets"]
+  verbs: ["get"]
+`
+
+	providerFile := `apiVersion: operator.cluster.x-k8s.io/v1alpha2
+kind: CoreProvider
+metadata:
+  name: cluster-api
+  namespace: capi-system
+spec:
+  version: v1.9.3
+  configSecret:
+    name: capi-variables
+    namespace: capi-system
+`
+
+	testCases := []struct {
+		name            string
+		opts            *diffOptions
+		expectedErr     string
+		expectedCreated []string
+		expectedChanged []string
+		expectedDeleted []string
+	}{
+		{
+			name:            "new version of an installed provider",
+			opts:            &diffOptions{coreProvider: "cluster-api:v1.9.3"},
+			expectedCreated: []string{"Role capi-system/capi-manager"},
+			expectedChanged: []string{"ServiceAccount capi-system/capi-manager"},
+			expectedDeleted: []string{"ClusterRole capi-system-capi-aggregated-manager-role"},
+		},
+		{
+			name:            "provider file",
+			opts:            &diffOptions{providerFile: "provider.yaml"},
+			expectedCreated: []string{"Role capi-system/capi-manager"},
+			expectedChanged: []string{"ServiceAccount capi-system/capi-manager"},
+			expectedDeleted: []string{"ClusterRole capi-system-capi-aggregated-manager-role"},
+		},
+		{
+			name:        "provider not installed",
+			opts:        &diffOptions{infrastructureProvider: "aws:v2.3.0"},
+			expectedErr: "is not installed",
+		},
+		{
+			name:        "provider file and installed provider",
+			opts:        &diffOptions{providerFile: "provider.yaml", coreProvider: "cluster-api:v1.9.3"},
+			expectedErr: "can't be used in combination",
+		},
+		{
+			name:        "no provider",
+			opts:        &diffOptions{},
+			expectedErr: "must be specified",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			dir := t.TempDir()
+			source := filepath.Join(dir, "source")
+
+			g.Expect(os.Mkdir(source, 0o700)).To(Succeed())
+			g.Expect(os.WriteFile(filepath.Join(source, "metadata.yaml"), []byte(metadata), 0o600)).To(Succeed())
+			g.Expect(os.WriteFile(filepath.Join(source, "components.yaml"), []byte(components), 0o600)).To(Succeed())
+
+			opts := *tc.opts
+			opts.source = source
+
+			if opts.providerFile != "" {
+				opts.providerFile = filepath.Join(dir, opts.providerFile)
+				g.Expect(os.WriteFile(opts.providerFile, []byte(providerFile), 0o600)).To(Succeed())
+			}
+
+			provider := &operatorv1.CoreProvider{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-api", Namespace: "capi-system"},
+				Spec: operatorv1.CoreProviderSpec{ProviderSpec: operatorv1.ProviderSpec{
+					Version:      "v1.9.2",
+					ConfigSecret: &operatorv1.SecretReference{Name: "capi-variables", Namespace: "capi-system"},
+				}},
+			}
+
+			componentLabels := map[string]string{
+				clusterctlv1.ClusterctlLabel: "",
+				clusterv1.ProviderNameLabel:  "cluster-api",
+			}
+
+			cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				provider,
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "capi-system"}},
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "capi-variables", Namespace: "capi-system"},
+					Data:       map[string][]byte{"CAPI_LOG_LEVEL": []byte("5")},
+				},
+				&corev1.ServiceAccount{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "capi-manager",
+						Namespace: "capi-system",
+						Labels:    map[string]string{"log-level": "1"},
+					},
+				},
+				// Removed by the new version.
+				&rbacv1.ClusterRole{
+					ObjectMeta: metav1.ObjectMeta{Name: "capi-system-capi-aggregated-manager-role", Labels: componentLabels},
+				},
+				// Preserved, like clusterctl upgrades do.
+				&corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{Name: "capi-legacy", Labels: componentLabels},
+				},
+				// Belongs to another provider.
+				&rbacv1.Role{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "capi-kubeadm-bootstrap-manager",
+						Namespace: "capi-system",
+						Labels:    map[string]string{clusterctlv1.ClusterctlLabel: "", clusterv1.ProviderNameLabel: "bootstrap-kubeadm"},
+					},
+				},
+			).Build()
+
+			diffs, err := diffProviderComponents(context.Background(), cl, &opts)
+			if tc.expectedErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tc.expectedErr)))
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+
+			created, changed, deleted := []string{}, []string{}, []string{}
+
+			for _, diff := range diffs {
+				switch {
+				case diff.Created:
+					created = append(created, providercontroller.ComponentName(diff.Manifest))
+				case diff.Deleted:
+					deleted = append(deleted, providercontroller.ComponentName(diff.Manifest))
+				default:
+					changed = append(changed, providercontroller.ComponentName(diff.Manifest))
+					g.Expect(diff.Diff).To(ContainSubstring(`+    log-level: "5"`))
+				}
+			}
+
+			g.Expect(created).To(ConsistOf(tc.expectedCreated))
+			g.Expect(changed).To(ConsistOf(tc.expectedChanged))
+			g.Expect(deleted).To(ConsistOf(tc.expectedDeleted))
+		})
+	}
+}
+
+func TestPrintComponentDiffs(t *testing.T) {
+	component := func(kind, name string) unstructured.Unstructured {
+		u := unstructured.Unstructured{}
+		u.SetKind(kind)
+		u.SetName(name)
+
+		return u
+	}
+
+	g := NewWithT(t)
+
+	out := &bytes.Buffer{}
+	printComponentDiffs(out, []providercontroller.ComponentDiff{
+		{Manifest: component("ServiceAccount", "b"), Diff: "service account b\n"},
+		{Manifest: component("Deployment", "manager"), Diff: "deployment\n"},
+		{Manifest: component("ServiceAccount", "a"), Created: true, Diff: "service account a\n"},
+		{Manifest: component("ServiceAccount", "c"), Deleted: true, Diff: "service account c\n"},
+	})
+
+	g.Expect(out.String()).To(Equal(`# Deployment: 0 created, 1 changed, 0 deleted
+deployment
+# ServiceAccount: 1 created, 1 changed, 1 deleted
+service account a
+service account b
+service account c
+`))
+
+	out.Reset()
+	printComponentDiffs(out, nil)
+
+	g.Expect(out.String()).To(Equal("The rendered manifests don't change the components\n"))
+}
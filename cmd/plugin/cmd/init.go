@@ -342,12 +342,20 @@ func initProviders(ctx context.Context, client ctrlclient.Client, initOpts *init
 }
 
 func checkProviderReadiness(ctx context.Context, client ctrlclient.Client, genericProvider operatorv1.GenericProvider, timeout time.Duration) {
+	if err := waitForProviderReadiness(ctx, client, genericProvider, timeout); err != nil {
+		log.Error(err, "Provider is not ready", "Type", genericProvider.GetType(), "Name", genericProvider.GetName(), "Namespace", genericProvider.GetNamespace())
+	}
+}
+
+// waitForProviderReadiness waits for the Ready condition of the provider, and returns an error if the provider
+// is not ready before the timeout.
+func waitForProviderReadiness(ctx context.Context, client ctrlclient.Client, genericProvider operatorv1.GenericProvider, timeout time.Duration) error {
 	log.Info("Waiting for provider to become ready", "Type", genericProvider.GetType(), "Name", genericProvider.GetName(), "Namespace", genericProvider.GetNamespace())
 
 	pollingInterval := 500 * time.Microsecond
 
 	// Check if the provider is ready.
-	if err := wait.PollUntilContextTimeout(ctx, pollingInterval, timeout, false, func(ctx context.Context) (done bool, err error) {
+	return wait.PollUntilContextTimeout(ctx, pollingInterval, timeout, false, func(ctx context.Context) (done bool, err error) {
 		err = client.Get(ctx, ctrlclient.ObjectKeyFromObject(genericProvider), genericProvider)
 		if err != nil {
 			return false, fmt.Errorf("cannot get provider: %w", err)
@@ -363,9 +371,7 @@ func checkProviderReadiness(ctx context.Context, client ctrlclient.Client, gener
 		}
 
 		return false, nil
-	}); err != nil {
-		log.Error(err, "Provider is not ready", "Type", genericProvider.GetType(), "Name", genericProvider.GetName(), "Namespace", genericProvider.GetNamespace())
-	}
+	})
 }
 
 func ensureCertManager(ctx context.Context, opts *initOptions) error {
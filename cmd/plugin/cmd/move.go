@@ -18,11 +18,44 @@ package cmd
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/go-errors/errors"
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	clusterctlclient "sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/yaml"
+
+	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
+	providercontroller "sigs.k8s.io/cluster-api-operator/internal/controller"
+	"sigs.k8s.io/cluster-api-operator/util"
+)
+
+const (
+	// moveClusterAPIDirectory is the subdirectory holding Cluster API objects written by clusterctl.
+	moveClusterAPIDirectory = "cluster-api"
+	// moveOperatorDirectory is the subdirectory holding operator providers and their dependencies.
+	moveOperatorDirectory = "operator"
 )
 
+// operatorResources holds operator objects which have to be recreated in the target management cluster.
+type operatorResources struct {
+	providers  []operatorv1.GenericProvider
+	secrets    []*corev1.Secret
+	configMaps []*corev1.ConfigMap
+}
+
 type moveOptions struct {
 	fromKubeconfig        string
 	fromKubeconfigContext string
@@ -32,6 +65,7 @@ type moveOptions struct {
 	fromDirectory         string
 	toDirectory           string
 	dryRun                bool
+	waitProviderTimeout   int
 }
 
 var moveOpts = &moveOptions{}
@@ -43,7 +77,13 @@ var moveCmd = &cobra.Command{
 	Long: LongDesc(`
 		Move Cluster API objects and all dependencies between management clusters.
 
-		Note: The destination cluster MUST have the required provider components installed.`),
+		Operator providers, with the Secrets and ConfigMaps they reference and their manifest ConfigMaps,
+		are recreated in the destination cluster first. Once all providers are ready, Cluster API objects are moved with clusterctl.
+
+		When writing to a directory, operator objects are stored in the "operator" subdirectory and
+		Cluster API objects in the "cluster-api" subdirectory.
+
+		Note: The destination cluster MUST have the Cluster API operator installed.`),
 
 	Example: Examples(`
 		Move Cluster API objects and all dependencies between management clusters.
@@ -78,6 +118,8 @@ func init() {
 		"Write Cluster API objects and all dependencies from a management cluster to directory.")
 	moveCmd.Flags().StringVar(&moveOpts.fromDirectory, "from-directory", "",
 		"Read Cluster API objects and all dependencies from a directory into a management cluster.")
+	moveCmd.Flags().IntVar(&moveOpts.waitProviderTimeout, "wait-provider-timeout", 5*60,
+		"Wait timeout per provider installation in seconds in the destination management cluster.")
 
 	moveCmd.MarkFlagsMutuallyExclusive("to-directory", "to-kubeconfig")
 	moveCmd.MarkFlagsMutuallyExclusive("from-directory", "to-directory")
@@ -100,5 +142,465 @@ func runMove() error {
 }
 
 func moveProvider(ctx context.Context, opts *moveOptions) error {
-	return errors.New("Not implemented")
+	clusterctlClient, err := clusterctlclient.New(ctx, "")
+	if err != nil {
+		return fmt.Errorf("cannot create clusterctl client: %w", err)
+	}
+
+	if opts.fromDirectory != "" {
+		return moveFromDirectory(ctx, clusterctlClient, opts)
+	}
+
+	if opts.fromKubeconfig == "" {
+		opts.fromKubeconfig = GetKubeconfigLocation()
+	}
+
+	fromClient, err := CreateKubeClient(opts.fromKubeconfig, opts.fromKubeconfigContext)
+	if err != nil {
+		return fmt.Errorf("cannot create a client for the source management cluster: %w", err)
+	}
+
+	resources, err := collectOperatorResources(ctx, fromClient)
+	if err != nil {
+		return err
+	}
+
+	fromKubeconfig := clusterctlclient.Kubeconfig{
+		Path:    opts.fromKubeconfig,
+		Context: opts.fromKubeconfigContext,
+	}
+
+	if opts.toDirectory != "" {
+		clusterAPIDirectory := filepath.Join(opts.toDirectory, moveClusterAPIDirectory)
+		operatorDirectory := filepath.Join(opts.toDirectory, moveOperatorDirectory)
+
+		for _, directory := range []string{clusterAPIDirectory, operatorDirectory} {
+			if err := os.MkdirAll(directory, 0o750); err != nil {
+				return fmt.Errorf("cannot create directory %s: %w", directory, err)
+			}
+		}
+
+		if err := writeOperatorResources(resources, operatorDirectory, opts.dryRun); err != nil {
+			return err
+		}
+
+		return clusterctlClient.Move(ctx, clusterctlclient.MoveOptions{
+			FromKubeconfig: fromKubeconfig,
+			Namespace:      opts.namespace,
+			ToDirectory:    clusterAPIDirectory,
+			DryRun:         opts.dryRun,
+		})
+	}
+
+	var toClient ctrlclient.Client
+
+	if !opts.dryRun {
+		toClient, err = CreateKubeClient(opts.toKubeconfig, opts.toKubeconfigContext)
+		if err != nil {
+			return fmt.Errorf("cannot create a client for the target management cluster: %w", err)
+		}
+	}
+
+	if err := restoreOperatorResources(ctx, toClient, resources, opts); err != nil {
+		return err
+	}
+
+	return clusterctlClient.Move(ctx, clusterctlclient.MoveOptions{
+		FromKubeconfig: fromKubeconfig,
+		ToKubeconfig: clusterctlclient.Kubeconfig{
+			Path:    opts.toKubeconfig,
+			Context: opts.toKubeconfigContext,
+		},
+		Namespace: opts.namespace,
+		DryRun:    opts.dryRun,
+	})
+}
+
+// moveFromDirectory restores operator objects and Cluster API objects from a directory into the target management cluster.
+func moveFromDirectory(ctx context.Context, clusterctlClient clusterctlclient.Client, opts *moveOptions) error {
+	clusterAPIDirectory := filepath.Join(opts.fromDirectory, moveClusterAPIDirectory)
+	operatorDirectory := filepath.Join(opts.fromDirectory, moveOperatorDirectory)
+
+	// Directories created by "clusterctl move --to-directory" contain Cluster API objects only.
+	if _, err := os.Stat(clusterAPIDirectory); os.IsNotExist(err) {
+		clusterAPIDirectory = opts.fromDirectory
+	}
+
+	resources := &operatorResources{}
+
+	if _, err := os.Stat(operatorDirectory); err == nil {
+		resources, err = readOperatorResources(operatorDirectory)
+		if err != nil {
+			return err
+		}
+	} else if os.IsNotExist(err) {
+		log.Info("No operator objects found in the directory, skipping providers restore", "Directory", opts.fromDirectory)
+	} else {
+		return err
+	}
+
+	var toClient ctrlclient.Client
+
+	if !opts.dryRun {
+		var err error
+
+		toClient, err = CreateKubeClient(opts.toKubeconfig, opts.toKubeconfigContext)
+		if err != nil {
+			return fmt.Errorf("cannot create a client for the target management cluster: %w", err)
+		}
+	}
+
+	if err := restoreOperatorResources(ctx, toClient, resources, opts); err != nil {
+		return err
+	}
+
+	// clusterctl doesn't support dry run when objects are restored from a directory.
+	if opts.dryRun {
+		log.Info("Dry run, skipping restore of Cluster API objects", "Directory", clusterAPIDirectory)
+
+		return nil
+	}
+
+	return clusterctlClient.Move(ctx, clusterctlclient.MoveOptions{
+		ToKubeconfig: clusterctlclient.Kubeconfig{
+			Path:    opts.toKubeconfig,
+			Context: opts.toKubeconfigContext,
+		},
+		FromDirectory: clusterAPIDirectory,
+	})
+}
+
+// collectOperatorResources lists all providers in the source management cluster together with the Secrets
+// and ConfigMaps they refer to, and the ConfigMaps with their downloaded manifests.
+func collectOperatorResources(ctx context.Context, cl ctrlclient.Client) (*operatorResources, error) {
+	resources := &operatorResources{}
+	collected := map[string]bool{}
+
+	for _, list := range operatorv1.ProviderLists {
+		list, ok := list.(genericProviderList)
+		if !ok {
+			log.V(5).Info("Expected to get GenericProviderList")
+			continue
+		}
+
+		list, ok = list.DeepCopyObject().(genericProviderList)
+		if !ok {
+			log.V(5).Info("Expected to get GenericProviderList")
+			continue
+		}
+
+		if err := cl.List(ctx, list); err != nil {
+			return nil, fmt.Errorf("cannot get a list of providers from the server: %w", err)
+		}
+
+		for _, provider := range list.GetItems() {
+			// The references are read before the status, and the version resolved in it, is cleared.
+			objs, err := providerReferences(ctx, cl, provider, true)
+			if err != nil {
+				return nil, err
+			}
+
+			if err := prepareForMove(provider); err != nil {
+				return nil, err
+			}
+
+			resources.providers = append(resources.providers, provider)
+
+			for _, obj := range objs {
+				if err := prepareForMove(obj); err != nil {
+					return nil, err
+				}
+
+				if collected[objectID(obj)] {
+					continue
+				}
+
+				collected[objectID(obj)] = true
+
+				switch obj := obj.(type) {
+				case *corev1.Secret:
+					resources.secrets = append(resources.secrets, obj)
+				case *corev1.ConfigMap:
+					resources.configMaps = append(resources.configMaps, obj)
+				}
+			}
+		}
+	}
+
+	log.Info("Collected operator objects", "Providers", len(resources.providers), "Secrets", len(resources.secrets), "ConfigMaps", len(resources.configMaps))
+
+	return resources, nil
+}
+
+// prepareForMove removes fields set by the API server or by the operator in the source management cluster,
+// so the object can be created in the target management cluster. Owner references are preserved, and
+// remapped to the new owners when the object is restored.
+func prepareForMove(obj ctrlclient.Object) error {
+	gvk, err := apiutil.GVKForObject(obj, scheme)
+	if err != nil {
+		return fmt.Errorf("cannot get kind of the object %s/%s: %w", obj.GetNamespace(), obj.GetName(), err)
+	}
+
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+	obj.SetResourceVersion("")
+	obj.SetUID("")
+	obj.SetGeneration(0)
+	obj.SetCreationTimestamp(metav1.Time{})
+	obj.SetManagedFields(nil)
+	obj.SetFinalizers(nil)
+
+	// Remove the applied spec hash, so the operator in the target cluster reconciles the provider from scratch.
+	if annotations := obj.GetAnnotations(); annotations != nil {
+		delete(annotations, providercontroller.AppliedSpecHashAnnotation)
+		obj.SetAnnotations(annotations)
+	}
+
+	if provider, ok := obj.(operatorv1.GenericProvider); ok {
+		provider.SetStatus(operatorv1.ProviderStatus{})
+	}
+
+	return nil
+}
+
+// objects returns all operator objects in the order they have to be created.
+func (r *operatorResources) objects() []ctrlclient.Object {
+	objects := []ctrlclient.Object{}
+
+	for _, secret := range r.secrets {
+		objects = append(objects, secret)
+	}
+
+	for _, cm := range r.configMaps {
+		objects = append(objects, cm)
+	}
+
+	for _, provider := range r.providers {
+		objects = append(objects, provider)
+	}
+
+	return objects
+}
+
+// writeOperatorResources stores operator objects in the directory, one object per file.
+func writeOperatorResources(resources *operatorResources, directory string, dryRun bool) error {
+	for _, obj := range resources.objects() {
+		kind := obj.GetObjectKind().GroupVersionKind().Kind
+		path := filepath.Join(directory, fmt.Sprintf("%s_%s_%s.yaml", kind, obj.GetNamespace(), obj.GetName()))
+
+		if dryRun {
+			log.Info("Dry run, skipping saving object", "Kind", kind, "Namespace", obj.GetNamespace(), "Name", obj.GetName(), "Path", path)
+			continue
+		}
+
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return fmt.Errorf("cannot serialize %s %s/%s: %w", kind, obj.GetNamespace(), obj.GetName(), err)
+		}
+
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			return fmt.Errorf("cannot write %s: %w", path, err)
+		}
+	}
+
+	return nil
+}
+
+// readOperatorResources loads operator objects previously stored with writeOperatorResources.
+func readOperatorResources(directory string) (*operatorResources, error) {
+	files, err := os.ReadDir(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := serializer.NewCodecFactory(scheme).UniversalDeserializer()
+	resources := &operatorResources{}
+
+	for _, file := range files {
+		if !file.Type().IsRegular() {
+			continue
+		}
+
+		path := filepath.Join(directory, file.Name())
+
+		data, err := os.ReadFile(filepath.Clean(path))
+		if err != nil {
+			return nil, fmt.Errorf("cannot read %s: %w", path, err)
+		}
+
+		obj, _, err := decoder.Decode(data, nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode %s: %w", path, err)
+		}
+
+		switch obj := obj.(type) {
+		case genericProvider:
+			resources.providers = append(resources.providers, obj)
+		case *corev1.Secret:
+			resources.secrets = append(resources.secrets, obj)
+		case *corev1.ConfigMap:
+			resources.configMaps = append(resources.configMaps, obj)
+		default:
+			return nil, fmt.Errorf("unexpected object %s in %s", obj.GetObjectKind().GroupVersionKind(), path)
+		}
+	}
+
+	return resources, nil
+}
+
+// restoreOperatorResources creates operator objects in the target management cluster and waits for the providers
+// to become ready. Objects which already exist in the target cluster are left untouched.
+func restoreOperatorResources(ctx context.Context, cl ctrlclient.Client, resources *operatorResources, opts *moveOptions) error {
+	if opts.dryRun {
+		for _, obj := range resources.objects() {
+			log.Info("Dry run, skipping creating object", "Kind", obj.GetObjectKind().GroupVersionKind().Kind, "Namespace", obj.GetNamespace(), "Name", obj.GetName())
+		}
+
+		return nil
+	}
+
+	// The core provider is created first, as all other providers wait for it to be ready.
+	sort.SliceStable(resources.providers, func(i, j int) bool {
+		return util.IsCoreProvider(resources.providers[i]) && !util.IsCoreProvider(resources.providers[j])
+	})
+
+	// Secrets and ConfigMaps are created before the providers, so they are available on the first provider reconcile.
+	// Their owner references hold the UIDs of the source cluster, so they are restored once the providers exist.
+	for _, obj := range secretsAndConfigMaps(resources) {
+		obj := obj.DeepCopyObject().(ctrlclient.Object)
+		obj.SetOwnerReferences(nil)
+
+		if err := createMovedObject(ctx, cl, obj); err != nil {
+			return err
+		}
+	}
+
+	for _, provider := range resources.providers {
+		if err := createMovedObject(ctx, cl, provider); err != nil {
+			return err
+		}
+	}
+
+	// Restore the ownership, so downloaded manifests and provider Secrets are garbage collected together with the provider.
+	for _, obj := range secretsAndConfigMaps(resources) {
+		if err := restoreOwnerReferences(ctx, cl, obj, resources.providers); err != nil {
+			return err
+		}
+	}
+
+	var wg sync.WaitGroup
+
+	errs := make([]error, len(resources.providers))
+
+	for i, provider := range resources.providers {
+		wg.Add(1)
+
+		go func(i int, provider operatorv1.GenericProvider) {
+			defer wg.Done()
+
+			if err := waitForProviderReadiness(ctx, cl, provider, time.Duration(opts.waitProviderTimeout)*time.Second); err != nil {
+				errs[i] = fmt.Errorf("provider %s/%s is not ready in the target cluster: %w", provider.GetNamespace(), provider.GetName(), err)
+			}
+		}(i, provider)
+	}
+
+	wg.Wait()
+
+	return kerrors.NewAggregate(errs)
+}
+
+// secretsAndConfigMaps returns the Secrets and ConfigMaps of the operator resources.
+func secretsAndConfigMaps(resources *operatorResources) []ctrlclient.Object {
+	objs := []ctrlclient.Object{}
+
+	for _, secret := range resources.secrets {
+		objs = append(objs, secret)
+	}
+
+	for _, cm := range resources.configMaps {
+		objs = append(objs, cm)
+	}
+
+	return objs
+}
+
+// restoreOwnerReferences sets the owner references of the object in the target management cluster,
+// remapped to the providers created there.
+func restoreOwnerReferences(ctx context.Context, cl ctrlclient.Client, obj ctrlclient.Object, providers []operatorv1.GenericProvider) error {
+	ownerReferences := remapOwnerReferences(obj.GetOwnerReferences(), obj.GetNamespace(), providers)
+	if len(ownerReferences) == 0 {
+		return nil
+	}
+
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+
+	target := obj.DeepCopyObject().(ctrlclient.Object)
+	if err := cl.Get(ctx, ctrlclient.ObjectKeyFromObject(obj), target); err != nil {
+		return fmt.Errorf("cannot get %s %s/%s: %w", kind, obj.GetNamespace(), obj.GetName(), err)
+	}
+
+	patch := ctrlclient.MergeFrom(target.DeepCopyObject().(ctrlclient.Object))
+	target.SetOwnerReferences(ownerReferences)
+
+	if err := cl.Patch(ctx, target, patch); err != nil {
+		return fmt.Errorf("cannot update owner references of %s %s/%s: %w", kind, obj.GetNamespace(), obj.GetName(), err)
+	}
+
+	return nil
+}
+
+// createMovedObject creates the object in the target management cluster. If the object already exists,
+// it is fetched instead, so the caller gets the UID of the existing object.
+func createMovedObject(ctx context.Context, cl ctrlclient.Client, obj ctrlclient.Object) error {
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+
+	if err := EnsureNamespaceExists(ctx, cl, obj.GetNamespace()); err != nil {
+		return fmt.Errorf("cannot ensure that namespace exists: %w", err)
+	}
+
+	log.Info("Creating object in the target cluster", "Kind", kind, "Namespace", obj.GetNamespace(), "Name", obj.GetName())
+
+	err := cl.Create(ctx, obj)
+	if err == nil {
+		return nil
+	}
+
+	if !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("cannot create %s %s/%s: %w", kind, obj.GetNamespace(), obj.GetName(), err)
+	}
+
+	log.Info("Object already exists in the target cluster, skipping creation", "Kind", kind, "Namespace", obj.GetNamespace(), "Name", obj.GetName())
+
+	if err := cl.Get(ctx, ctrlclient.ObjectKeyFromObject(obj), obj); err != nil {
+		return fmt.Errorf("cannot get %s %s/%s: %w", kind, obj.GetNamespace(), obj.GetName(), err)
+	}
+
+	return nil
+}
+
+// remapOwnerReferences replaces owner references pointing to moved providers with references to the providers
+// created in the target management cluster. References to unknown owners are dropped.
+func remapOwnerReferences(ownerReferences []metav1.OwnerReference, namespace string, providers []operatorv1.GenericProvider) []metav1.OwnerReference {
+	remapped := []metav1.OwnerReference{}
+
+	for _, ref := range ownerReferences {
+		for _, provider := range providers {
+			gvk, err := apiutil.GVKForObject(provider, scheme)
+			if err != nil || gvk.Kind != ref.Kind || provider.GetName() != ref.Name || provider.GetNamespace() != namespace {
+				continue
+			}
+
+			ref.APIVersion = gvk.GroupVersion().String()
+			ref.UID = provider.GetUID()
+			remapped = append(remapped, ref)
+
+			break
+		}
+	}
+
+	return remapped
+}
+
+// objectID returns a unique identifier for the object.
+func objectID(obj ctrlclient.Object) string {
+	return fmt.Sprintf("%s/%s/%s", obj.GetObjectKind().GroupVersionKind().Kind, obj.GetNamespace(), obj.GetName())
 }
@@ -0,0 +1,300 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
+	providercontroller "sigs.k8s.io/cluster-api-operator/internal/controller"
+)
+
+func TestPrepareForMove(t *testing.T) {
+	g := NewWithT(t)
+
+	provider := &operatorv1.CoreProvider{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "cluster-api",
+			Namespace:         "capi-system",
+			UID:               types.UID("uid"),
+			ResourceVersion:   "10",
+			Generation:        2,
+			CreationTimestamp: metav1.Now(),
+			Finalizers:        []string{"provider.cluster.x-k8s.io"},
+			Annotations: map[string]string{
+				providercontroller.AppliedSpecHashAnnotation: "hash",
+				"foo": "bar",
+			},
+		},
+		Spec: operatorv1.CoreProviderSpec{
+			ProviderSpec: operatorv1.ProviderSpec{Version: "v1.12.0"},
+		},
+		Status: operatorv1.CoreProviderStatus{
+			ProviderStatus: operatorv1.ProviderStatus{InstalledVersion: ptr.To("v1.12.0")},
+		},
+	}
+
+	g.Expect(prepareForMove(provider)).To(Succeed())
+
+	g.Expect(provider.Kind).To(Equal("CoreProvider"))
+	g.Expect(provider.APIVersion).To(Equal(operatorv1.GroupVersion.String()))
+	g.Expect(provider.UID).To(BeEmpty())
+	g.Expect(provider.ResourceVersion).To(BeEmpty())
+	g.Expect(provider.Generation).To(BeZero())
+	g.Expect(provider.CreationTimestamp.IsZero()).To(BeTrue())
+	g.Expect(provider.Finalizers).To(BeEmpty())
+	g.Expect(provider.Annotations).To(Equal(map[string]string{"foo": "bar"}))
+	g.Expect(provider.Spec.Version).To(Equal("v1.12.0"))
+	g.Expect(provider.GetStatus()).To(Equal(operatorv1.ProviderStatus{}))
+}
+
+func TestCollectOperatorResources(t *testing.T) {
+	g := NewWithT(t)
+
+	provider := &operatorv1.InfrastructureProvider{
+		ObjectMeta: metav1.ObjectMeta{Name: "docker", Namespace: "capd-system", UID: types.UID("uid")},
+		Spec: operatorv1.InfrastructureProviderSpec{
+			ProviderSpec: operatorv1.ProviderSpec{
+				Version:       "v1.12.0",
+				ConfigSecret:  &operatorv1.SecretReference{Name: "credentials"},
+				Kustomization: &operatorv1.ConfigmapReference{Name: "kustomization"},
+				FetchConfig: &operatorv1.FetchConfiguration{OCIConfiguration: operatorv1.OCIConfiguration{
+					OCI: "registry.example.com/capd",
+					OCIAuth: &operatorv1.OCIAuth{
+						DockerConfigSecretRef: &operatorv1.SecretReference{Name: "registry"},
+						CASecretRef:           &operatorv1.SecretReference{Name: "credentials"},
+					},
+					OCIVerification: &operatorv1.OCIVerification{
+						PublicKeySecretRef: operatorv1.SecretReference{Name: "cosign", Namespace: "keys"},
+					},
+				}},
+			},
+		},
+	}
+
+	secret := func(name, namespace string) *corev1.Secret {
+		return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	}
+
+	manifests := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+		Name:      "infrastructure-docker-v1.12.0",
+		Namespace: "capd-system",
+		Labels:    providercontroller.ProviderLabels(provider),
+	}}
+
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		provider,
+		secret("credentials", "capd-system"),
+		secret("registry", "capd-system"),
+		secret("cosign", "keys"),
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "kustomization", Namespace: "capd-system"}},
+		manifests,
+	).Build()
+
+	resources, err := collectOperatorResources(context.Background(), cl)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	ids := []string{}
+	for _, obj := range resources.objects() {
+		ids = append(ids, objectID(obj))
+	}
+
+	g.Expect(ids).To(ConsistOf(
+		"InfrastructureProvider/capd-system/docker",
+		"Secret/capd-system/credentials",
+		"Secret/capd-system/registry",
+		"Secret/keys/cosign",
+		"ConfigMap/capd-system/kustomization",
+		"ConfigMap/capd-system/infrastructure-docker-v1.12.0",
+	))
+}
+
+func TestOperatorResourcesDirectoryRoundTrip(t *testing.T) {
+	g := NewWithT(t)
+
+	directory := t.TempDir()
+
+	resources := &operatorResources{
+		providers: []operatorv1.GenericProvider{
+			&operatorv1.CoreProvider{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-api", Namespace: "capi-system"},
+				Spec: operatorv1.CoreProviderSpec{
+					ProviderSpec: operatorv1.ProviderSpec{Version: "v1.12.0"},
+				},
+			},
+			&operatorv1.InfrastructureProvider{
+				ObjectMeta: metav1.ObjectMeta{Name: "docker", Namespace: "capd-system"},
+				Spec: operatorv1.InfrastructureProviderSpec{
+					ProviderSpec: operatorv1.ProviderSpec{
+						Version:      "v1.12.0",
+						ConfigSecret: &operatorv1.SecretReference{Name: "credentials"},
+					},
+				},
+			},
+		},
+		secrets: []*corev1.Secret{{
+			ObjectMeta: metav1.ObjectMeta{Name: "credentials", Namespace: "capd-system"},
+			Data:       map[string][]byte{"key": []byte("value")},
+		}},
+		configMaps: []*corev1.ConfigMap{{
+			ObjectMeta: metav1.ObjectMeta{Name: "docker-v1.12.0", Namespace: "capd-system"},
+			Data:       map[string]string{"components": "---"},
+		}},
+	}
+
+	for _, obj := range resources.objects() {
+		g.Expect(prepareForMove(obj)).To(Succeed())
+	}
+
+	g.Expect(writeOperatorResources(resources, directory, true)).To(Succeed())
+
+	files, err := os.ReadDir(directory)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(files).To(BeEmpty())
+
+	g.Expect(writeOperatorResources(resources, directory, false)).To(Succeed())
+	g.Expect(filepath.Join(directory, "CoreProvider_capi-system_cluster-api.yaml")).To(BeAnExistingFile())
+	g.Expect(filepath.Join(directory, "Secret_capd-system_credentials.yaml")).To(BeAnExistingFile())
+	g.Expect(filepath.Join(directory, "ConfigMap_capd-system_docker-v1.12.0.yaml")).To(BeAnExistingFile())
+
+	restored, err := readOperatorResources(directory)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(restored.providers).To(ConsistOf(resources.providers))
+	g.Expect(restored.secrets).To(ConsistOf(resources.secrets))
+	g.Expect(restored.configMaps).To(ConsistOf(resources.configMaps))
+}
+
+func TestRemapOwnerReferences(t *testing.T) {
+	g := NewWithT(t)
+
+	provider := &operatorv1.InfrastructureProvider{
+		ObjectMeta: metav1.ObjectMeta{Name: "docker", Namespace: "capd-system", UID: types.UID("new-uid")},
+	}
+
+	ownerReferences := []metav1.OwnerReference{
+		{
+			APIVersion: operatorv1.GroupVersion.String(),
+			Kind:       "InfrastructureProvider",
+			Name:       "docker",
+			UID:        types.UID("old-uid"),
+		},
+		{
+			APIVersion: operatorv1.GroupVersion.String(),
+			Kind:       "InfrastructureProvider",
+			Name:       "unknown",
+			UID:        types.UID("other-uid"),
+		},
+	}
+
+	g.Expect(remapOwnerReferences(ownerReferences, "capd-system", []operatorv1.GenericProvider{provider})).To(Equal([]metav1.OwnerReference{{
+		APIVersion: operatorv1.GroupVersion.String(),
+		Kind:       "InfrastructureProvider",
+		Name:       "docker",
+		UID:        types.UID("new-uid"),
+	}}))
+
+	g.Expect(remapOwnerReferences(ownerReferences, "other-namespace", []operatorv1.GenericProvider{provider})).To(BeEmpty())
+}
+
+func TestRestoreOperatorResourcesWaitsForProviders(t *testing.T) {
+	g := NewWithT(t)
+
+	ready := &operatorv1.CoreProvider{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-api", Namespace: "capi-system"},
+		Status: operatorv1.CoreProviderStatus{ProviderStatus: operatorv1.ProviderStatus{
+			Conditions: []metav1.Condition{{Type: clusterv1.ReadyCondition, Status: metav1.ConditionTrue}},
+		}},
+	}
+
+	notReady := &operatorv1.InfrastructureProvider{
+		ObjectMeta: metav1.ObjectMeta{Name: "docker", Namespace: "capd-system"},
+	}
+
+	resources := &operatorResources{providers: []operatorv1.GenericProvider{notReady, ready}}
+	for _, provider := range resources.providers {
+		g.Expect(prepareForMove(provider)).To(Succeed())
+	}
+
+	// Conditions are cleared when preparing the move, and set again by the operator in the target cluster.
+	ready.SetConditions([]metav1.Condition{{Type: clusterv1.ReadyCondition, Status: metav1.ConditionTrue}})
+
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	err := restoreOperatorResources(context.Background(), cl, resources, &moveOptions{waitProviderTimeout: 1})
+	g.Expect(err).To(MatchError(ContainSubstring("provider capd-system/docker is not ready")))
+	g.Expect(err).NotTo(MatchError(ContainSubstring("cluster-api")))
+}
+
+func TestRestoreOperatorResourcesRemapsOwnerReferences(t *testing.T) {
+	g := NewWithT(t)
+
+	ownerReferences := []metav1.OwnerReference{{
+		APIVersion: operatorv1.GroupVersion.String(),
+		Kind:       "CoreProvider",
+		Name:       "cluster-api",
+		UID:        types.UID("source-uid"),
+	}}
+
+	provider := &operatorv1.CoreProvider{ObjectMeta: metav1.ObjectMeta{Name: "cluster-api", Namespace: "capi-system"}}
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "credentials", Namespace: "capi-system", OwnerReferences: ownerReferences}}
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "core-cluster-api-v1.12.0", Namespace: "capi-system", OwnerReferences: ownerReferences}}
+
+	resources := &operatorResources{
+		providers:  []operatorv1.GenericProvider{provider},
+		secrets:    []*corev1.Secret{secret},
+		configMaps: []*corev1.ConfigMap{cm},
+	}
+	for _, obj := range resources.objects() {
+		g.Expect(prepareForMove(obj)).To(Succeed())
+	}
+
+	// The provider already exists in the target cluster, with its own UID.
+	existing := &operatorv1.CoreProvider{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-api", Namespace: "capi-system", UID: types.UID("target-uid")},
+		Status: operatorv1.CoreProviderStatus{ProviderStatus: operatorv1.ProviderStatus{
+			Conditions: []metav1.Condition{{Type: clusterv1.ReadyCondition, Status: metav1.ConditionTrue}},
+		}},
+	}
+
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing).Build()
+
+	g.Expect(restoreOperatorResources(context.Background(), cl, resources, &moveOptions{waitProviderTimeout: 1})).To(Succeed())
+
+	restored := map[string]ctrlclient.Object{
+		"credentials":              &corev1.Secret{},
+		"core-cluster-api-v1.12.0": &corev1.ConfigMap{},
+	}
+
+	for name, obj := range restored {
+		g.Expect(cl.Get(context.Background(), ctrlclient.ObjectKey{Namespace: "capi-system", Name: name}, obj)).To(Succeed())
+		g.Expect(obj.GetOwnerReferences()).To(HaveLen(1))
+		g.Expect(obj.GetOwnerReferences()[0].UID).To(Equal(types.UID("target-uid")))
+	}
+}
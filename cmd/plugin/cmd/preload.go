@@ -27,6 +27,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"oras.land/oras-go/v2/registry/remote/auth"
+	"oras.land/oras-go/v2/registry/remote/credentials"
 	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
 	providercontroller "sigs.k8s.io/cluster-api-operator/internal/controller"
 	"sigs.k8s.io/cluster-api-operator/util"
@@ -48,6 +49,7 @@ type loadOptions struct {
 	artifactURL               string
 	kubeconfig                string
 	existing                  bool
+	registry                  ociRegistryFlags
 }
 
 var loadOpts = &loadOptions{}
@@ -134,6 +136,7 @@ func init() {
 		"The target namespace where the operator should be deployed. If unspecified, the 'capi-operator-system' namespace is used.")
 	loadCmd.Flags().StringVarP(&loadOpts.artifactURL, "artifact-url", "u", "",
 		"The URL to OCI artifact or GitHub/GitLab release, to collect component manifests from.")
+	addOCIRegistryFlags(loadCmd, &loadOpts.registry)
 
 	RootCmd.AddCommand(loadCmd)
 }
@@ -149,7 +152,7 @@ func runPreLoad() error {
 
 	// Load Core Provider.
 	if loadOpts.coreProvider != "" {
-		configMap, err := templateConfigMap(ctx, clusterctlv1.CoreProviderType, loadOpts.artifactURL, loadOpts.coreProvider, loadOpts.targetNamespace)
+		configMap, err := templateConfigMap(ctx, clusterctlv1.CoreProviderType, loadOpts.artifactURL, loadOpts.coreProvider, loadOpts.targetNamespace, loadOpts.registry)
 		if err != nil {
 			return fmt.Errorf("cannot prepare manifests config map for core provider: %w", err)
 		} else {
@@ -159,7 +162,7 @@ func runPreLoad() error {
 
 	// Load Bootstrap Providers.
 	for _, bootstrapProvider := range loadOpts.bootstrapProviders {
-		configMap, err := templateConfigMap(ctx, clusterctlv1.BootstrapProviderType, loadOpts.artifactURL, bootstrapProvider, loadOpts.targetNamespace)
+		configMap, err := templateConfigMap(ctx, clusterctlv1.BootstrapProviderType, loadOpts.artifactURL, bootstrapProvider, loadOpts.targetNamespace, loadOpts.registry)
 		if err != nil {
 			return fmt.Errorf("cannot prepare manifests config map for bootstrap provider: %w", err)
 		}
@@ -169,7 +172,7 @@ func runPreLoad() error {
 
 	// Load Infrastructure Providers.
 	for _, infrastructureProvider := range loadOpts.infrastructureProviders {
-		configMap, err := templateConfigMap(ctx, clusterctlv1.InfrastructureProviderType, loadOpts.artifactURL, infrastructureProvider, loadOpts.targetNamespace)
+		configMap, err := templateConfigMap(ctx, clusterctlv1.InfrastructureProviderType, loadOpts.artifactURL, infrastructureProvider, loadOpts.targetNamespace, loadOpts.registry)
 		if err != nil {
 			return fmt.Errorf("cannot prepare manifests config map for infrastructure provider: %w", err)
 		}
@@ -179,7 +182,7 @@ func runPreLoad() error {
 
 	// Load Control Plane Providers.
 	for _, controlPlaneProvider := range loadOpts.controlPlaneProviders {
-		configMap, err := templateConfigMap(ctx, clusterctlv1.ControlPlaneProviderType, loadOpts.artifactURL, controlPlaneProvider, loadOpts.targetNamespace)
+		configMap, err := templateConfigMap(ctx, clusterctlv1.ControlPlaneProviderType, loadOpts.artifactURL, controlPlaneProvider, loadOpts.targetNamespace, loadOpts.registry)
 		if err != nil {
 			return fmt.Errorf("cannot prepare manifests config map for controlplane provider: %w", err)
 		}
@@ -189,7 +192,7 @@ func runPreLoad() error {
 
 	// Load Add-on Providers.
 	for _, addonProvider := range loadOpts.addonProviders {
-		configMap, err := templateConfigMap(ctx, clusterctlv1.AddonProviderType, loadOpts.artifactURL, addonProvider, loadOpts.targetNamespace)
+		configMap, err := templateConfigMap(ctx, clusterctlv1.AddonProviderType, loadOpts.artifactURL, addonProvider, loadOpts.targetNamespace, loadOpts.registry)
 		if err != nil {
 			return fmt.Errorf("cannot prepare manifests config map for addon provider: %w", err)
 		}
@@ -199,7 +202,7 @@ func runPreLoad() error {
 
 	// Load IPAM Providers.
 	for _, ipamProvider := range loadOpts.ipamProviders {
-		configMap, err := templateConfigMap(ctx, clusterctlv1.IPAMProviderType, loadOpts.artifactURL, ipamProvider, loadOpts.targetNamespace)
+		configMap, err := templateConfigMap(ctx, clusterctlv1.IPAMProviderType, loadOpts.artifactURL, ipamProvider, loadOpts.targetNamespace, loadOpts.registry)
 		if err != nil {
 			return fmt.Errorf("cannot prepare manifests config map for IPAM provider: %w", err)
 		}
@@ -209,7 +212,7 @@ func runPreLoad() error {
 
 	// Load Runtime Extension Providers.
 	for _, runtimeExtension := range loadOpts.runtimeExtensionProviders {
-		configMap, err := templateConfigMap(ctx, clusterctlv1.RuntimeExtensionProviderType, loadOpts.artifactURL, runtimeExtension, loadOpts.targetNamespace)
+		configMap, err := templateConfigMap(ctx, clusterctlv1.RuntimeExtensionProviderType, loadOpts.artifactURL, runtimeExtension, loadOpts.targetNamespace, loadOpts.registry)
 		if err != nil {
 			return fmt.Errorf("cannot prepare manifests config map for runtime extension provider: %w", err)
 		}
@@ -280,9 +283,24 @@ func fetchProviders(ctx context.Context, cl client.Client, providerList genericP
 		return configMaps, err
 	}
 
+	defaults, err := ociRegistryOptions(loadOpts.registry)
+	if err != nil {
+		return configMaps, err
+	}
+
 	for _, provider := range providerList.GetItems() {
 		if provider.GetSpec().FetchConfig != nil && provider.GetSpec().FetchConfig.OCI != "" {
-			cm, err := providercontroller.OCIConfigMap(ctx, provider, ociAuthentication())
+			registry, err := providercontroller.OCIRegistryAuthentication(ctx, cl, provider, defaults)
+			if err != nil {
+				return configMaps, err
+			}
+
+			publicKey, err := providercontroller.OCIVerificationPublicKey(ctx, cl, provider)
+			if err != nil {
+				return configMaps, err
+			}
+
+			cm, err := providercontroller.OCIConfigMap(ctx, provider, registry, publicKey)
 			if err != nil {
 				return configMaps, err
 			}
@@ -301,7 +319,7 @@ func fetchProviders(ctx context.Context, cl client.Client, providerList genericP
 	return configMaps, nil
 }
 
-func templateConfigMap(ctx context.Context, providerType clusterctlv1.ProviderType, providerURL, providerInput, defaultNamespace string) (*corev1.ConfigMap, error) {
+func templateConfigMap(ctx context.Context, providerType clusterctlv1.ProviderType, providerURL, providerInput, defaultNamespace string, registryFlags ociRegistryFlags) (*corev1.ConfigMap, error) {
 	provider, err := templateGenericProvider(providerType, providerInput, defaultNamespace, "", "")
 	if err != nil {
 		return nil, err
@@ -336,8 +354,13 @@ func templateConfigMap(ctx context.Context, providerType clusterctlv1.ProviderTy
 	}
 	provider.SetSpec(spec)
 
+	registry, err := ociRegistryOptions(registryFlags)
+	if err != nil {
+		return nil, err
+	}
+
 	if spec.Version != "" {
-		return providercontroller.OCIConfigMap(ctx, provider, ociAuthentication())
+		return providercontroller.OCIConfigMap(ctx, provider, registry, nil)
 	}
 
 	// User didn't set the version, try to get repository default.
@@ -362,7 +385,7 @@ func templateConfigMap(ctx context.Context, providerType clusterctlv1.ProviderTy
 
 	provider.SetSpec(spec)
 
-	return providercontroller.OCIConfigMap(ctx, provider, ociAuthentication())
+	return providercontroller.OCIConfigMap(ctx, provider, registry, nil)
 }
 
 func providerConfigMap(ctx context.Context, provider operatorv1.GenericProvider) (*corev1.ConfigMap, error) {
@@ -424,3 +447,78 @@ func ociAuthentication() *auth.Credential {
 
 	return nil
 }
+
+// ociRegistryFlags are the flags configuring the connection to OCI registries.
+type ociRegistryFlags struct {
+	registryConfig string
+	caFile         string
+	certFile       string
+	keyFile        string
+}
+
+// addOCIRegistryFlags adds the flags configuring the connection to OCI registries to the command.
+func addOCIRegistryFlags(cmd *cobra.Command, flags *ociRegistryFlags) {
+	cmd.Flags().StringVar(&flags.registryConfig, "registry-config", "",
+		"Path to a Docker config file with per-registry credentials. They take precedence over the OCI_* environment variables.")
+	cmd.Flags().StringVar(&flags.caFile, "ca-file", "",
+		"Path to a PEM encoded CA bundle to verify the OCI registry certificate with.")
+	cmd.Flags().StringVar(&flags.certFile, "cert-file", "",
+		"Path to a PEM encoded client certificate presented to the OCI registry.")
+	cmd.Flags().StringVar(&flags.keyFile, "key-file", "",
+		"Path to the PEM encoded private key of the client certificate.")
+}
+
+// ociRegistryOptions returns the options to connect to OCI registries, from the environment variables
+// and the files set in the flags.
+func ociRegistryOptions(flags ociRegistryFlags) (providercontroller.OCIRegistryOptions, error) {
+	options := providercontroller.OCIRegistryOptions{
+		Credential: ociAuthentication(),
+	}
+
+	if flags.registryConfig != "" {
+		store, err := credentials.NewFileStore(flags.registryConfig)
+		if err != nil {
+			return options, fmt.Errorf("cannot load registry config: %w", err)
+		}
+
+		options.CredentialsStore = store
+	}
+
+	caBundle, err := readOptionalFile(flags.caFile)
+	if err != nil {
+		return options, err
+	}
+
+	clientCert, err := readOptionalFile(flags.certFile)
+	if err != nil {
+		return options, err
+	}
+
+	clientKey, err := readOptionalFile(flags.keyFile)
+	if err != nil {
+		return options, err
+	}
+
+	tlsConfig, err := providercontroller.OCITLSConfig(caBundle, clientCert, clientKey)
+	if err != nil {
+		return options, err
+	}
+
+	options.TLSConfig = tlsConfig
+
+	return options, nil
+}
+
+// readOptionalFile returns the content of the file, or nil if the path is empty.
+func readOptionalFile(path string) ([]byte, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %s: %w", path, err)
+	}
+
+	return content, nil
+}
@@ -29,6 +29,7 @@ import (
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
+	providercontroller "sigs.k8s.io/cluster-api-operator/internal/controller"
 	"sigs.k8s.io/cluster-api-operator/internal/controller/genericprovider"
 )
 
@@ -190,7 +191,7 @@ func TestPreloadCommand(t *testing.T) {
 					g.Expect(err).To(Succeed())
 				}
 
-				g.Expect(publish(ctx, dir, opts.artifactURL)).To(Succeed())
+				g.Expect(publish(ctx, providercontroller.OCIRegistryOptions{}, dir, opts.artifactURL)).To(Succeed())
 
 				for _, data := range opts.providers {
 					spec := data.provider.GetSpec()
@@ -27,14 +27,15 @@ import (
 	oras "oras.land/oras-go/v2"
 	"oras.land/oras-go/v2/content/file"
 	"oras.land/oras-go/v2/registry/remote"
-	"oras.land/oras-go/v2/registry/remote/auth"
-	"oras.land/oras-go/v2/registry/remote/retry"
+
+	providercontroller "sigs.k8s.io/cluster-api-operator/internal/controller"
 )
 
 type publishManifestsOptions struct {
-	ociURL string
-	dir    string
-	files  []string
+	ociURL   string
+	dir      string
+	files    []string
+	registry ociRegistryFlags
 }
 
 var publishOpts = &publishManifestsOptions{}
@@ -64,6 +65,7 @@ func init() {
 	publishCmd.PersistentFlags().StringSliceVarP(&publishOpts.files, "file", "f", []string{}, `Provider manifes file`)
 	publishCmd.Flags().StringVarP(&publishOpts.ociURL, "artifact-url", "u", "",
 		"The URL of the OCI artifact to collect component manifests from.")
+	addOCIRegistryFlags(publishCmd, &publishOpts.registry)
 
 	RootCmd.AddCommand(publishCmd)
 }
@@ -71,10 +73,15 @@ func init() {
 func runPublish() (err error) {
 	ctx := context.Background()
 
-	return publish(ctx, publishOpts.dir, publishOpts.ociURL, publishOpts.files...)
+	registry, err := ociRegistryOptions(publishOpts.registry)
+	if err != nil {
+		return err
+	}
+
+	return publish(ctx, registry, publishOpts.dir, publishOpts.ociURL, publishOpts.files...)
 }
 
-func publish(ctx context.Context, dir, ociURL string, files ...string) error {
+func publish(ctx context.Context, registry providercontroller.OCIRegistryOptions, dir, ociURL string, files ...string) error {
 	// 0. Create a file store
 	fs, err := file.New(dir)
 	if err != nil {
@@ -158,14 +165,7 @@ func publish(ctx context.Context, dir, ociURL string, files ...string) error {
 	}
 
 	repo.PlainHTTP = plainHTTP
-
-	if creds := ociAuthentication(); creds != nil {
-		repo.Client = &auth.Client{
-			Client:     retry.DefaultClient,
-			Cache:      auth.NewCache(),
-			Credential: auth.StaticCredential(reg, *creds),
-		}
-	}
+	repo.Client = registry.Client(reg)
 
 	// 4. Copy from the file store to the remote repository
 	_, err = oras.Copy(ctx, fs, version, repo, version, oras.DefaultCopyOptions)
@@ -0,0 +1,302 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilyaml "sigs.k8s.io/cluster-api/util/yaml"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+
+	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
+	providercontroller "sigs.k8s.io/cluster-api-operator/internal/controller"
+)
+
+type renderOptions struct {
+	providerFile     string
+	configSecretFile string
+	patchesFiles     []string
+	source           string
+	registry         ociRegistryFlags
+}
+
+var renderOpts = &renderOptions{}
+
+var renderCmd = &cobra.Command{
+	Use:     "render",
+	GroupID: groupOther,
+	Short:   "Render the manifests of a provider without installing it",
+	Long: LongDesc(`
+		Render the manifests of a provider, as the operator installs them, without a management cluster.
+
+		The manifests are fetched from the source of the provider, then the variables from the configuration
+		secret are substituted and the provider customizations, patches and image overrides are applied.
+
+		The source can be replaced with an OCI artifact, a URL, or a local directory holding the metadata.yaml
+		and components files named as in the provider OCI artifacts.`),
+	Example: Examples(`
+		# Render the manifests of a provider.
+		capioperator render -f infrastructure-aws.yaml --config-secret aws-variables.yaml
+
+		# Render the manifests of a provider with additional patches.
+		capioperator render -f infrastructure-aws.yaml --config-secret aws-variables.yaml --patches patches.yaml
+
+		# Render the manifests of a provider from an OCI artifact.
+		capioperator render -f infrastructure-aws.yaml -u ttl.sh/infrastructure-provider
+
+		# Render the manifests of a provider from a local directory.
+		capioperator render -f infrastructure-aws.yaml -u ./out/infrastructure-aws`),
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runRender()
+	},
+}
+
+func init() {
+	renderCmd.Flags().StringVarP(&renderOpts.providerFile, "file", "f", "",
+		"Path to the provider resource to render the manifests of.")
+	renderCmd.Flags().StringVar(&renderOpts.configSecretFile, "config-secret", "",
+		"Path to the Secret with the provider variables. It replaces the configuration secret of the provider.")
+	renderCmd.Flags().StringSliceVar(&renderOpts.patchesFiles, "patches", nil,
+		"Paths to files with a list of patches, in the format of the provider patches, applied after the provider patches.")
+	renderCmd.Flags().StringVarP(&renderOpts.source, "source", "u", "",
+		"The OCI artifact, URL or local directory to fetch the provider manifests from, instead of the provider source.")
+	addOCIRegistryFlags(renderCmd, &renderOpts.registry)
+
+	_ = renderCmd.MarkFlagRequired("file")
+
+	RootCmd.AddCommand(renderCmd)
+}
+
+func runRender() error {
+	ctx := context.Background()
+
+	manifests, err := renderProviderFile(ctx, renderOpts)
+	if err != nil {
+		return err
+	}
+
+	out, err := utilyaml.FromUnstructured(manifests)
+	if err != nil {
+		return fmt.Errorf("cannot serialize provider manifests: %w", err)
+	}
+
+	fmt.Print(string(out))
+
+	return nil
+}
+
+// renderProviderFile renders the manifests of the provider read from the file, with the configuration secret
+// and the patches read from the files.
+func renderProviderFile(ctx context.Context, opts *renderOptions) ([]unstructured.Unstructured, error) {
+	provider, err := readProviderFile(opts.providerFile)
+	if err != nil {
+		return nil, err
+	}
+
+	objs := []ctrlclient.Object{}
+
+	if opts.configSecretFile != "" {
+		secret, err := readConfigSecretFile(opts.configSecretFile, provider.GetNamespace())
+		if err != nil {
+			return nil, err
+		}
+
+		spec := provider.GetSpec()
+		spec.ConfigSecret = &operatorv1.SecretReference{Name: secret.Name, Namespace: secret.Namespace}
+		provider.SetSpec(spec)
+
+		objs = append(objs, secret)
+	}
+
+	for _, path := range opts.patchesFiles {
+		patches, err := readPatchesFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		spec := provider.GetSpec()
+		spec.Patches = append(spec.Patches, patches...)
+		provider.SetSpec(spec)
+	}
+
+	return renderProvider(ctx, provider, objs, opts.source, opts.registry)
+}
+
+// renderProvider renders the manifests of the provider with the operator phases, against an in-memory client
+// holding the objects the provider refers to. If set, the source replaces the fetch configuration of the provider.
+func renderProvider(ctx context.Context, provider genericProvider, objs []ctrlclient.Object, source string, registryFlags ociRegistryFlags) ([]unstructured.Unstructured, error) {
+	configMap, err := setRenderSource(provider, source)
+	if err != nil {
+		return nil, err
+	}
+
+	if configMap != nil {
+		objs = append(objs, configMap)
+	}
+
+	registry, err := ociRegistryOptions(registryFlags)
+	if err != nil {
+		return nil, err
+	}
+
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+
+	manifests, err := providercontroller.RenderComponents(ctx, cl, provider, providercontroller.WithOCIRegistryOptions(registry))
+	if err != nil {
+		return nil, fmt.Errorf("cannot render the manifests of provider %s/%s: %w", provider.GetNamespace(), provider.GetName(), err)
+	}
+
+	return manifests, nil
+}
+
+// setRenderSource replaces the fetch configuration of the provider with the source. For a local directory,
+// the ConfigMap with the manifests read from the directory is returned, and selected by the provider.
+func setRenderSource(provider genericProvider, source string) (*corev1.ConfigMap, error) {
+	if source == "" {
+		return nil, nil
+	}
+
+	spec := provider.GetSpec()
+	spec.FetchConfig = nil
+
+	if info, err := os.Stat(source); err == nil && info.IsDir() {
+		if spec.Version == "" {
+			return nil, fmt.Errorf("provider version must be set to render the manifests from a local directory")
+		}
+
+		provider.SetSpec(spec)
+
+		configMap, err := providercontroller.LocalDirectoryConfigMap(provider, source)
+		if err != nil {
+			return nil, err
+		}
+
+		spec.FetchConfig = &operatorv1.FetchConfiguration{
+			Selector: &metav1.LabelSelector{MatchLabels: configMap.Labels},
+		}
+		provider.SetSpec(spec)
+
+		return configMap, nil
+	}
+
+	parsedURL, err := url.Parse(source)
+	if err != nil {
+		return nil, fmt.Errorf("invalid source: %w", err)
+	}
+
+	switch parsedURL.Scheme {
+	case "http", "https", "file":
+		spec.FetchConfig = &operatorv1.FetchConfiguration{URL: source}
+	default:
+		spec.FetchConfig = &operatorv1.FetchConfiguration{
+			OCIConfiguration: operatorv1.OCIConfiguration{OCI: source},
+		}
+	}
+
+	provider.SetSpec(spec)
+
+	return nil, nil
+}
+
+// readProviderFile reads the provider resource from the file. The provider is in the default namespace if
+// the file doesn't set one.
+func readProviderFile(path string) (genericProvider, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %s: %w", path, err)
+	}
+
+	obj, gvk, err := serializer.NewCodecFactory(scheme).UniversalDeserializer().Decode(data, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode %s: %w", path, err)
+	}
+
+	provider, ok := obj.(genericProvider)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a provider: %s", path, gvk)
+	}
+
+	provider.GetObjectKind().SetGroupVersionKind(*gvk)
+
+	if provider.GetNamespace() == "" {
+		provider.SetNamespace(metav1.NamespaceDefault)
+	}
+
+	return provider, nil
+}
+
+// readConfigSecretFile reads the provider configuration secret from the file. The secret is in the given
+// namespace if the file doesn't set one.
+func readConfigSecretFile(path, namespace string) (*corev1.Secret, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %s: %w", path, err)
+	}
+
+	secret := &corev1.Secret{}
+	if err := yaml.UnmarshalStrict(data, secret); err != nil {
+		return nil, fmt.Errorf("cannot decode %s: %w", path, err)
+	}
+
+	if secret.Name == "" {
+		return nil, fmt.Errorf("secret in %s has no name", path)
+	}
+
+	if secret.Namespace == "" {
+		secret.Namespace = namespace
+	}
+
+	// String data is merged into the data by the API server, which is not involved here.
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+
+	for key, value := range secret.StringData {
+		secret.Data[key] = []byte(value)
+	}
+
+	secret.StringData = nil
+
+	return secret, nil
+}
+
+// readPatchesFile reads the list of patches from the file.
+func readPatchesFile(path string) ([]*operatorv1.Patch, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %s: %w", path, err)
+	}
+
+	patches := []*operatorv1.Patch{}
+	if err := yaml.UnmarshalStrict(data, &patches); err != nil {
+		return nil, fmt.Errorf("cannot decode patches in %s: %w", path, err)
+	}
+
+	return patches, nil
+}
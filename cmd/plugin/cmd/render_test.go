@@ -0,0 +1,154 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	utilyaml "sigs.k8s.io/cluster-api/util/yaml"
+)
+
+func TestRenderProviderFile(t *testing.T) {
+	metadata := `apiVersion: clusterctl.cluster.x-k8s.io/v1alpha3
+kind: Metadata
+releaseSeries:
+- major: 1
+  minor: 9
+  contract: v1beta1
+`
+
+	components := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: capi-controller-manager
+  namespace: capi-system
+spec:
+  template:
+    spec:
+      containers:
+      - name: manager
+        image: registry.k8s.io/cluster-api/cluster-api-controller:v1.9.3
+        args:
+        - --v=${CAPI_LOG_LEVEL}
+`
+
+	provider := `apiVersion: operator.cluster.x-k8s.io/v1alpha2
+kind: CoreProvider
+metadata:
+  name: cluster-api
+  namespace: capi-system
+spec:
+  version: "%s"
+`
+
+	secret := `apiVersion: v1
+kind: Secret
+metadata:
+  name: capi-variables
+stringData:
+  CAPI_LOG_LEVEL: "5"
+`
+
+	patches := `- patch: |
+    metadata:
+      labels:
+        team: platform
+  target:
+    kind: Deployment
+`
+
+	testCases := []struct {
+		name             string
+		version          string
+		configSecret     bool
+		patches          bool
+		expectedErr      string
+		expectedContents []string
+	}{
+		{
+			name:         "provider with config secret and patches",
+			version:      "v1.9.3",
+			configSecret: true,
+			patches:      true,
+			expectedContents: []string{
+				"--v=5",
+				"team: platform",
+				"kind: Namespace",
+			},
+		},
+		{
+			name:        "missing variable",
+			version:     "v1.9.3",
+			expectedErr: "CAPI_LOG_LEVEL",
+		},
+		{
+			name:         "local directory without provider version",
+			configSecret: true,
+			expectedErr:  "provider version must be set",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			dir := t.TempDir()
+			source := filepath.Join(dir, "source")
+
+			g.Expect(os.Mkdir(source, 0o700)).To(Succeed())
+			g.Expect(os.WriteFile(filepath.Join(source, "metadata.yaml"), []byte(metadata), 0o600)).To(Succeed())
+			g.Expect(os.WriteFile(filepath.Join(source, "core-components.yaml"), []byte(components), 0o600)).To(Succeed())
+
+			opts := &renderOptions{
+				providerFile: filepath.Join(dir, "provider.yaml"),
+				source:       source,
+			}
+
+			g.Expect(os.WriteFile(opts.providerFile, []byte(fmt.Sprintf(provider, tc.version)), 0o600)).To(Succeed())
+
+			if tc.configSecret {
+				opts.configSecretFile = filepath.Join(dir, "secret.yaml")
+				g.Expect(os.WriteFile(opts.configSecretFile, []byte(secret), 0o600)).To(Succeed())
+			}
+
+			if tc.patches {
+				opts.patchesFiles = []string{filepath.Join(dir, "patches.yaml")}
+				g.Expect(os.WriteFile(opts.patchesFiles[0], []byte(patches), 0o600)).To(Succeed())
+			}
+
+			manifests, err := renderProviderFile(context.Background(), opts)
+			if tc.expectedErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tc.expectedErr)))
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+
+			out, err := utilyaml.FromUnstructured(manifests)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			for _, content := range tc.expectedContents {
+				g.Expect(string(out)).To(ContainSubstring(content))
+			}
+		})
+	}
+}
@@ -40,13 +40,25 @@ const (
 
 var verbosity *int
 
+// exitCodeError is returned by commands which have to terminate with a specific exit code.
+type exitCodeError struct {
+	code    int
+	message string
+}
+
+func (e *exitCodeError) Error() string {
+	return e.message
+}
+
 var log logr.Logger
 
 // RootCmd is capioperator root CLI command.
 var RootCmd = &cobra.Command{
 	Use:          "capioperator",
 	SilenceUsage: true,
-	Short:        "capioperator controls the lifecycle of a Cluster API management cluster",
+	// Errors are printed by Execute, as the exit code errors must not be printed.
+	SilenceErrors: true,
+	Short:         "capioperator controls the lifecycle of a Cluster API management cluster",
 	Long: LongDesc(`
 		Get started with Cluster API using capioperator to create a management cluster,
 		install providers, and create templates for your workload cluster.`),
@@ -64,11 +76,25 @@ func Execute() {
 				stackErr.ErrorStack()
 			}
 		}
+
 		// TODO: print cmd help if validation error
-		os.Exit(1)
+		os.Exit(handleError(RootCmd, err))
 	}
 }
 
+// handleError prints the error of the command, unless it is an exitCodeError, whose command already reported
+// its result, and returns the exit code.
+func handleError(cmd *cobra.Command, err error) int {
+	var exitErr *exitCodeError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+
+	cmd.PrintErrln(cmd.ErrPrefix(), err.Error())
+
+	return 1
+}
+
 func init() {
 	flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
 
@@ -0,0 +1,78 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+)
+
+func TestHandleError(t *testing.T) {
+	testCases := []struct {
+		name             string
+		err              error
+		expectedCode     int
+		expectedPrintout string
+	}{
+		{
+			name:         "exit code error is not printed",
+			err:          &exitCodeError{code: upgradeAvailableExitCode, message: "upgrades are available"},
+			expectedCode: upgradeAvailableExitCode,
+		},
+		{
+			name:         "wrapped exit code error is not printed",
+			err:          fmt.Errorf("diff: %w", &exitCodeError{code: diffFoundExitCode, message: "the rendered manifests change the components"}),
+			expectedCode: diffFoundExitCode,
+		},
+		{
+			name:             "other errors are printed",
+			err:              errors.New("cannot create a client"),
+			expectedCode:     1,
+			expectedPrintout: "Error: cannot create a client\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			out := &bytes.Buffer{}
+
+			cmd := &cobra.Command{
+				Use:           "test",
+				SilenceUsage:  true,
+				SilenceErrors: true,
+				RunE: func(*cobra.Command, []string) error {
+					return tc.err
+				},
+			}
+			cmd.SetArgs([]string{})
+			cmd.SetOut(out)
+			cmd.SetErr(out)
+
+			err := cmd.Execute()
+			g.Expect(err).To(MatchError(tc.err))
+			g.Expect(handleError(cmd, err)).To(Equal(tc.expectedCode))
+			g.Expect(out.String()).To(Equal(tc.expectedPrintout))
+		})
+	}
+}
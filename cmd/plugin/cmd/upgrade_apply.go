@@ -18,24 +18,40 @@ package cmd
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/go-errors/errors"
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
+	"sigs.k8s.io/cluster-api-operator/util"
 )
 
 type upgradeApplyOptions struct {
-	kubeconfig              string
-	kubeconfigContext       string
-	contract                string
-	coreProvider            string
-	bootstrapProviders      []string
-	controlPlaneProviders   []string
-	infrastructureProviders []string
-	ipamProviders           []string
-	// runtimeExtensionProviders []string
-	addonProviders      []string
-	waitProviders       bool
-	waitProviderTimeout int
+	kubeconfig                string
+	kubeconfigContext         string
+	contract                  string
+	coreProvider              string
+	bootstrapProviders        []string
+	controlPlaneProviders     []string
+	infrastructureProviders   []string
+	ipamProviders             []string
+	runtimeExtensionProviders []string
+	addonProviders            []string
+	waitProviders             bool
+	waitProviderTimeout       int
+}
+
+// upgradeTarget defines the version a provider in the management cluster should be upgraded to.
+type upgradeTarget struct {
+	provider operatorv1.GenericProvider
+	version  string
 }
 
 var upgradeApplyOpts = &upgradeApplyOptions{}
@@ -57,7 +73,10 @@ var upgradeApplyCmd = &cobra.Command{
 		capioperator upgrade apply --contract v1alpha4
 
 		# Upgrades only the aws provider to the v2.0.1 version.
-		capioperator upgrade apply --infrastructure aws:v2.0.1`),
+		capioperator upgrade apply --infrastructure aws:v2.0.1
+
+		# Upgrades the core and kubeadm providers, waiting for each provider to be upgraded.
+		capioperator upgrade apply --core cluster-api:v1.12.0 --bootstrap kubeadm:v1.12.0 --control-plane kubeadm:v1.12.0 --wait-providers`),
 	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runUpgradeApply()
@@ -82,8 +101,8 @@ func init() {
 		"ControlPlane providers instance and versions (e.g. kubeadm:v1.1.5) to upgrade to. This flag can be used as alternative to --contract.")
 	upgradeApplyCmd.Flags().StringSliceVar(&upgradeApplyOpts.ipamProviders, "ipam", nil,
 		"IPAM providers and versions (e.g. infoblox:v0.0.1) to upgrade to. This flag can be used as alternative to --contract.")
-	// upgradeApplyCmd.Flags().StringSliceVar(&upgradeApplyOpts.runtimeExtensionProviders, "runtime-extension", nil,
-	//	"Runtime extension providers and versions (e.g. test:v0.0.1) to upgrade to. This flag can be used as alternative to --contract.")
+	upgradeApplyCmd.Flags().StringSliceVar(&upgradeApplyOpts.runtimeExtensionProviders, "runtime-extension", nil,
+		"Runtime extension providers and versions (e.g. test:v0.0.1) to upgrade to. This flag can be used as alternative to --contract.")
 	upgradeApplyCmd.Flags().StringSliceVar(&upgradeApplyOpts.addonProviders, "addon", nil,
 		"Add-on providers and versions (e.g. helm:v0.1.0) to upgrade to. This flag can be used as alternative to --contract.")
 	upgradeApplyCmd.Flags().BoolVar(&upgradeApplyOpts.waitProviders, "wait-providers", false,
@@ -99,21 +118,254 @@ func runUpgradeApply() error {
 		(len(upgradeApplyOpts.bootstrapProviders) > 0) ||
 		(len(upgradeApplyOpts.controlPlaneProviders) > 0) ||
 		(len(upgradeApplyOpts.infrastructureProviders) > 0) ||
-		// (len(upgradeApplyOpts.ipamProviders) > 0) ||
-		// (len(upgradeApplyOpts.runtimeExtensionProviders) > 0) ||
+		(len(upgradeApplyOpts.ipamProviders) > 0) ||
+		(len(upgradeApplyOpts.runtimeExtensionProviders) > 0) ||
 		(len(upgradeApplyOpts.addonProviders) > 0)
 
 	if upgradeApplyOpts.contract == "" && !hasProviderNames {
-		return errors.New("Either the --contract flag or at least one of the following flags has to be set: --core, --bootstrap, --control-plane, --infrastructure, --ipam, --extension, --addon")
+		return errors.New("Either the --contract flag or at least one of the following flags has to be set: --core, --bootstrap, --control-plane, --infrastructure, --ipam, --runtime-extension, --addon")
 	}
 
 	if upgradeApplyOpts.contract != "" && hasProviderNames {
-		return errors.New("The --contract flag can't be used in combination with --core, --bootstrap, --control-plane, --infrastructure, --ipam, --extension, --addon")
+		return errors.New("The --contract flag can't be used in combination with --core, --bootstrap, --control-plane, --infrastructure, --ipam, --runtime-extension, --addon")
 	}
 
 	return upgradeProvider(ctx, upgradeApplyOpts)
 }
 
 func upgradeProvider(ctx context.Context, opts *upgradeApplyOptions) error {
-	return errors.New("Not implemented")
+	if opts.kubeconfig == "" {
+		opts.kubeconfig = GetKubeconfigLocation()
+	}
+
+	client, err := CreateKubeClient(opts.kubeconfig, opts.kubeconfigContext)
+	if err != nil {
+		return fmt.Errorf("cannot create a client: %w", err)
+	}
+
+	var targets []upgradeTarget
+
+	if opts.contract != "" {
+		targets, err = planContractUpgrade(ctx, client, opts.contract)
+	} else {
+		targets, err = planProvidersUpgrade(ctx, client, opts)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if len(targets) == 0 {
+		log.Info("All providers are already up to date")
+		return nil
+	}
+
+	// Providers are upgraded in dependency order, starting with the core provider.
+	sortUpgradeTargets(targets)
+
+	for _, target := range targets {
+		if err := applyUpgradeTarget(ctx, client, target); err != nil {
+			return err
+		}
+
+		if opts.waitProviders {
+			if err := waitForProviderUpgrade(ctx, client, target, time.Duration(opts.waitProviderTimeout)*time.Second); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// planContractUpgrade returns the latest versions of all installed providers, as computed by the upgrade plan.
+func planContractUpgrade(ctx context.Context, client ctrlclient.Client, contract string) ([]upgradeTarget, error) {
+	if err := util.ValidateUpgradeContract(contract); err != nil {
+		return nil, err
+	}
+
+	plan, err := planUpgrade(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	genericProviders, _, err := util.GetInstalledProviders(ctx, client)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get installed providers: %w", err)
+	}
+
+	return contractUpgradeTargets(plan, genericProviders, contract), nil
+}
+
+// contractUpgradeTargets returns the upgrade targets of the plan items compatible with the contract.
+// Providers whose next version implements an incompatible contract are not upgraded.
+func contractUpgradeTargets(plan upgradePlan, genericProviders []operatorv1.GenericProvider, contract string) []upgradeTarget {
+	targets := []upgradeTarget{}
+
+	for _, item := range plan.Providers {
+		if !isUpgradeAvailable(item) {
+			continue
+		}
+
+		if !util.CompatibleContracts(contract).Has(item.Contract) {
+			log.Info("Provider next version is not compatible with the contract, skipping", "Type", item.Type, "Name", item.Name, "Namespace", item.Namespace,
+				"Version", item.NextVersion, "Contract", item.Contract)
+
+			continue
+		}
+
+		for _, genericProvider := range genericProviders {
+			if genericProvider.GetType() == item.Type &&
+				genericProvider.ProviderName() == item.Name &&
+				genericProvider.GetNamespace() == item.Namespace {
+				targets = append(targets, upgradeTarget{provider: genericProvider, version: item.NextVersion})
+
+				break
+			}
+		}
+	}
+
+	return targets
+}
+
+// planProvidersUpgrade returns the versions explicitly requested for each provider.
+func planProvidersUpgrade(ctx context.Context, client ctrlclient.Client, opts *upgradeApplyOptions) ([]upgradeTarget, error) {
+	genericProviders, _, err := util.GetInstalledProviders(ctx, client)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get installed providers: %w", err)
+	}
+
+	providerInputs := map[clusterctlv1.ProviderType][]string{
+		clusterctlv1.BootstrapProviderType:        opts.bootstrapProviders,
+		clusterctlv1.ControlPlaneProviderType:     opts.controlPlaneProviders,
+		clusterctlv1.InfrastructureProviderType:   opts.infrastructureProviders,
+		clusterctlv1.IPAMProviderType:             opts.ipamProviders,
+		clusterctlv1.RuntimeExtensionProviderType: opts.runtimeExtensionProviders,
+		clusterctlv1.AddonProviderType:            opts.addonProviders,
+	}
+
+	if opts.coreProvider != "" {
+		providerInputs[clusterctlv1.CoreProviderType] = []string{opts.coreProvider}
+	}
+
+	targets := []upgradeTarget{}
+
+	for providerType, inputs := range providerInputs {
+		for _, input := range inputs {
+			target, err := findUpgradeTarget(genericProviders, providerType, input)
+			if err != nil {
+				return nil, err
+			}
+
+			if target.provider.GetSpec().Version == target.version {
+				log.Info("Provider is already up to date", "Type", target.provider.GetType(), "Name", target.provider.GetName(), "Namespace", target.provider.GetNamespace(), "Version", target.version)
+				continue
+			}
+
+			targets = append(targets, target)
+		}
+	}
+
+	return targets, nil
+}
+
+// findUpgradeTarget matches the provider input against the installed providers of the given type.
+func findUpgradeTarget(genericProviders []operatorv1.GenericProvider, providerType clusterctlv1.ProviderType, providerInput string) (upgradeTarget, error) {
+	namespace, name, version, err := parseUpgradeItem(providerInput)
+	if err != nil {
+		return upgradeTarget{}, err
+	}
+
+	var target upgradeTarget
+
+	for _, genericProvider := range genericProviders {
+		if util.ClusterctlProviderType(genericProvider) != providerType ||
+			genericProvider.ProviderName() != name ||
+			(namespace != "" && genericProvider.GetNamespace() != namespace) {
+			continue
+		}
+
+		if target.provider != nil {
+			return upgradeTarget{}, fmt.Errorf("multiple %s providers with name %s found, please use the namespace/name:version format", providerType, name)
+		}
+
+		target = upgradeTarget{provider: genericProvider, version: version}
+	}
+
+	if target.provider == nil {
+		return upgradeTarget{}, fmt.Errorf("%s provider %s is not installed in the management cluster", providerType, providerInput)
+	}
+
+	return target, nil
+}
+
+// parseUpgradeItem parses the provider input in the name:version or the deprecated namespace/name:version format.
+func parseUpgradeItem(providerInput string) (namespace, name, version string, err error) {
+	nameAndVersion := providerInput
+
+	if parts := strings.Split(providerInput, "/"); len(parts) == 2 {
+		log.Info("Specifying the provider using namespace/name:version is deprecated and will be dropped in a future release", "Provider", providerInput)
+
+		namespace, nameAndVersion = parts[0], parts[1]
+	} else if len(parts) > 2 {
+		return "", "", "", fmt.Errorf("invalid provider format: %s", providerInput)
+	}
+
+	parts := strings.Split(nameAndVersion, ":")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", "", fmt.Errorf("invalid provider format: %s, expected name:version", providerInput)
+	}
+
+	return namespace, parts[0], parts[1], nil
+}
+
+// sortUpgradeTargets sorts the upgrade targets by provider type, so the core provider is upgraded first.
+func sortUpgradeTargets(targets []upgradeTarget) {
+	sort.SliceStable(targets, func(i, j int) bool {
+		return util.ClusterctlProviderType(targets[i].provider).Order() < util.ClusterctlProviderType(targets[j].provider).Order()
+	})
+}
+
+// applyUpgradeTarget patches the provider version, which triggers the upgrade in the operator.
+func applyUpgradeTarget(ctx context.Context, client ctrlclient.Client, target upgradeTarget) error {
+	provider := target.provider
+
+	log.Info("Upgrading provider", "Type", provider.GetType(), "Name", provider.GetName(), "Namespace", provider.GetNamespace(),
+		"Current Version", provider.GetSpec().Version, "Target Version", target.version)
+
+	//nolint:forcetypeassert
+	patch := ctrlclient.MergeFrom(provider.DeepCopyObject().(ctrlclient.Object))
+
+	spec := provider.GetSpec()
+	spec.Version = target.version
+	provider.SetSpec(spec)
+
+	if err := client.Patch(ctx, provider, patch); err != nil {
+		return fmt.Errorf("cannot upgrade %s provider %s/%s: %w", provider.GetType(), provider.GetNamespace(), provider.GetName(), err)
+	}
+
+	return nil
+}
+
+// waitForProviderUpgrade waits until the operator reports the target version as installed, and the provider is ready.
+func waitForProviderUpgrade(ctx context.Context, client ctrlclient.Client, target upgradeTarget, timeout time.Duration) error {
+	provider := target.provider
+
+	log.Info("Waiting for provider to be upgraded", "Type", provider.GetType(), "Name", provider.GetName(), "Namespace", provider.GetNamespace())
+
+	pollingInterval := 500 * time.Millisecond
+
+	if err := wait.PollUntilContextTimeout(ctx, pollingInterval, timeout, false, func(ctx context.Context) (bool, error) {
+		if err := client.Get(ctx, ctrlclient.ObjectKeyFromObject(provider), provider); err != nil {
+			return false, fmt.Errorf("cannot get provider: %w", err)
+		}
+
+		return util.IsProviderUpgraded(provider, target.version)
+	}); err != nil {
+		return fmt.Errorf("%s provider %s/%s was not upgraded to %s: %w", provider.GetType(), provider.GetNamespace(), provider.GetName(), target.version, err)
+	}
+
+	log.Info("Provider is upgraded", "Type", provider.GetType(), "Name", provider.GetName(), "Namespace", provider.GetNamespace(), "Version", target.version)
+
+	return nil
 }
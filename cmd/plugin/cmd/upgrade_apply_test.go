@@ -0,0 +1,272 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+
+	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
+	"sigs.k8s.io/cluster-api-operator/util"
+)
+
+func TestParseUpgradeItem(t *testing.T) {
+	testCases := []struct {
+		name              string
+		provider          string
+		expectedNamespace string
+		expectedName      string
+		expectedVersion   string
+		expectedErr       bool
+	}{
+		{
+			name:            "name and version",
+			provider:        "aws:v2.0.1",
+			expectedName:    "aws",
+			expectedVersion: "v2.0.1",
+		},
+		{
+			name:              "namespace, name and version",
+			provider:          "capa-system/aws:v2.0.1",
+			expectedNamespace: "capa-system",
+			expectedName:      "aws",
+			expectedVersion:   "v2.0.1",
+		},
+		{
+			name:        "missing version",
+			provider:    "aws",
+			expectedErr: true,
+		},
+		{
+			name:        "empty version",
+			provider:    "aws:",
+			expectedErr: true,
+		},
+		{
+			name:        "invalid format",
+			provider:    "capa-system/aws/extra:v2.0.1",
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			namespace, name, version, err := parseUpgradeItem(tc.provider)
+			if tc.expectedErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(namespace).To(Equal(tc.expectedNamespace))
+			g.Expect(name).To(Equal(tc.expectedName))
+			g.Expect(version).To(Equal(tc.expectedVersion))
+		})
+	}
+}
+
+func TestFindUpgradeTarget(t *testing.T) {
+	genericProviders := []operatorv1.GenericProvider{
+		generateGenericProvider(clusterctlv1.CoreProviderType, "cluster-api", "capi-system", "v1.11.0", "", ""),
+		generateGenericProvider(clusterctlv1.InfrastructureProviderType, "docker", "capd-system", "v1.11.0", "", ""),
+		generateGenericProvider(clusterctlv1.InfrastructureProviderType, "aws", "capa-system", "v2.0.0", "", ""),
+		generateGenericProvider(clusterctlv1.InfrastructureProviderType, "aws", "capa-other", "v2.0.0", "", ""),
+	}
+
+	testCases := []struct {
+		name              string
+		providerType      clusterctlv1.ProviderType
+		provider          string
+		expectedNamespace string
+		expectedVersion   string
+		expectedErr       bool
+	}{
+		{
+			name:              "core provider",
+			providerType:      clusterctlv1.CoreProviderType,
+			provider:          "cluster-api:v1.12.0",
+			expectedNamespace: "capi-system",
+			expectedVersion:   "v1.12.0",
+		},
+		{
+			name:              "infrastructure provider",
+			providerType:      clusterctlv1.InfrastructureProviderType,
+			provider:          "docker:v1.12.0",
+			expectedNamespace: "capd-system",
+			expectedVersion:   "v1.12.0",
+		},
+		{
+			name:              "infrastructure provider with namespace",
+			providerType:      clusterctlv1.InfrastructureProviderType,
+			provider:          "capa-other/aws:v2.0.1",
+			expectedNamespace: "capa-other",
+			expectedVersion:   "v2.0.1",
+		},
+		{
+			name:         "ambiguous provider",
+			providerType: clusterctlv1.InfrastructureProviderType,
+			provider:     "aws:v2.0.1",
+			expectedErr:  true,
+		},
+		{
+			name:         "provider of a different type",
+			providerType: clusterctlv1.BootstrapProviderType,
+			provider:     "docker:v1.12.0",
+			expectedErr:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			target, err := findUpgradeTarget(genericProviders, tc.providerType, tc.provider)
+			if tc.expectedErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(target.provider.GetNamespace()).To(Equal(tc.expectedNamespace))
+			g.Expect(target.version).To(Equal(tc.expectedVersion))
+		})
+	}
+}
+
+func TestSortUpgradeTargets(t *testing.T) {
+	g := NewWithT(t)
+
+	targets := []upgradeTarget{
+		{provider: generateGenericProvider(clusterctlv1.AddonProviderType, "helm", "caaph-system", "v0.1.0", "", "")},
+		{provider: generateGenericProvider(clusterctlv1.InfrastructureProviderType, "docker", "capd-system", "v1.11.0", "", "")},
+		{provider: generateGenericProvider(clusterctlv1.BootstrapProviderType, "kubeadm", "capi-kubeadm-bootstrap-system", "v1.11.0", "", "")},
+		{provider: generateGenericProvider(clusterctlv1.CoreProviderType, "cluster-api", "capi-system", "v1.11.0", "", "")},
+	}
+
+	sortUpgradeTargets(targets)
+
+	names := []string{}
+	for _, target := range targets {
+		names = append(names, target.provider.GetName())
+	}
+
+	g.Expect(names).To(Equal([]string{"cluster-api", "kubeadm", "docker", "helm"}))
+}
+
+func TestContractUpgradeTargets(t *testing.T) {
+	g := NewWithT(t)
+
+	genericProviders := []operatorv1.GenericProvider{
+		generateGenericProvider(clusterctlv1.CoreProviderType, "cluster-api", "capi-system", "v1.11.0", "", ""),
+		generateGenericProvider(clusterctlv1.BootstrapProviderType, "kubeadm", "capi-kubeadm-bootstrap-system", "v1.11.0", "", ""),
+		generateGenericProvider(clusterctlv1.InfrastructureProviderType, "docker", "capd-system", "v1.11.0", "", ""),
+		generateGenericProvider(clusterctlv1.InfrastructureProviderType, "aws", "capa-system", "v2.8.0", "", ""),
+	}
+
+	plan := upgradePlan{Providers: []upgradeItem{
+		{Name: "cluster-api", Namespace: "capi-system", Type: "core", CurrentVersion: "v1.11.0", NextVersion: "v1.12.0", Contract: "v1beta2"},
+		{Name: "kubeadm", Namespace: "capi-kubeadm-bootstrap-system", Type: "bootstrap", CurrentVersion: "v1.11.0", NextVersion: "v1.12.0", Contract: "v1beta2"},
+		{Name: "docker", Namespace: "capd-system", Type: "infrastructure", CurrentVersion: "v1.11.0", NextVersion: "v1.13.0", Contract: "v1beta3"},
+		{Name: "aws", Namespace: "capa-system", Type: "infrastructure", CurrentVersion: "v2.8.0", NextVersion: "v2.8.0", Contract: "v1beta1"},
+	}}
+
+	targets := contractUpgradeTargets(plan, genericProviders, "v1beta2")
+
+	names := []string{}
+	for _, target := range targets {
+		names = append(names, target.provider.GetName()+":"+target.version)
+	}
+
+	g.Expect(names).To(Equal([]string{"cluster-api:v1.12.0", "kubeadm:v1.12.0"}))
+}
+
+func TestProviderUpgraded(t *testing.T) {
+	testCases := []struct {
+		name             string
+		generation       int64
+		installedVersion *string
+		conditions       []metav1.Condition
+		expected         bool
+		expectedErr      bool
+	}{
+		{
+			name:             "upgrade not observed yet",
+			generation:       2,
+			installedVersion: ptr.To("v1.11.0"),
+			conditions: []metav1.Condition{
+				{Type: operatorv1.ProviderUpgradedCondition, Status: metav1.ConditionFalse, ObservedGeneration: 1},
+			},
+		},
+		{
+			name:             "upgrade failed",
+			generation:       2,
+			installedVersion: ptr.To("v1.11.0"),
+			conditions: []metav1.Condition{
+				{Type: operatorv1.ProviderUpgradedCondition, Status: metav1.ConditionFalse, ObservedGeneration: 2, Message: "failed"},
+			},
+			expectedErr: true,
+		},
+		{
+			name:             "provider upgraded but not ready",
+			generation:       2,
+			installedVersion: ptr.To("v1.12.0"),
+			conditions: []metav1.Condition{
+				{Type: operatorv1.ProviderUpgradedCondition, Status: metav1.ConditionTrue, ObservedGeneration: 2},
+				{Type: clusterv1.ReadyCondition, Status: metav1.ConditionFalse, ObservedGeneration: 2},
+			},
+		},
+		{
+			name:             "provider upgraded and ready",
+			generation:       2,
+			installedVersion: ptr.To("v1.12.0"),
+			conditions: []metav1.Condition{
+				{Type: operatorv1.ProviderUpgradedCondition, Status: metav1.ConditionTrue, ObservedGeneration: 2},
+				{Type: clusterv1.ReadyCondition, Status: metav1.ConditionTrue, ObservedGeneration: 2},
+			},
+			expected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			provider := generateGenericProvider(clusterctlv1.CoreProviderType, "cluster-api", "capi-system", "v1.12.0", "", "")
+			provider.SetGeneration(tc.generation)
+			provider.SetStatus(operatorv1.ProviderStatus{
+				InstalledVersion: tc.installedVersion,
+				Conditions:       tc.conditions,
+			})
+
+			upgraded, err := util.IsProviderUpgraded(provider, "v1.12.0")
+			if tc.expectedErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(upgraded).To(Equal(tc.expected))
+		})
+	}
+}
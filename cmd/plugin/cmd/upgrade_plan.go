@@ -19,13 +19,17 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
 	"text/tabwriter"
 
+	"github.com/go-errors/errors"
 	"github.com/spf13/cobra"
 	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
 	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
@@ -37,52 +41,75 @@ import (
 	"sigs.k8s.io/cluster-api-operator/util"
 )
 
+// upgradeAvailableExitCode is the exit code of the upgrade plan command with --exit-code,
+// when at least one component can be upgraded.
+const upgradeAvailableExitCode = 2
+
 type upgradePlanOptions struct {
 	kubeconfig        string
 	kubeconfigContext string
+	output            string
+	exitCode          bool
 }
 
 // certManagerUpgradePlan defines the upgrade plan if cert-manager needs to be
 // upgraded to a different version.
 type certManagerUpgradePlan struct {
-	ExternallyManaged bool
-	From, To          string
-	ShouldUpgrade     bool
+	ExternallyManaged bool   `json:"externallyManaged"`
+	From              string `json:"from"`
+	To                string `json:"to"`
+	ShouldUpgrade     bool   `json:"shouldUpgrade"`
 }
 
 // capiOperatorUpgradePlan defines the upgrade plan if CAPI operator needs to be
 // upgraded to a different version.
 type capiOperatorUpgradePlan struct {
-	ExternallyManaged bool
-	From, To          string
-	ShouldUpgrade     bool
+	ExternallyManaged bool   `json:"externallyManaged"`
+	From              string `json:"from"`
+	To                string `json:"to"`
+	ShouldUpgrade     bool   `json:"shouldUpgrade"`
 }
 
 // upgradePlan defines a list of possible upgrade targets for a management cluster.
 type upgradePlan struct {
-	Contract  string
-	Providers []upgradeItem
+	Contract  string        `json:"contract"`
+	Providers []upgradeItem `json:"providers"`
+}
+
+// upgradePlanOutput is the machine-readable representation of the upgrade plan printed with --output.
+// Fields can be added, but existing fields must not be renamed or removed, as automation relies on them.
+type upgradePlanOutput struct {
+	CertManager      certManagerUpgradePlan  `json:"certManager"`
+	CAPIOperator     capiOperatorUpgradePlan `json:"capiOperator"`
+	Contract         string                  `json:"contract"`
+	Providers        []upgradeItem           `json:"providers"`
+	UpgradeAvailable bool                    `json:"upgradeAvailable"`
 }
 
 type providerSource string
 
-type providerSourceType string
+type providerSourceType = util.ProviderSourceType
 
 var (
-	providerSourceTypeBuiltin   providerSourceType = "builtin"
-	providerSourceTypeCustomURL providerSourceType = "custom-url"
-	providerSourceTypeConfigMap providerSourceType = "config-map"
+	providerSourceTypeBuiltin   = util.ProviderSourceTypeBuiltin
+	providerSourceTypeCustomURL = util.ProviderSourceTypeCustomURL
+	providerSourceTypeConfigMap = util.ProviderSourceTypeConfigMap
 )
 
 // upgradeItem defines a possible upgrade target for a provider in the management cluster.
+// Contract is the API Version of Cluster API (contract) of the next version, or of the installed version
+// for externally managed providers. Providers fetched from ConfigMaps are externally managed: their
+// versions are not published in a repository, so the plugin doesn't upgrade them.
 type upgradeItem struct {
-	Name           string
-	Namespace      string
-	Type           string
-	Source         providerSource
-	SourceType     providerSourceType
-	CurrentVersion string
-	NextVersion    string
+	Name              string             `json:"name"`
+	Namespace         string             `json:"namespace"`
+	Type              string             `json:"type"`
+	Source            providerSource     `json:"source"`
+	SourceType        providerSourceType `json:"sourceType"`
+	CurrentVersion    string             `json:"currentVersion"`
+	NextVersion       string             `json:"nextVersion"`
+	Contract          string             `json:"contract"`
+	ExternallyManaged bool               `json:"externallyManaged"`
 }
 
 var upgradePlanOpts = &upgradePlanOptions{}
@@ -103,7 +130,13 @@ var upgradePlanCmd = &cobra.Command{
 
 	Example: Examples(`
 		# Gets the recommended target versions for upgrading Cluster API providers.
-		capioperator upgrade plan`),
+		capioperator upgrade plan
+
+		# Prints the upgrade plan in JSON format.
+		capioperator upgrade plan -o json
+
+		# Exits with code 2 if any of the components can be upgraded.
+		capioperator upgrade plan --exit-code`),
 
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runUpgradePlan()
@@ -115,11 +148,19 @@ func init() {
 		"Path to the kubeconfig file to use for accessing the management cluster. If empty, default discovery rules apply.")
 	upgradePlanCmd.Flags().StringVar(&upgradePlanOpts.kubeconfigContext, "kubeconfig-context", "",
 		"Context to be used within the kubeconfig file. If empty, current context will be used.")
+	upgradePlanCmd.Flags().StringVarP(&upgradePlanOpts.output, "output", "o", "",
+		"Output format; available options are 'yaml' and 'json'. If empty, the plan is printed as a table.")
+	upgradePlanCmd.Flags().BoolVar(&upgradePlanOpts.exitCode, "exit-code", false,
+		fmt.Sprintf("Exit with code %d if any of the components can be upgraded.", upgradeAvailableExitCode))
 }
 
 func runUpgradePlan() error {
 	ctx := context.Background()
 
+	if upgradePlanOpts.output != "" && upgradePlanOpts.output != "yaml" && upgradePlanOpts.output != "json" {
+		return errors.Errorf("invalid output format: %s", upgradePlanOpts.output)
+	}
+
 	if upgradePlanOpts.kubeconfig == "" {
 		upgradePlanOpts.kubeconfig = GetKubeconfigLocation()
 	}
@@ -164,14 +205,76 @@ func runUpgradePlan() error {
 		return err
 	}
 
+	// ensure provider are sorted consistently (by Type, Name, Namespace).
+	sortUpgradeItems(upgradePlan)
+
+	output := newUpgradePlanOutput(certManUpgradePlan, capiOperatorUpgradePlan, upgradePlan)
+
+	switch upgradePlanOpts.output {
+	case "":
+		if err := printUpgradePlan(upgradePlan); err != nil {
+			return err
+		}
+	case "yaml":
+		y, err := yaml.Marshal(&output)
+		if err != nil {
+			return err
+		}
+
+		fmt.Print(string(y))
+	case "json":
+		y, err := json.MarshalIndent(&output, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(string(y))
+	}
+
+	if upgradePlanOpts.exitCode && output.UpgradeAvailable {
+		return &exitCodeError{code: upgradeAvailableExitCode, message: "upgrades are available"}
+	}
+
+	return nil
+}
+
+// newUpgradePlanOutput combines the cert-manager, CAPI operator and providers upgrade plans.
+func newUpgradePlanOutput(certManager certManagerUpgradePlan, capiOperator capiOperatorUpgradePlan, plan upgradePlan) upgradePlanOutput {
+	output := upgradePlanOutput{
+		CertManager:  certManager,
+		CAPIOperator: capiOperator,
+		Contract:     plan.Contract,
+		Providers:    plan.Providers,
+	}
+
+	if output.Providers == nil {
+		output.Providers = []upgradeItem{}
+	}
+
+	output.UpgradeAvailable = (certManager.ShouldUpgrade && !certManager.ExternallyManaged) ||
+		(capiOperator.ShouldUpgrade && !capiOperator.ExternallyManaged)
+
+	for _, item := range plan.Providers {
+		if isUpgradeAvailable(item) {
+			output.UpgradeAvailable = true
+		}
+	}
+
+	return output
+}
+
+// isUpgradeAvailable returns true if the provider can be upgraded to a newer version.
+func isUpgradeAvailable(item upgradeItem) bool {
+	return !item.ExternallyManaged && item.NextVersion != "" && item.NextVersion != item.CurrentVersion
+}
+
+// printUpgradePlan prints the providers upgrade plan as a table.
+func printUpgradePlan(upgradePlan upgradePlan) error {
 	if len(upgradePlan.Providers) == 0 {
 		log.Info("There are no providers in the cluster. Please use capioperator init to initialize a Cluster API management cluster.")
 		return nil
 	}
 
-	// ensure provider are sorted consistently (by Type, Name, Namespace).
-	sortUpgradeItems(upgradePlan)
-
 	upgradeAvailable := false
 
 	fmt.Printf("\nLatest release available for the %s API Version of Cluster API (contract):\n\n", upgradePlan.Contract)
@@ -183,11 +286,16 @@ func runUpgradePlan() error {
 	}
 
 	for _, upgradeItem := range upgradePlan.Providers {
-		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", upgradeItem.Name, upgradeItem.Namespace, upgradeItem.Type, upgradeItem.CurrentVersion, prettifyTargetVersion(upgradeItem.NextVersion)); err != nil {
+		nextVersion := prettifyTargetVersion(upgradeItem.NextVersion)
+		if upgradeItem.ExternallyManaged {
+			nextVersion = "Externally managed"
+		}
+
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", upgradeItem.Name, upgradeItem.Namespace, upgradeItem.Type, upgradeItem.CurrentVersion, nextVersion); err != nil {
 			return err
 		}
 
-		if upgradeItem.NextVersion != "" {
+		if isUpgradeAvailable(upgradeItem) {
 			upgradeAvailable = true
 		}
 	}
@@ -300,7 +408,7 @@ func isCAPIOperatorExternallyManaged(deployment *appsv1.Deployment) bool {
 }
 
 func planUpgrade(ctx context.Context, client ctrlclient.Client) (upgradePlan, error) {
-	genericProviders, contract, err := getInstalledProviders(ctx, client)
+	genericProviders, contract, err := util.GetInstalledProviders(ctx, client)
 	if err != nil {
 		return upgradePlan{}, fmt.Errorf("cannot get installed providers: %w", err)
 	}
@@ -308,156 +416,50 @@ func planUpgrade(ctx context.Context, client ctrlclient.Client) (upgradePlan, er
 	upgradeItems := []upgradeItem{}
 
 	for _, genericProvider := range genericProviders {
-		providerFetchSource, providerSourceType, err := getProviderFetchConfig(ctx, genericProvider)
-		if err != nil {
-			return upgradePlan{}, fmt.Errorf("cannot get provider fetch URL: %w", err)
-		}
-
-		// TODO: ignore configmap source type for now.
-		if providerSourceType == providerSourceTypeConfigMap {
-			continue
-		}
-
-		configClient, err := configclient.New(ctx, "")
-		if err != nil {
-			return upgradePlan{}, fmt.Errorf("cannot create config client: %w", err)
-		}
-
-		providerConfig := configclient.NewProvider(capiOperatorProviderName, string(providerFetchSource), clusterctlv1.ProviderTypeUnknown)
-
-		repo, err := util.RepositoryFactory(ctx, providerConfig, configClient.Variables())
+		item, err := planProviderUpgrade(ctx, genericProvider)
 		if err != nil {
-			return upgradePlan{}, fmt.Errorf("cannot create repository: %w", err)
+			return upgradePlan{}, err
 		}
 
-		upgradeItems = append(upgradeItems, upgradeItem{
-			Name:           genericProvider.ProviderName(),
-			Namespace:      genericProvider.GetNamespace(),
-			Type:           genericProvider.GetType(),
-			CurrentVersion: genericProvider.GetSpec().Version,
-			NextVersion:    repo.DefaultVersion(),
-			Source:         providerFetchSource,
-			SourceType:     providerSourceType,
-		})
+		upgradeItems = append(upgradeItems, item)
 	}
 
 	return upgradePlan{Contract: contract, Providers: upgradeItems}, nil
 }
 
-func getInstalledProviders(ctx context.Context, client ctrlclient.Client) ([]operatorv1.GenericProvider, string, error) {
-	// Iterate through installed providers and create a list of upgrade plans.
-	genericProviders := []operatorv1.GenericProvider{}
-
-	contract := "v1beta2"
-
-	// Get Core Providers.
-	var coreProviderList operatorv1.CoreProviderList
-
-	if err := client.List(ctx, &coreProviderList); err != nil {
-		return nil, "", fmt.Errorf("cannot get a list of core providers from the server: %w", err)
-	}
-
-	if len(coreProviderList.Items) == 1 && coreProviderList.Items[0].Status.Contract != nil {
-		contract = *coreProviderList.Items[0].Status.Contract
-	}
-
-	for i := range coreProviderList.Items {
-		genericProviders = append(genericProviders, &coreProviderList.Items[i])
-	}
-
-	// Get Bootstrap Providers.
-	var bootstrapProviderList operatorv1.BootstrapProviderList
-
-	if err := client.List(ctx, &bootstrapProviderList); err != nil {
-		return nil, "", fmt.Errorf("cannot get a list of bootstrap providers from the server: %w", err)
-	}
-
-	for i := range bootstrapProviderList.Items {
-		genericProviders = append(genericProviders, &bootstrapProviderList.Items[i])
-	}
-
-	// Get Control Plane Providers.
-	var controlPlaneProviderList operatorv1.ControlPlaneProviderList
-
-	if err := client.List(ctx, &controlPlaneProviderList); err != nil {
-		return nil, "", fmt.Errorf("cannot get a list of control plane providers from the server: %w", err)
-	}
-
-	for i := range controlPlaneProviderList.Items {
-		genericProviders = append(genericProviders, &controlPlaneProviderList.Items[i])
-	}
-
-	// Get Infrastructure Providers.
-	var infrastructureProviderList operatorv1.InfrastructureProviderList
-
-	if err := client.List(ctx, &infrastructureProviderList); err != nil {
-		return nil, "", fmt.Errorf("cannot get a list of infrastructure providers from the server: %w", err)
-	}
-
-	for i := range infrastructureProviderList.Items {
-		genericProviders = append(genericProviders, &infrastructureProviderList.Items[i])
-	}
-
-	// Get Addon Providers.
-	var addonProviderList operatorv1.AddonProviderList
-
-	if err := client.List(ctx, &addonProviderList); err != nil {
-		return nil, "", fmt.Errorf("cannot get a list of addon providers from the server: %w", err)
-	}
-
-	for i := range addonProviderList.Items {
-		genericProviders = append(genericProviders, &addonProviderList.Items[i])
-	}
-
-	// Get IPAM Providers.
-	var ipamProviderList operatorv1.IPAMProviderList
-
-	if err := client.List(ctx, &ipamProviderList); err != nil {
-		return nil, "", fmt.Errorf("cannot get a list of ipam providers from the server: %w", err)
-	}
-
-	for i := range ipamProviderList.Items {
-		genericProviders = append(genericProviders, &ipamProviderList.Items[i])
-	}
-
-	// Get Runtime Extension Providers.
-	var runtimeExtensionProviderList operatorv1.RuntimeExtensionProviderList
-
-	if err := client.List(ctx, &runtimeExtensionProviderList); err != nil {
-		return nil, "", fmt.Errorf("cannot get a list of runtime extension providers from the server: %w", err)
+// planProviderUpgrade returns the latest version of the provider available in its repository, with its
+// API Version of Cluster API (contract). Providers fetched from ConfigMaps are returned as externally managed.
+func planProviderUpgrade(ctx context.Context, genericProvider operatorv1.GenericProvider) (upgradeItem, error) {
+	providerFetchSource, providerSourceType, err := util.GetProviderFetchURL(ctx, genericProvider)
+	if err != nil {
+		return upgradeItem{}, fmt.Errorf("cannot get provider fetch URL: %w", err)
 	}
 
-	for i := range runtimeExtensionProviderList.Items {
-		genericProviders = append(genericProviders, &runtimeExtensionProviderList.Items[i])
+	item := upgradeItem{
+		Name:           genericProvider.ProviderName(),
+		Namespace:      genericProvider.GetNamespace(),
+		Type:           genericProvider.GetType(),
+		CurrentVersion: genericProvider.GetSpec().Version,
+		Source:         providerSource(providerFetchSource),
+		SourceType:     providerSourceType,
 	}
 
-	return genericProviders, contract, nil
-}
+	if providerSourceType == providerSourceTypeConfigMap {
+		item.ExternallyManaged = true
+		item.Contract = ptr.Deref(genericProvider.GetStatus().Contract, "")
 
-func getProviderFetchConfig(ctx context.Context, genericProvider operatorv1.GenericProvider) (providerSource, providerSourceType, error) {
-	// Check that fetch url was provider by user.
-	spec := genericProvider.GetSpec()
-	if spec.FetchConfig != nil && spec.FetchConfig.URL != "" {
-		return providerSource(spec.FetchConfig.URL), providerSourceTypeCustomURL, nil
+		return item, nil
 	}
 
-	// Get fetch url from clusterctl configuration.
-	// TODO: support custom clusterctl configuration.
-	configClient, err := configclient.New(ctx, "")
+	item.NextVersion, err = util.GetLatestProviderVersion(ctx, capiOperatorProviderName, providerFetchSource)
 	if err != nil {
-		return "", "", err
+		return upgradeItem{}, err
 	}
 
-	providerConfig, err := configClient.Providers().Get(genericProvider.ProviderName(), util.ClusterctlProviderType(genericProvider))
+	item.Contract, err = util.GetProviderVersionContract(ctx, capiOperatorProviderName, providerFetchSource, item.NextVersion)
 	if err != nil {
-		// TODO: implement support of fetching data from config maps
-		// This is a temporary fix for providers installed from config maps
-		if strings.Contains(err.Error(), "failed to get configuration") {
-			return "", providerSourceTypeConfigMap, nil
-		}
-
-		return "", "", err
+		return upgradeItem{}, fmt.Errorf("cannot get the contract of provider %s/%s: %w", item.Namespace, item.Name, err)
 	}
 
-	return providerSource(providerConfig.URL()), providerSourceTypeBuiltin, nil
+	return item, nil
 }
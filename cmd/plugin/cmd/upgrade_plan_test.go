@@ -17,9 +17,13 @@ limitations under the License.
 package cmd
 
 import (
+	"context"
+	"encoding/json"
 	"testing"
 
 	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -171,9 +175,144 @@ func TestUpgradePlan(t *testing.T) {
 				g.Expect(provider.CurrentVersion).To(Equal(tt.wantedUpgradePlan.Providers[i].CurrentVersion))
 				g.Expect(provider.Source).To(Equal(tt.wantedUpgradePlan.Providers[i].Source))
 				g.Expect(provider.SourceType).To(Equal(tt.wantedUpgradePlan.Providers[i].SourceType))
+				g.Expect(provider.Contract).NotTo(BeEmpty())
+				g.Expect(provider.ExternallyManaged).To(BeFalse())
 			}
 
 			g.Expect(env.CleanupAndWait(ctx, resources...)).To(Succeed())
 		})
 	}
 }
+
+func TestNewUpgradePlanOutput(t *testing.T) {
+	tests := []struct {
+		name                   string
+		certManager            certManagerUpgradePlan
+		capiOperator           capiOperatorUpgradePlan
+		plan                   upgradePlan
+		wantedUpgradeAvailable bool
+	}{
+		{
+			name: "everything is up to date",
+			plan: upgradePlan{
+				Contract: "v1beta2",
+				Providers: []upgradeItem{
+					{Name: "cluster-api", Type: "core", CurrentVersion: "v1.12.0", NextVersion: "v1.12.0"},
+				},
+			},
+		},
+		{
+			name: "provider can be upgraded",
+			plan: upgradePlan{
+				Contract: "v1beta2",
+				Providers: []upgradeItem{
+					{Name: "cluster-api", Type: "core", CurrentVersion: "v1.11.0", NextVersion: "v1.12.0"},
+				},
+			},
+			wantedUpgradeAvailable: true,
+		},
+		{
+			name:                   "cert-manager can be upgraded",
+			certManager:            certManagerUpgradePlan{From: "v1.16.0", To: "v1.17.0", ShouldUpgrade: true},
+			plan:                   upgradePlan{Contract: "v1beta2"},
+			wantedUpgradeAvailable: true,
+		},
+		{
+			name: "externally managed provider is ignored",
+			plan: upgradePlan{
+				Contract: "v1beta2",
+				Providers: []upgradeItem{
+					{Name: "my-provider", Type: "infrastructure", CurrentVersion: "v0.1.0", NextVersion: "v0.2.0", ExternallyManaged: true},
+				},
+			},
+		},
+		{
+			name:         "externally managed CAPI operator is ignored",
+			capiOperator: capiOperatorUpgradePlan{From: "v0.20.0", To: "v0.21.0", ShouldUpgrade: true, ExternallyManaged: true},
+			plan:         upgradePlan{Contract: "v1beta2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			output := newUpgradePlanOutput(tt.certManager, tt.capiOperator, tt.plan)
+			g.Expect(output.UpgradeAvailable).To(Equal(tt.wantedUpgradeAvailable))
+			g.Expect(output.Contract).To(Equal(tt.plan.Contract))
+			g.Expect(output.Providers).NotTo(BeNil())
+		})
+	}
+}
+
+func TestUpgradePlanOutputSchema(t *testing.T) {
+	g := NewWithT(t)
+
+	output := newUpgradePlanOutput(
+		certManagerUpgradePlan{From: "v1.16.0", To: "v1.17.0", ShouldUpgrade: true},
+		capiOperatorUpgradePlan{From: "v0.20.0", To: "v0.20.0", ExternallyManaged: true},
+		upgradePlan{
+			Contract: "v1beta2",
+			Providers: []upgradeItem{{
+				Name:           "cluster-api",
+				Namespace:      "capi-system",
+				Type:           "core",
+				Source:         "https://github.com/kubernetes-sigs/cluster-api/releases/latest/core-components.yaml",
+				SourceType:     providerSourceTypeBuiltin,
+				CurrentVersion: "v1.11.0",
+				NextVersion:    "v1.12.0",
+				Contract:       "v1beta2",
+			}},
+		},
+	)
+
+	data, err := json.Marshal(output)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(data).To(MatchJSON(`{
+		"certManager": {"externallyManaged": false, "from": "v1.16.0", "to": "v1.17.0", "shouldUpgrade": true},
+		"capiOperator": {"externallyManaged": true, "from": "v0.20.0", "to": "v0.20.0", "shouldUpgrade": false},
+		"contract": "v1beta2",
+		"providers": [{
+			"name": "cluster-api",
+			"namespace": "capi-system",
+			"type": "core",
+			"source": "https://github.com/kubernetes-sigs/cluster-api/releases/latest/core-components.yaml",
+			"sourceType": "builtin",
+			"currentVersion": "v1.11.0",
+			"nextVersion": "v1.12.0",
+			"contract": "v1beta2",
+			"externallyManaged": false
+		}],
+		"upgradeAvailable": true
+	}`))
+}
+
+func TestPlanProviderUpgradeFromConfigMap(t *testing.T) {
+	g := NewWithT(t)
+
+	provider := &operatorv1.InfrastructureProvider{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-provider", Namespace: "my-provider-system"},
+		Spec: operatorv1.InfrastructureProviderSpec{ProviderSpec: operatorv1.ProviderSpec{
+			Version: "v0.1.0",
+			FetchConfig: &operatorv1.FetchConfiguration{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"provider": "my-provider"}},
+			},
+		}},
+		Status: operatorv1.InfrastructureProviderStatus{ProviderStatus: operatorv1.ProviderStatus{
+			Contract: ptr.To("v1beta2"),
+		}},
+	}
+
+	item, err := planProviderUpgrade(context.Background(), provider)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(item).To(Equal(upgradeItem{
+		Name:              "my-provider",
+		Namespace:         "my-provider-system",
+		Type:              "infrastructure",
+		SourceType:        providerSourceTypeConfigMap,
+		CurrentVersion:    "v0.1.0",
+		Contract:          "v1beta2",
+		ExternallyManaged: true,
+	}))
+	g.Expect(isUpgradeAvailable(item)).To(BeFalse())
+}
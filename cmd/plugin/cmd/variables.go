@@ -0,0 +1,265 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/yaml"
+
+	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
+	providercontroller "sigs.k8s.io/cluster-api-operator/internal/controller"
+	"sigs.k8s.io/cluster-api-operator/internal/processor"
+)
+
+type variablesOptions struct {
+	coreProvider             string
+	bootstrapProvider        string
+	controlPlaneProvider     string
+	infrastructureProvider   string
+	ipamProvider             string
+	runtimeExtensionProvider string
+	addonProvider            string
+	artifactURL              string
+	templateProcessor        string
+	output                   string
+	registry                 ociRegistryFlags
+}
+
+// providerVariable is a variable of the provider components, printed by the variables command.
+type providerVariable struct {
+	Name     string  `json:"name"`
+	Required bool    `json:"required"`
+	Default  *string `json:"default,omitempty"`
+}
+
+var variablesOpts = &variablesOptions{}
+
+var variablesCmd = &cobra.Command{
+	Use:     "variables",
+	GroupID: groupOther,
+	Short:   "Print the variables required by a provider version",
+	Long: LongDesc(`
+		Print the variables used by the components of a provider version.
+
+		Required variables have no default value, and must be set in the provider configuration secret,
+		otherwise the provider fails the VariablesResolved check.
+
+		The components are fetched from the provider repository, or from the OCI artifact or GitHub/GitLab
+		release given with the artifact URL.`),
+	Example: Examples(`
+		# Print the variables of the latest version of the AWS infrastructure provider.
+		capioperator variables --infrastructure aws
+
+		# Print the variables of a specific version of the AWS infrastructure provider.
+		capioperator variables --infrastructure aws::v2.3.0
+
+		# Print the variables of a provider from an OCI artifact, as processed by the envsubst template processor.
+		capioperator variables --infrastructure aws::v2.3.0 -u ttl.sh/infrastructure-provider --template-processor Envsubst
+
+		# Print the variables in YAML format.
+		capioperator variables --core cluster-api -o yaml`),
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runVariables()
+	},
+}
+
+func init() {
+	variablesCmd.Flags().StringVar(&variablesOpts.coreProvider, "core", "",
+		"Core provider version (e.g. cluster-api:v1.1.5) to print the variables of.")
+	variablesCmd.Flags().StringVarP(&variablesOpts.infrastructureProvider, "infrastructure", "i", "",
+		"Infrastructure provider and version (e.g. aws:v0.5.0) to print the variables of.")
+	variablesCmd.Flags().StringVarP(&variablesOpts.bootstrapProvider, "bootstrap", "b", "",
+		"Bootstrap provider and version (e.g. kubeadm:v1.1.5) to print the variables of.")
+	variablesCmd.Flags().StringVarP(&variablesOpts.controlPlaneProvider, "control-plane", "c", "",
+		"Control plane provider and version (e.g. kubeadm:v1.1.5) to print the variables of.")
+	variablesCmd.Flags().StringVar(&variablesOpts.ipamProvider, "ipam", "",
+		"IPAM provider and version (e.g. infoblox:v0.0.1) to print the variables of.")
+	variablesCmd.Flags().StringVar(&variablesOpts.runtimeExtensionProvider, "runtime-extension", "",
+		"Runtime extension provider and version (e.g. my-extension:v0.0.1) to print the variables of.")
+	variablesCmd.Flags().StringVar(&variablesOpts.addonProvider, "addon", "",
+		"Add-on provider and version (e.g. helm:v0.1.0) to print the variables of.")
+	variablesCmd.Flags().StringVarP(&variablesOpts.artifactURL, "artifact-url", "u", "",
+		"The URL to OCI artifact or GitHub/GitLab release, to collect component manifests from. If unspecified, the provider repository is used.")
+	variablesCmd.Flags().StringVar(&variablesOpts.templateProcessor, "template-processor", "",
+		"The template processor of the provider components: Simple, Envsubst or GoTemplate. If unspecified, the Simple processor is used.")
+	variablesCmd.Flags().StringVarP(&variablesOpts.output, "output", "o", "",
+		"Output format. Valid values: [yaml, json]. If unspecified, the variables are printed as a table.")
+	addOCIRegistryFlags(variablesCmd, &variablesOpts.registry)
+
+	RootCmd.AddCommand(variablesCmd)
+}
+
+func runVariables() error {
+	ctx := context.Background()
+
+	if variablesOpts.output != "" && variablesOpts.output != "yaml" && variablesOpts.output != "json" {
+		return fmt.Errorf("invalid output format: %s", variablesOpts.output)
+	}
+
+	providerType, providerInput, err := variablesProvider(variablesOpts)
+	if err != nil {
+		return err
+	}
+
+	configMap, err := variablesConfigMap(ctx, providerType, providerInput, variablesOpts)
+	if err != nil {
+		return fmt.Errorf("cannot fetch the components of provider %q: %w", providerInput, err)
+	}
+
+	components, err := providercontroller.GetComponentsData(*configMap)
+	if err != nil {
+		return err
+	}
+
+	variables, err := componentsVariables([]byte(components), operatorv1.TemplateProcessor(variablesOpts.templateProcessor))
+	if err != nil {
+		return err
+	}
+
+	return printVariables(os.Stdout, variables, variablesOpts.output)
+}
+
+// variablesProvider returns the type and the input of the provider selected with the flags.
+func variablesProvider(opts *variablesOptions) (clusterctlv1.ProviderType, string, error) {
+	providers := map[clusterctlv1.ProviderType]string{
+		clusterctlv1.CoreProviderType:             opts.coreProvider,
+		clusterctlv1.BootstrapProviderType:        opts.bootstrapProvider,
+		clusterctlv1.ControlPlaneProviderType:     opts.controlPlaneProvider,
+		clusterctlv1.InfrastructureProviderType:   opts.infrastructureProvider,
+		clusterctlv1.IPAMProviderType:             opts.ipamProvider,
+		clusterctlv1.RuntimeExtensionProviderType: opts.runtimeExtensionProvider,
+		clusterctlv1.AddonProviderType:            opts.addonProvider,
+	}
+
+	var (
+		providerType  clusterctlv1.ProviderType
+		providerInput string
+	)
+
+	for t, input := range providers {
+		if input == "" {
+			continue
+		}
+
+		if providerInput != "" {
+			return "", "", fmt.Errorf("only one provider can be specified")
+		}
+
+		providerType, providerInput = t, input
+	}
+
+	if providerInput == "" {
+		return "", "", fmt.Errorf("a provider must be specified")
+	}
+
+	return providerType, providerInput, nil
+}
+
+// variablesConfigMap returns the ConfigMap with the components of the provider, fetched from the artifact URL
+// if set, or from the provider repository.
+func variablesConfigMap(ctx context.Context, providerType clusterctlv1.ProviderType, providerInput string, opts *variablesOptions) (*corev1.ConfigMap, error) {
+	if opts.artifactURL != "" {
+		return templateConfigMap(ctx, providerType, opts.artifactURL, providerInput, "", opts.registry)
+	}
+
+	provider, err := templateGenericProvider(providerType, providerInput, "", "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	return providerConfigMap(ctx, provider)
+}
+
+// componentsVariables returns the variables of the components, as inspected by the template processor.
+func componentsVariables(components []byte, templateProcessor operatorv1.TemplateProcessor) ([]providerVariable, error) {
+	p, err := processor.New(templateProcessor)
+	if err != nil {
+		return nil, err
+	}
+
+	variableMap, err := p.GetVariableMap(components)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse the variables of the components: %w", err)
+	}
+
+	variables := make([]providerVariable, 0, len(variableMap))
+
+	for _, name := range slices.Sorted(maps.Keys(variableMap)) {
+		variables = append(variables, providerVariable{
+			Name:     name,
+			Required: variableMap[name] == nil,
+			Default:  variableMap[name],
+		})
+	}
+
+	return variables, nil
+}
+
+// printVariables prints the variables in the output format, or as a table.
+func printVariables(w io.Writer, variables []providerVariable, output string) error {
+	switch output {
+	case "yaml":
+		out, err := yaml.Marshal(variables)
+		if err != nil {
+			return err
+		}
+
+		_, err = w.Write(out)
+
+		return err
+	case "json":
+		out, err := json.MarshalIndent(variables, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintln(w, string(out))
+
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 10, 4, 3, ' ', 0)
+
+	if _, err := fmt.Fprintln(tw, "NAME\tREQUIRED\tDEFAULT"); err != nil {
+		return err
+	}
+
+	for _, variable := range variables {
+		defaultValue := ""
+		if variable.Default != nil {
+			defaultValue = *variable.Default
+		}
+
+		if _, err := fmt.Fprintf(tw, "%s\t%t\t%s\n", variable.Name, variable.Required, defaultValue); err != nil {
+			return err
+		}
+	}
+
+	return tw.Flush()
+}
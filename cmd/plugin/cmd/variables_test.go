@@ -0,0 +1,136 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+
+	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
+)
+
+func TestVariablesProvider(t *testing.T) {
+	testCases := []struct {
+		name          string
+		opts          *variablesOptions
+		expectedType  clusterctlv1.ProviderType
+		expectedInput string
+		expectedErr   bool
+	}{
+		{
+			name:          "infrastructure provider",
+			opts:          &variablesOptions{infrastructureProvider: "aws::v2.3.0"},
+			expectedType:  clusterctlv1.InfrastructureProviderType,
+			expectedInput: "aws::v2.3.0",
+		},
+		{
+			name:        "no provider",
+			opts:        &variablesOptions{},
+			expectedErr: true,
+		},
+		{
+			name:        "several providers",
+			opts:        &variablesOptions{coreProvider: "cluster-api", bootstrapProvider: "kubeadm"},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			providerType, providerInput, err := variablesProvider(tc.opts)
+			if tc.expectedErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(providerType).To(Equal(tc.expectedType))
+			g.Expect(providerInput).To(Equal(tc.expectedInput))
+		})
+	}
+}
+
+func TestComponentsVariables(t *testing.T) {
+	components := []byte(`args:
+- --feature-gates=MachinePool=${EXP_MACHINE_POOL:=false}
+- --region=${AWS_REGION}
+`)
+
+	testCases := []struct {
+		name              string
+		templateProcessor operatorv1.TemplateProcessor
+		output            string
+		expected          string
+	}{
+		{
+			name: "table",
+			expected: "NAME               REQUIRED   DEFAULT\n" +
+				"AWS_REGION         true       \n" +
+				"EXP_MACHINE_POOL   false      false\n",
+		},
+		{
+			name:              "yaml",
+			templateProcessor: operatorv1.EnvsubstTemplateProcessor,
+			output:            "yaml",
+			expected: `- name: AWS_REGION
+  required: true
+- default: "false"
+  name: EXP_MACHINE_POOL
+  required: false
+`,
+		},
+		{
+			name:   "json",
+			output: "json",
+			expected: `[
+  {
+    "name": "AWS_REGION",
+    "required": true
+  },
+  {
+    "name": "EXP_MACHINE_POOL",
+    "required": false,
+    "default": "false"
+  }
+]
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			variables, err := componentsVariables(components, tc.templateProcessor)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			buf := &bytes.Buffer{}
+			g.Expect(printVariables(buf, variables, tc.output)).To(Succeed())
+			g.Expect(buf.String()).To(Equal(tc.expected))
+		})
+	}
+
+	g := NewWithT(t)
+
+	_, err := componentsVariables(components, "Jsonnet")
+	g.Expect(err).To(HaveOccurred())
+}
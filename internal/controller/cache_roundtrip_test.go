@@ -104,7 +104,7 @@ func TestApplyFromCache_HashMismatchSkips(t *testing.T) {
 			Name:      "cluster-api",
 			Namespace: ns.Name,
 			Annotations: map[string]string{
-				appliedSpecHashAnnotation: "stale-hash",
+				AppliedSpecHashAnnotation: "stale-hash",
 			},
 		},
 		TypeMeta: metav1.TypeMeta{
@@ -126,7 +126,7 @@ func TestApplyFromCache_HashMismatchSkips(t *testing.T) {
 			Name:      cacheName,
 			Namespace: ns.Name,
 			Annotations: map[string]string{
-				appliedSpecHashAnnotation: "different-hash",
+				AppliedSpecHashAnnotation: "different-hash",
 			},
 		},
 		Data: map[string][]byte{
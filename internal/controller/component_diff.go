@@ -0,0 +1,251 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
+	"sigs.k8s.io/cluster-api-operator/util"
+)
+
+// ComponentDiff is the change applying its manifest makes to a provider component.
+type ComponentDiff struct {
+	// Manifest is the rendered manifest of the component, or the live component if it is deleted.
+	Manifest unstructured.Unstructured
+
+	// Created is true if the component doesn't exist yet.
+	Created bool
+
+	// Deleted is true if the component is installed but not rendered anymore, and is deleted by the upgrade.
+	Deleted bool
+
+	// Diff is the unified diff between the live and the rendered component.
+	Diff string
+}
+
+// ComponentsDiff computes the changes applying the manifests of the provider with server-side apply makes to the
+// live components, with a dry-run. The live components of the provider which are not in the manifests are returned
+// as deleted. The unchanged components are not returned.
+func ComponentsDiff(ctx context.Context, cl client.Client, provider operatorv1.GenericProvider, manifests []unstructured.Unstructured) ([]ComponentDiff, error) {
+	diffs := []ComponentDiff{}
+	rendered := map[string]bool{}
+
+	for i := range manifests {
+		manifest := manifests[i]
+		rendered[componentKey(manifest)] = true
+
+		live, applied, err := dryRunApply(ctx, cl, cl, manifest)
+		if err != nil {
+			return nil, err
+		}
+
+		if live == nil {
+			diff, err := unifiedDiff(ComponentName(manifest), nil, manifest.Object)
+			if err != nil {
+				return nil, err
+			}
+
+			diffs = append(diffs, ComponentDiff{Manifest: manifest, Created: true, Diff: diff})
+
+			continue
+		}
+
+		diff, err := unifiedDiff(ComponentName(manifest), live.Object, applied.Object)
+		if err != nil {
+			return nil, err
+		}
+
+		if diff != "" {
+			diffs = append(diffs, ComponentDiff{Manifest: manifest, Diff: diff})
+		}
+	}
+
+	deleted, err := deletedComponents(ctx, cl, provider, rendered)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, live := range deleted {
+		diff, err := unifiedDiff(ComponentName(live), live.Object, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		diffs = append(diffs, ComponentDiff{Manifest: live, Deleted: true, Diff: diff})
+	}
+
+	return diffs, nil
+}
+
+// deletedComponents returns the live components of the provider which are not rendered anymore, and which
+// an upgrade deletes. Like clusterctl upgrades, the CRDs, the namespaces and the cluster-wide components not
+// belonging to the provider instance are preserved.
+func deletedComponents(ctx context.Context, cl client.Client, provider operatorv1.GenericProvider, rendered map[string]bool) ([]unstructured.Unstructured, error) {
+	proxy := &controllerProxy{ctrlClient: clientProxy{Client: cl}}
+
+	labels := map[string]string{
+		clusterctlv1.ClusterctlLabel: "",
+		clusterv1.ProviderNameLabel:  clusterctlv1.ManifestLabel(provider.ProviderName(), util.ClusterctlProviderType(provider)),
+	}
+
+	live, err := proxy.ListResources(ctx, labels, provider.GetNamespace())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the components of the provider: %w", err)
+	}
+
+	deleted := []unstructured.Unstructured{}
+
+	for _, component := range live {
+		kind := component.GetKind()
+
+		switch {
+		case rendered[componentKey(component)]:
+			continue
+		case kind == customResourceDefinitionKind || kind == namespaceKind:
+			continue
+		case component.GetNamespace() == "" && kind != validatingWebhookConfigurationKind && kind != mutatingWebhookConfigurationKind &&
+			!strings.HasPrefix(component.GetName(), provider.GetNamespace()+"-"):
+			continue
+		}
+
+		deleted = append(deleted, component)
+	}
+
+	return deleted, nil
+}
+
+// componentKey identifies a component by its group, kind, namespace and name, regardless of its API version.
+func componentKey(component unstructured.Unstructured) string {
+	gk := schema.FromAPIVersionAndKind(component.GetAPIVersion(), component.GetKind()).GroupKind()
+
+	return gk.String() + "/" + component.GetNamespace() + "/" + component.GetName()
+}
+
+// dryRunApply returns the live component, and the component as applied from the manifest with server-side apply,
+// with the defaults of the API server. The live component is nil if it doesn't exist yet, in which case the
+// manifest is not applied.
+func dryRunApply(ctx context.Context, cl client.Client, reader client.Reader, manifest unstructured.Unstructured) (live, applied *unstructured.Unstructured, err error) {
+	live = &unstructured.Unstructured{}
+	live.SetGroupVersionKind(manifest.GroupVersionKind())
+
+	if err := reader.Get(ctx, client.ObjectKeyFromObject(&manifest), live); err != nil {
+		// The kind of the component doesn't exist if its CRD is not installed yet.
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil, nil, nil
+		}
+
+		return nil, nil, fmt.Errorf("failed to get %s: %w", ComponentName(manifest), err)
+	}
+
+	applied = manifest.DeepCopy()
+	if err := cl.Patch(ctx, applied, client.Apply, client.ForceOwnership, client.FieldOwner(cacheOwner), client.DryRunAll); err != nil {
+		return nil, nil, fmt.Errorf("failed to dry-run apply %s: %w", ComponentName(manifest), err)
+	}
+
+	return live, applied, nil
+}
+
+// unifiedDiff returns the unified diff between the YAML of the live and the applied object, without the status
+// and the metadata maintained by the API server. The live object is nil if it is created, and the applied object
+// is nil if it is deleted. The diff is empty if the objects don't differ.
+func unifiedDiff(name string, live, applied map[string]any) (string, error) {
+	from, err := marshalComponent(name, live)
+	if err != nil {
+		return "", err
+	}
+
+	to, err := marshalComponent(name, applied)
+	if err != nil {
+		return "", err
+	}
+
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(from)),
+		B:        difflib.SplitLines(string(to)),
+		FromFile: name + " (live)",
+		ToFile:   name + " (rendered)",
+		Context:  3,
+	})
+}
+
+// marshalComponent returns the YAML of the object without the server fields, or nothing if the object is nil.
+func marshalComponent(name string, obj map[string]any) ([]byte, error) {
+	if obj == nil {
+		return nil, nil
+	}
+
+	data, err := yaml.Marshal(withoutServerFields(obj))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s: %w", name, err)
+	}
+
+	return data, nil
+}
+
+// withoutServerFields returns a copy of the object without the status and the metadata maintained by the API server.
+// Empty objects are removed too, as they are equivalent to unset fields.
+func withoutServerFields(obj map[string]any) map[string]any {
+	obj = runtime.DeepCopyJSON(obj)
+
+	delete(obj, "status")
+
+	for _, field := range driftIgnoredMetadataFields {
+		unstructured.RemoveNestedField(obj, "metadata", field)
+	}
+
+	removeEmptyObjects(obj)
+
+	return obj
+}
+
+// removeEmptyObjects removes the fields of the object whose value is an empty object, once their own empty
+// objects are removed.
+func removeEmptyObjects(obj map[string]any) {
+	for key, value := range obj {
+		if nested, ok := value.(map[string]any); ok {
+			removeEmptyObjects(nested)
+
+			if len(nested) == 0 {
+				delete(obj, key)
+			}
+		}
+	}
+}
+
+// ComponentName returns the kind and the name of the component, like "Deployment capi-system/capi-controller-manager".
+func ComponentName(component unstructured.Unstructured) string {
+	name := component.GetName()
+	if component.GetNamespace() != "" {
+		name = component.GetNamespace() + "/" + name
+	}
+
+	return component.GetKind() + " " + name
+}
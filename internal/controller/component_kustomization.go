@@ -0,0 +1,123 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"path"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	kustomizetypes "sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/yaml"
+
+	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
+	"sigs.k8s.io/cluster-api-operator/internal/kustomize"
+)
+
+// applyKustomization runs the kustomization of the provider over the components, which are used as its first resources.
+func applyKustomization(ctx context.Context, cl client.Client, provider operatorv1.GenericProvider) func(objs []unstructured.Unstructured) ([]unstructured.Unstructured, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	return func(objs []unstructured.Unstructured) ([]unstructured.Unstructured, error) {
+		if provider.GetSpec().Kustomization == nil {
+			log.V(5).Info("No kustomization to apply")
+			return objs, nil
+		}
+
+		files, err := fetchKustomizationFiles(ctx, cl, provider)
+		if err != nil {
+			return nil, err
+		}
+
+		log.V(5).Info("Applying kustomization", "configMap", provider.GetSpec().Kustomization.Name)
+
+		return kustomize.RenderOverlay(files, ".", objs)
+	}
+}
+
+// fetchKustomizationFiles returns the files of the kustomization ConfigMap of the provider, keyed by their file name.
+func fetchKustomizationFiles(ctx context.Context, cl client.Client, provider operatorv1.GenericProvider) (kustomize.Files, error) {
+	ref := provider.GetSpec().Kustomization
+	key := types.NamespacedName{Namespace: cmp.Or(ref.Namespace, provider.GetNamespace()), Name: ref.Name}
+
+	cm := &corev1.ConfigMap{}
+	if err := cl.Get(ctx, key, cm); err != nil {
+		return nil, fmt.Errorf("failed to get kustomization ConfigMap %s/%s: %w", key.Namespace, key.Name, err)
+	}
+
+	if _, ok := cm.Data[operatorv1.KustomizationConfigMapKey]; !ok {
+		return nil, fmt.Errorf("kustomization ConfigMap %s/%s has no %q key", key.Namespace, key.Name, operatorv1.KustomizationConfigMapKey)
+	}
+
+	files := kustomize.Files{}
+
+	for name, data := range cm.Data {
+		files[name] = []byte(data)
+	}
+
+	for name, data := range cm.BinaryData {
+		files[name] = data
+	}
+
+	return componentDirectories(files), nil
+}
+
+// componentDirectories turns the files holding a kustomization of the Component kind into directories with the
+// same name, as kustomize only loads components from directories, and ConfigMap keys can't contain directories.
+// The other files are copied to each component directory, so that components refer to them by their file name.
+func componentDirectories(files kustomize.Files) kustomize.Files {
+	components := []string{}
+
+	for name, data := range files {
+		typeMeta := &metav1.TypeMeta{}
+		if name != operatorv1.KustomizationConfigMapKey && yaml.Unmarshal(data, typeMeta) == nil && typeMeta.Kind == kustomizetypes.ComponentKind {
+			components = append(components, name)
+		}
+	}
+
+	if len(components) == 0 {
+		return files
+	}
+
+	dirs := kustomize.Files{}
+
+	for name, data := range files {
+		if !slices.Contains(components, name) {
+			dirs[name] = data
+		}
+	}
+
+	for _, component := range components {
+		for name, data := range files {
+			if name != operatorv1.KustomizationConfigMapKey && !slices.Contains(components, name) {
+				dirs[path.Join(component, name)] = data
+			}
+		}
+
+		dirs[path.Join(component, operatorv1.KustomizationConfigMapKey)] = files[component]
+	}
+
+	return dirs
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilyaml "sigs.k8s.io/cluster-api/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
+)
+
+func TestApplyKustomization(t *testing.T) {
+	components := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: capi-controller-manager
+  namespace: capi-system
+spec:
+  template:
+    spec:
+      containers:
+      - name: manager
+        image: registry.k8s.io/cluster-api/cluster-api-controller:v1.9.3
+`
+
+	overlay := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "overlay", Namespace: "capi-system"},
+		Data: map[string]string{
+			operatorv1.KustomizationConfigMapKey: "patches:\n- path: replicas.yaml\ncommonLabels:\n  team: platform\n",
+			"replicas.yaml":                      "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: capi-controller-manager\n  namespace: capi-system\nspec:\n  replicas: 3\n",
+		},
+	}
+
+	componentsOverlay := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "components", Namespace: "capi-system"},
+		Data: map[string]string{
+			operatorv1.KustomizationConfigMapKey: "components:\n- ha.yaml\ntransformers:\n- suffix.yaml\n",
+			"ha.yaml":                            "apiVersion: kustomize.config.k8s.io/v1alpha1\nkind: Component\nresources:\n- pdb.yaml\npatches:\n- path: replicas.yaml\n",
+			"pdb.yaml":                           "apiVersion: policy/v1\nkind: PodDisruptionBudget\nmetadata:\n  name: capi-controller-manager\n  namespace: capi-system\n",
+			"replicas.yaml":                      overlay.Data["replicas.yaml"],
+			"suffix.yaml":                        "apiVersion: builtin\nkind: PrefixSuffixTransformer\nmetadata:\n  name: suffix\nsuffix: -v2\nfieldSpecs:\n- kind: PodDisruptionBudget\n  path: metadata/name\n",
+		},
+	}
+
+	invalid := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "invalid", Namespace: "capi-system"},
+		Data:       map[string]string{"replicas.yaml": overlay.Data["replicas.yaml"]},
+	}
+
+	testCases := []struct {
+		name          string
+		kustomization *operatorv1.ConfigmapReference
+		expectedErr   bool
+		check         func(g *WithT, objs []unstructured.Unstructured)
+	}{
+		{
+			name: "no kustomization",
+			check: func(g *WithT, objs []unstructured.Unstructured) {
+				g.Expect(objs[0].GetLabels()).To(BeEmpty())
+			},
+		},
+		{
+			name:          "kustomization in the provider namespace",
+			kustomization: &operatorv1.ConfigmapReference{Name: "overlay"},
+			check: func(g *WithT, objs []unstructured.Unstructured) {
+				g.Expect(objs).To(HaveLen(1))
+				g.Expect(objs[0].GetLabels()).To(HaveKeyWithValue("team", "platform"))
+
+				replicas, _, _ := unstructured.NestedFieldNoCopy(objs[0].Object, "spec", "replicas")
+				g.Expect(replicas).To(BeEquivalentTo(3))
+			},
+		},
+		{
+			name:          "component file and built-in transformer",
+			kustomization: &operatorv1.ConfigmapReference{Name: "components"},
+			check: func(g *WithT, objs []unstructured.Unstructured) {
+				g.Expect(objs).To(HaveLen(2))
+				g.Expect(objs[1].GetName()).To(Equal("capi-controller-manager-v2"))
+
+				replicas, _, _ := unstructured.NestedFieldNoCopy(objs[0].Object, "spec", "replicas")
+				g.Expect(replicas).To(BeEquivalentTo(3))
+			},
+		},
+		{
+			name:          "missing kustomization key",
+			kustomization: &operatorv1.ConfigmapReference{Name: "invalid", Namespace: "capi-system"},
+			expectedErr:   true,
+		},
+		{
+			name:          "missing ConfigMap",
+			kustomization: &operatorv1.ConfigmapReference{Name: "missing"},
+			expectedErr:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			objs, err := utilyaml.ToUnstructured([]byte(components))
+			g.Expect(err).NotTo(HaveOccurred())
+
+			provider := &operatorv1.CoreProvider{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-api", Namespace: "capi-system"},
+				Spec: operatorv1.CoreProviderSpec{ProviderSpec: operatorv1.ProviderSpec{
+					Kustomization: tc.kustomization,
+				}},
+			}
+
+			cl := fake.NewClientBuilder().WithObjects(overlay, componentsOverlay, invalid).Build()
+
+			objs, err = applyKustomization(context.Background(), cl, provider)(objs)
+			if tc.expectedErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+			tc.check(g, objs)
+		})
+	}
+}
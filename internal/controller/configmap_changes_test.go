@@ -140,14 +140,14 @@ func TestConfigMapChangesAreAppliedToTheProvider(t *testing.T) {
 			return false
 		}
 
-		hash := provider.GetAnnotations()[appliedSpecHashAnnotation]
+		hash := provider.GetAnnotations()[AppliedSpecHashAnnotation]
 
 		return hash != ""
 	}, timeout).Should(BeTrue(), "Provider should have a hash annotation after reconciliation")
 
 	// Get the initial hash annotation
 	g.Expect(env.Get(ctx, client.ObjectKeyFromObject(provider), provider)).To(Succeed())
-	initialHash := provider.GetAnnotations()[appliedSpecHashAnnotation]
+	initialHash := provider.GetAnnotations()[AppliedSpecHashAnnotation]
 	g.Expect(initialHash).ToNot(BeEmpty())
 
 	t.Log("Initial hash:", initialHash)
@@ -165,7 +165,7 @@ func TestConfigMapChangesAreAppliedToTheProvider(t *testing.T) {
 			return false
 		}
 
-		currentHash := provider.GetAnnotations()[appliedSpecHashAnnotation]
+		currentHash := provider.GetAnnotations()[AppliedSpecHashAnnotation]
 
 		return currentHash != "" && currentHash != initialHash
 	}, 30*time.Second).Should(BeTrue())
@@ -296,14 +296,14 @@ func TestConfigMapChangesWithNonMatchingSelector(t *testing.T) {
 			return false
 		}
 
-		hash := provider.GetAnnotations()[appliedSpecHashAnnotation]
+		hash := provider.GetAnnotations()[AppliedSpecHashAnnotation]
 
 		return hash != ""
 	}, timeout).Should(BeTrue(), "Provider should have a hash annotation after reconciliation")
 
 	// Get initial hash
 	g.Expect(env.Get(ctx, client.ObjectKeyFromObject(provider), provider)).To(Succeed())
-	initialHash := provider.GetAnnotations()[appliedSpecHashAnnotation]
+	initialHash := provider.GetAnnotations()[AppliedSpecHashAnnotation]
 	g.Expect(initialHash).ToNot(BeEmpty())
 
 	// Update the non-matching ConfigMap - this should NOT trigger provider reconciliation
@@ -319,7 +319,7 @@ func TestConfigMapChangesWithNonMatchingSelector(t *testing.T) {
 			return false
 		}
 
-		currentHash := provider.GetAnnotations()[appliedSpecHashAnnotation]
+		currentHash := provider.GetAnnotations()[AppliedSpecHashAnnotation]
 
 		return currentHash == initialHash
 	}, 10*time.Second, 2*time.Second).Should(BeTrue())
@@ -339,7 +339,7 @@ func TestConfigMapChangesWithNonMatchingSelector(t *testing.T) {
 			return false
 		}
 
-		currentHash := provider.GetAnnotations()[appliedSpecHashAnnotation]
+		currentHash := provider.GetAnnotations()[AppliedSpecHashAnnotation]
 
 		return currentHash != "" && currentHash != initialHash
 	}, 30*time.Second).Should(BeTrue())
@@ -17,6 +17,7 @@ limitations under the License.
 package controller
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 
@@ -30,7 +31,8 @@ import (
 )
 
 // newConfigMapToProviderFuncMapForProviderList maps a Kubernetes ConfigMap to all the providers that reference it.
-// It lists all the providers that have fetchConfig.selector that matches the ConfigMap's labels.
+// It lists all the providers that have fetchConfig.selector that matches the ConfigMap's labels, or that use
+// the ConfigMap kustomization.
 func newConfigMapToProviderFuncMapForProviderList(k8sClient client.Client, providerList genericprovider.GenericProviderList) handler.MapFunc {
 	providerListType := fmt.Sprintf("%T", providerList)
 
@@ -39,8 +41,8 @@ func newConfigMapToProviderFuncMapForProviderList(k8sClient client.Client, provi
 
 		var requests []reconcile.Request
 
-		// List all providers of this type
-		if err := k8sClient.List(ctx, providerList, client.InNamespace(configMap.GetNamespace())); err != nil {
+		// List all providers of this type, as the kustomization ConfigMap can be in another namespace.
+		if err := k8sClient.List(ctx, providerList); err != nil {
 			log.Error(err, "failed to list providers")
 			return nil
 		}
@@ -50,9 +52,19 @@ func newConfigMapToProviderFuncMapForProviderList(k8sClient client.Client, provi
 		for _, provider := range providerList.GetItems() {
 			log := log.WithValues("provider", map[string]string{"name": provider.GetName(), "namespace": provider.GetNamespace()})
 
-			// Check if provider uses fetchConfig with selector
 			spec := provider.GetSpec()
-			if spec.FetchConfig == nil || spec.FetchConfig.Selector == nil {
+
+			// Check if provider uses the ConfigMap kustomization
+			if ref := spec.Kustomization; ref != nil && ref.Name == configMap.GetName() && cmp.Or(ref.Namespace, provider.GetNamespace()) == configMap.GetNamespace() {
+				log.Info("ConfigMap is the provider kustomization, enqueueing reconcile request")
+
+				requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(provider)})
+
+				continue
+			}
+
+			// Check if provider uses fetchConfig with selector
+			if provider.GetNamespace() != configMap.GetNamespace() || spec.FetchConfig == nil || spec.FetchConfig.Selector == nil {
 				continue
 			}
 
@@ -233,3 +233,58 @@ func TestProviderConfigMapMapperNoMatches(t *testing.T) {
 
 	g.Expect(requests).To(BeEmpty())
 }
+
+func TestProviderConfigMapMapperKustomization(t *testing.T) {
+	g := NewWithT(t)
+
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(setupScheme()).
+		WithObjects(
+			&operatorv1.InfrastructureProvider{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "provider-with-kustomization",
+					Namespace: testNamespaceName,
+				},
+				Spec: operatorv1.InfrastructureProviderSpec{
+					ProviderSpec: operatorv1.ProviderSpec{
+						Kustomization: &operatorv1.ConfigmapReference{Name: "overlay"},
+					},
+				},
+			},
+			&operatorv1.InfrastructureProvider{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "provider-with-shared-kustomization",
+					Namespace: "other-namespace",
+				},
+				Spec: operatorv1.InfrastructureProviderSpec{
+					ProviderSpec: operatorv1.ProviderSpec{
+						Kustomization: &operatorv1.ConfigmapReference{Name: "overlay", Namespace: testNamespaceName},
+					},
+				},
+			},
+			&operatorv1.InfrastructureProvider{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "provider-with-other-kustomization",
+					Namespace: testNamespaceName,
+				},
+				Spec: operatorv1.InfrastructureProviderSpec{
+					ProviderSpec: operatorv1.ProviderSpec{
+						Kustomization: &operatorv1.ConfigmapReference{Name: "other-overlay"},
+					},
+				},
+			},
+		).
+		Build()
+
+	requests := newConfigMapToProviderFuncMapForProviderList(k8sClient, &operatorv1.InfrastructureProviderList{})(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "overlay",
+			Namespace: testNamespaceName,
+		},
+	})
+
+	g.Expect(requests).To(ConsistOf(
+		ctrl.Request{NamespacedName: types.NamespacedName{Namespace: testNamespaceName, Name: "provider-with-kustomization"}},
+		ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "other-namespace", Name: "provider-with-shared-kustomization"}},
+	))
+}
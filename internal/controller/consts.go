@@ -24,4 +24,8 @@ const (
 	deploymentKind = "Deployment"
 	daemonSetKind  = "DaemonSet"
 	namespaceKind  = "Namespace"
+
+	customResourceDefinitionKind       = "CustomResourceDefinition"
+	validatingWebhookConfigurationKind = "ValidatingWebhookConfiguration"
+	mutatingWebhookConfigurationKind   = "MutatingWebhookConfiguration"
 )
@@ -0,0 +1,455 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
+	"sigs.k8s.io/cluster-api-operator/internal/controller/genericprovider"
+	"sigs.k8s.io/cluster-api-operator/util"
+)
+
+const (
+	// defaultDriftCheckInterval is the default interval the provider components are compared with the cache at.
+	defaultDriftCheckInterval = 10 * time.Minute
+
+	// driftMessageMaxComponents is the maximum number of drifted components listed in the condition message.
+	driftMessageMaxComponents = 10
+)
+
+// driftWatchedComponents are the kinds of provider components whose changes trigger a drift check. The components
+// of other kinds are compared with the cache at the drift check interval.
+var driftWatchedComponents = []client.Object{
+	&appsv1.Deployment{},
+	&corev1.Service{},
+	&corev1.ServiceAccount{},
+	&rbacv1.Role{},
+	&rbacv1.RoleBinding{},
+	&rbacv1.ClusterRole{},
+	&rbacv1.ClusterRoleBinding{},
+	&apiextensionsv1.CustomResourceDefinition{},
+	&admissionregistrationv1.MutatingWebhookConfiguration{},
+	&admissionregistrationv1.ValidatingWebhookConfiguration{},
+}
+
+// driftIgnoredMetadataFields are the metadata fields maintained by the API server, which are not compared.
+var driftIgnoredMetadataFields = []string{"managedFields", "resourceVersion", "generation", "creationTimestamp", "uid"}
+
+// ProviderDriftReconciler detects changes made outside the operator to the installed components of the providers,
+// by comparing them with the manifests in the provider cache.
+type ProviderDriftReconciler struct {
+	Client client.Client
+
+	// APIReader reads the live components from the API server, to not start an informer for each of their kinds.
+	APIReader client.Reader
+
+	// CheckInterval is the interval the components are compared with the cache at. Defaults to 10 minutes.
+	CheckInterval time.Duration
+}
+
+// GenericProviderDriftReconciler detects the drift of the components of a provider kind.
+type GenericProviderDriftReconciler struct {
+	Client        client.Client
+	APIReader     client.Reader
+	Provider      operatorv1.GenericProvider
+	CheckInterval time.Duration
+
+	providerGVK schema.GroupVersionKind
+}
+
+func (r *ProviderDriftReconciler) SetupWithManager(mgr ctrl.Manager, options controller.Options) error {
+	errs := []error{}
+
+	for _, provider := range operatorv1.Providers {
+		errs = append(errs, (&GenericProviderDriftReconciler{
+			Client:        r.Client,
+			APIReader:     r.APIReader,
+			Provider:      provider,
+			CheckInterval: r.CheckInterval,
+		}).SetupWithManager(mgr, options))
+	}
+
+	return kerrors.NewAggregate(errs)
+}
+
+func (r *GenericProviderDriftReconciler) SetupWithManager(mgr ctrl.Manager, options controller.Options) error {
+	kinds, _, err := mgr.GetScheme().ObjectKinds(r.Provider)
+	if err != nil {
+		return err
+	}
+
+	r.providerGVK = kinds[0]
+
+	if r.CheckInterval == 0 {
+		r.CheckInterval = defaultDriftCheckInterval
+	}
+
+	b := ctrl.NewControllerManagedBy(mgr).
+		Named(fmt.Sprintf("drift-%s", r.providerGVK)).
+		For(r.Provider, builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{})))
+
+	// Only the metadata of the components is cached, as they are compared with the API server.
+	for _, component := range driftWatchedComponents {
+		b = b.Watches(component, handler.EnqueueRequestsFromMapFunc(r.componentToProviders),
+			builder.OnlyMetadata, builder.WithPredicates(componentChangedPredicate()))
+	}
+
+	return b.WithOptions(options).Complete(r)
+}
+
+func (r *GenericProviderDriftReconciler) Reconcile(ctx context.Context, req reconcile.Request) (_ reconcile.Result, reterr error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	provider, ok := r.Provider.DeepCopyObject().(operatorv1.GenericProvider)
+	if !ok {
+		return ctrl.Result{}, fmt.Errorf("failed to cast provider object as GenericProvider")
+	}
+
+	if err := r.Client.Get(ctx, req.NamespacedName, provider); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// Components are only compared with the cache once the provider is installed.
+	if !provider.GetDeletionTimestamp().IsZero() || !conditions.IsTrue(provider, operatorv1.ProviderInstalledCondition) {
+		return ctrl.Result{}, nil
+	}
+
+	// Components changed by hand while the provider is paused are neither reported nor remediated. The check
+	// runs again once the paused annotation is removed.
+	if annotations.HasPaused(provider) {
+		log.V(2).Info("Provider is paused, skipping drift check")
+
+		return ctrl.Result{}, nil
+	}
+
+	patchHelper, err := patch.NewHelper(provider, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	defer func() {
+		if err := patchHelper.Patch(ctx, provider, patch.WithOwnedConditions{Conditions: []string{operatorv1.ComponentsDriftedCondition}}); err != nil {
+			reterr = kerrors.NewAggregate([]error{reterr, err})
+		}
+	}()
+
+	if provider.GetSpec().DriftPolicy == operatorv1.IgnoreDriftPolicy {
+		conditions.Delete(provider, operatorv1.ComponentsDriftedCondition)
+
+		return ctrl.Result{}, nil
+	}
+
+	secret := &corev1.Secret{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Name: ProviderCacheName(provider), Namespace: provider.GetNamespace()}, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.V(2).Info("Provider cache not found, skipping drift check")
+
+			return ctrl.Result{}, nil
+		}
+
+		return ctrl.Result{}, fmt.Errorf("failed to get provider cache: %w", err)
+	}
+
+	// The cache doesn't match the installed components while the provider is being installed or upgraded.
+	cacheHash := secret.GetAnnotations()[AppliedSpecHashAnnotation]
+	if cacheHash == "" || cacheHash != provider.GetAnnotations()[AppliedSpecHashAnnotation] {
+		log.V(2).Info("Provider cache is not applied yet, skipping drift check")
+
+		return ctrl.Result{RequeueAfter: r.CheckInterval}, nil
+	}
+
+	manifests, err := cachedManifests(secret.Data, secret.GetAnnotations()[operatorv1.CompressedAnnotation] == operatorv1.TrueValue)
+	if err != nil {
+		setDriftCheckFailedCondition(provider, err)
+
+		return ctrl.Result{}, err
+	}
+
+	drifts, err := componentsDrift(ctx, r.Client, r.APIReader, manifests)
+	if err != nil {
+		setDriftCheckFailedCondition(provider, err)
+
+		return ctrl.Result{}, err
+	}
+
+	switch {
+	case len(drifts) == 0:
+		conditions.Set(provider, metav1.Condition{
+			Type:    operatorv1.ComponentsDriftedCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  operatorv1.ComponentsInSyncReason,
+			Message: "Provider components match the cached manifests",
+		})
+	case provider.GetSpec().DriftPolicy == operatorv1.RemediateDriftPolicy:
+		log.Info("Re-applying drifted provider components", "components", driftDescriptions(drifts))
+
+		drifted := make([]unstructured.Unstructured, 0, len(drifts))
+		for _, drift := range drifts {
+			drifted = append(drifted, drift.manifest)
+		}
+
+		if err := applyManifests(ctx, r.Client, drifted); err != nil {
+			conditions.Set(provider, metav1.Condition{
+				Type:    operatorv1.ComponentsDriftedCondition,
+				Status:  metav1.ConditionTrue,
+				Reason:  operatorv1.ComponentsDriftDetectedReason,
+				Message: fmt.Sprintf("%s; failed to re-apply the components: %v", driftMessage(drifts), err),
+			})
+
+			return ctrl.Result{}, err
+		}
+
+		conditions.Set(provider, metav1.Condition{
+			Type:    operatorv1.ComponentsDriftedCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  operatorv1.ComponentsDriftRemediatedReason,
+			Message: "Re-applied from the cache. " + driftMessage(drifts),
+		})
+	default:
+		log.Info("Provider components drifted from the cached manifests", "components", driftDescriptions(drifts))
+
+		conditions.Set(provider, metav1.Condition{
+			Type:    operatorv1.ComponentsDriftedCondition,
+			Status:  metav1.ConditionTrue,
+			Reason:  operatorv1.ComponentsDriftDetectedReason,
+			Message: driftMessage(drifts),
+		})
+	}
+
+	return ctrl.Result{RequeueAfter: r.CheckInterval}, nil
+}
+
+// componentToProviders maps a provider component to the providers of the kind it is labelled with.
+func (r *GenericProviderDriftReconciler) componentToProviders(ctx context.Context, component client.Object) []reconcile.Request {
+	log := ctrl.LoggerFrom(ctx)
+
+	label := component.GetLabels()[clusterv1.ProviderNameLabel]
+
+	providerList, err := newProviderList(r.Client.Scheme(), r.providerGVK)
+	if err != nil {
+		log.Error(err, "Failed to create provider list")
+
+		return nil
+	}
+
+	if err := r.Client.List(ctx, providerList); err != nil {
+		log.Error(err, "Failed to list providers")
+
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+
+	for _, provider := range providerList.GetItems() {
+		if clusterctlv1.ManifestLabel(provider.ProviderName(), util.ClusterctlProviderType(provider)) == label {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(provider)})
+		}
+	}
+
+	return requests
+}
+
+// newProviderList returns a new list of the provider kind.
+func newProviderList(scheme *runtime.Scheme, providerGVK schema.GroupVersionKind) (genericprovider.GenericProviderList, error) {
+	obj, err := scheme.New(providerGVK.GroupVersion().WithKind(providerGVK.Kind + "List"))
+	if err != nil {
+		return nil, err
+	}
+
+	providerList, ok := obj.(genericprovider.GenericProviderList)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a provider list", providerGVK.Kind)
+	}
+
+	return providerList, nil
+}
+
+// componentChangedPredicate filters the events of the provider components, ignoring their status updates.
+func componentChangedPredicate() predicate.Predicate {
+	isComponent := predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		_, ok := obj.GetLabels()[clusterv1.ProviderNameLabel]
+		return ok
+	})
+
+	// The generation isn't tracked by all the kinds, so their updates are not filtered.
+	untrackedGeneration := predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return e.ObjectNew.GetGeneration() == 0
+		},
+	}
+
+	return predicate.And(isComponent, predicate.Or(
+		predicate.GenerationChangedPredicate{},
+		predicate.LabelChangedPredicate{},
+		predicate.AnnotationChangedPredicate{},
+		untrackedGeneration,
+	))
+}
+
+// componentDrift is a provider component which differs from its cached manifest.
+type componentDrift struct {
+	manifest unstructured.Unstructured
+	deleted  bool
+	fields   []string
+}
+
+// String returns the description of the drift, like "Deployment capi-system/capi-controller-manager: spec.replicas".
+func (d componentDrift) String() string {
+	if d.deleted {
+		return ComponentName(d.manifest) + ": deleted"
+	}
+
+	return ComponentName(d.manifest) + ": " + strings.Join(d.fields, ", ")
+}
+
+// componentsDrift compares the live components with the manifests, and returns the deleted components, and the
+// components whose fields would be changed by applying the manifest with server-side apply.
+func componentsDrift(ctx context.Context, cl client.Client, reader client.Reader, manifests []unstructured.Unstructured) ([]componentDrift, error) {
+	drifts := []componentDrift{}
+
+	for i := range manifests {
+		manifest := manifests[i]
+
+		live, applied, err := dryRunApply(ctx, cl, reader, manifest)
+		if err != nil {
+			return nil, err
+		}
+
+		if live == nil {
+			drifts = append(drifts, componentDrift{manifest: manifest, deleted: true})
+			continue
+		}
+
+		if fields := driftedFields(live.Object, applied.Object); len(fields) > 0 {
+			drifts = append(drifts, componentDrift{manifest: manifest, fields: fields})
+		}
+	}
+
+	return drifts, nil
+}
+
+// driftedFields returns the paths of the fields which differ between the live and the applied object, ignoring
+// the status and the metadata maintained by the API server.
+func driftedFields(live, applied map[string]any) []string {
+	fields := []string{}
+	diffFields("", withoutServerFields(live), withoutServerFields(applied), &fields)
+
+	return fields
+}
+
+// diffFields adds the paths of the fields which differ between the values to the list. The elements of lists
+// of the same length are compared one by one.
+func diffFields(path string, live, applied any, fields *[]string) {
+	liveMap, liveIsMap := live.(map[string]any)
+	appliedMap, appliedIsMap := applied.(map[string]any)
+
+	if liveIsMap && appliedIsMap {
+		keys := slices.Collect(maps.Keys(liveMap))
+		for key := range appliedMap {
+			if _, ok := liveMap[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+
+		slices.Sort(keys)
+
+		for _, key := range keys {
+			diffFields(strings.TrimPrefix(path+"."+key, "."), liveMap[key], appliedMap[key], fields)
+		}
+
+		return
+	}
+
+	liveList, liveIsList := live.([]any)
+	appliedList, appliedIsList := applied.([]any)
+
+	if liveIsList && appliedIsList && len(liveList) == len(appliedList) {
+		for i := range liveList {
+			diffFields(fmt.Sprintf("%s[%d]", path, i), liveList[i], appliedList[i], fields)
+		}
+
+		return
+	}
+
+	if !reflect.DeepEqual(live, applied) {
+		*fields = append(*fields, path)
+	}
+}
+
+// driftDescriptions returns the descriptions of the drifted components.
+func driftDescriptions(drifts []componentDrift) []string {
+	descriptions := make([]string, 0, len(drifts))
+	for _, drift := range drifts {
+		descriptions = append(descriptions, drift.String())
+	}
+
+	return descriptions
+}
+
+// driftMessage returns the condition message listing the drifted components.
+func driftMessage(drifts []componentDrift) string {
+	descriptions := driftDescriptions(drifts)
+
+	message := fmt.Sprintf("Drifted components (%d): %s", len(drifts), strings.Join(descriptions[:min(len(descriptions), driftMessageMaxComponents)], "; "))
+	if len(descriptions) > driftMessageMaxComponents {
+		message += fmt.Sprintf("; and %d more", len(descriptions)-driftMessageMaxComponents)
+	}
+
+	return message
+}
+
+// setDriftCheckFailedCondition sets the ComponentsDrifted condition to unknown, with the error.
+func setDriftCheckFailedCondition(provider operatorv1.GenericProvider, err error) {
+	conditions.Set(provider, metav1.Condition{
+		Type:    operatorv1.ComponentsDriftedCondition,
+		Status:  metav1.ConditionUnknown,
+		Reason:  operatorv1.ComponentsDriftCheckFailedReason,
+		Message: err.Error(),
+	})
+}
@@ -0,0 +1,244 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
+)
+
+func TestDriftedFields(t *testing.T) {
+	testCases := []struct {
+		name     string
+		live     map[string]any
+		applied  map[string]any
+		expected []string
+	}{
+		{
+			name: "same object with different server metadata and status",
+			live: map[string]any{
+				"metadata": map[string]any{"name": "manager", "resourceVersion": "1", "generation": int64(1)},
+				"spec":     map[string]any{"replicas": int64(1)},
+				"status":   map[string]any{"replicas": int64(1)},
+			},
+			applied: map[string]any{
+				"metadata": map[string]any{"name": "manager", "resourceVersion": "2", "generation": int64(2)},
+				"spec":     map[string]any{"replicas": int64(1)},
+			},
+			expected: []string{},
+		},
+		{
+			name: "changed fields",
+			live: map[string]any{
+				"metadata": map[string]any{"name": "manager", "labels": map[string]any{"team": "platform"}},
+				"spec": map[string]any{
+					"replicas": int64(3),
+					"containers": []any{
+						map[string]any{"name": "manager", "image": "registry.k8s.io/capi:v1.9.0"},
+					},
+				},
+			},
+			applied: map[string]any{
+				"metadata": map[string]any{"name": "manager"},
+				"spec": map[string]any{
+					"replicas": int64(1),
+					"containers": []any{
+						map[string]any{"name": "manager", "image": "registry.k8s.io/capi:v1.9.3"},
+					},
+				},
+			},
+			expected: []string{"metadata.labels", "spec.containers[0].image", "spec.replicas"},
+		},
+		{
+			name:     "list with a different length",
+			live:     map[string]any{"rules": []any{"a", "b"}},
+			applied:  map[string]any{"rules": []any{"a"}},
+			expected: []string{"rules"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(driftedFields(tc.live, tc.applied)).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestDriftMessage(t *testing.T) {
+	g := NewWithT(t)
+
+	deployment := unstructured.Unstructured{}
+	deployment.SetKind("Deployment")
+	deployment.SetName("capi-controller-manager")
+	deployment.SetNamespace("capi-system")
+
+	clusterRole := unstructured.Unstructured{}
+	clusterRole.SetKind("ClusterRole")
+	clusterRole.SetName("capi-manager-role")
+
+	g.Expect(driftMessage([]componentDrift{
+		{manifest: deployment, fields: []string{"spec.replicas", "spec.template.spec.containers[0].image"}},
+		{manifest: clusterRole, deleted: true},
+	})).To(Equal("Drifted components (2): Deployment capi-system/capi-controller-manager: spec.replicas, " +
+		"spec.template.spec.containers[0].image; ClusterRole capi-manager-role: deleted"))
+
+	drifts := []componentDrift{}
+	for i := range driftMessageMaxComponents + 2 {
+		role := clusterRole.DeepCopy()
+		role.SetName(fmt.Sprintf("role-%d", i))
+		drifts = append(drifts, componentDrift{manifest: *role, deleted: true})
+	}
+
+	g.Expect(driftMessage(drifts)).To(HaveSuffix("ClusterRole role-9: deleted; and 2 more"))
+}
+
+func TestDriftReconcile(t *testing.T) {
+	manifests := []unstructured.Unstructured{{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "ServiceAccount",
+		"metadata":   map[string]any{"name": "capi-manager", "namespace": "capi-system"},
+	}}}
+
+	data, err := json.Marshal(manifests)
+	NewWithT(t).Expect(err).NotTo(HaveOccurred())
+
+	testCases := []struct {
+		name              string
+		driftPolicy       operatorv1.DriftPolicy
+		installed         bool
+		paused            bool
+		providerHash      string
+		expectedCondition *metav1.Condition
+	}{
+		{
+			name:         "deleted component is reported",
+			installed:    true,
+			providerHash: "hash",
+			expectedCondition: &metav1.Condition{
+				Status:  metav1.ConditionTrue,
+				Reason:  operatorv1.ComponentsDriftDetectedReason,
+				Message: "Drifted components (1): ServiceAccount capi-system/capi-manager: deleted",
+			},
+		},
+		{
+			name:         "provider not installed",
+			providerHash: "hash",
+		},
+		{
+			name:         "cache not applied yet",
+			installed:    true,
+			providerHash: "previous-hash",
+		},
+		{
+			name:         "provider paused",
+			installed:    true,
+			paused:       true,
+			providerHash: "hash",
+		},
+		{
+			name:         "drift ignored",
+			driftPolicy:  operatorv1.IgnoreDriftPolicy,
+			installed:    true,
+			providerHash: "hash",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			provider := &operatorv1.CoreProvider{
+				TypeMeta: metav1.TypeMeta{Kind: "CoreProvider", APIVersion: operatorv1.GroupVersion.String()},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "cluster-api",
+					Namespace:   "capi-system",
+					Annotations: map[string]string{AppliedSpecHashAnnotation: tc.providerHash},
+				},
+				Spec: operatorv1.CoreProviderSpec{ProviderSpec: operatorv1.ProviderSpec{
+					Version:     "v1.9.3",
+					DriftPolicy: tc.driftPolicy,
+				}},
+			}
+
+			if tc.paused {
+				provider.Annotations[clusterv1.PausedAnnotation] = ""
+			}
+
+			if tc.installed {
+				conditions.Set(provider, metav1.Condition{
+					Type:   operatorv1.ProviderInstalledCondition,
+					Status: metav1.ConditionTrue,
+					Reason: "Installed",
+				})
+			}
+
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        ProviderCacheName(provider),
+					Namespace:   "capi-system",
+					Annotations: map[string]string{AppliedSpecHashAnnotation: "hash"},
+				},
+				Data: map[string][]byte{"cache": data},
+			}
+
+			cl := fake.NewClientBuilder().
+				WithScheme(setupScheme()).
+				WithObjects(provider, secret).
+				WithStatusSubresource(provider).
+				Build()
+
+			r := &GenericProviderDriftReconciler{
+				Client:        cl,
+				APIReader:     cl,
+				Provider:      &operatorv1.CoreProvider{},
+				CheckInterval: defaultDriftCheckInterval,
+			}
+
+			_, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(provider)})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			g.Expect(cl.Get(context.Background(), client.ObjectKeyFromObject(provider), provider)).To(Succeed())
+
+			condition := conditions.Get(provider, operatorv1.ComponentsDriftedCondition)
+			if tc.expectedCondition == nil {
+				g.Expect(condition).To(BeNil())
+				return
+			}
+
+			g.Expect(condition).NotTo(BeNil())
+			g.Expect(condition.Status).To(Equal(tc.expectedCondition.Status))
+			g.Expect(condition.Reason).To(Equal(tc.expectedCondition.Reason))
+			g.Expect(condition.Message).To(Equal(tc.expectedCondition.Message))
+		})
+	}
+}
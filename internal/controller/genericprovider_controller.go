@@ -34,8 +34,10 @@ import (
 	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
 	"sigs.k8s.io/cluster-api-operator/internal/controller/genericprovider"
 	"sigs.k8s.io/cluster-api-operator/util"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
 	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
 	configclient "sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -61,7 +63,9 @@ type GenericProviderReconciler struct {
 }
 
 const (
-	appliedSpecHashAnnotation = "operator.cluster.x-k8s.io/applied-spec-hash"
+	// AppliedSpecHashAnnotation records the hash of the provider spec and of its referenced objects applied by the
+	// operator, which is compared to skip reconciling unchanged providers.
+	AppliedSpecHashAnnotation = "operator.cluster.x-k8s.io/applied-spec-hash"
 	cacheOwner                = "capi-operator"
 )
 
@@ -102,12 +106,16 @@ func (r *GenericProviderReconciler) BuildWithManager(ctx context.Context, mgr ct
 	reconciler := NewPhaseReconciler(*r, r.Provider, r.ProviderList)
 
 	r.ReconcilePhases = []PhaseFn{
+		reconciler.VerifyUpgrade,
 		reconciler.ApplyFromCache,
+		reconciler.WaitForMaintenanceWindow,
 		reconciler.PreflightChecks,
 		reconciler.InitializePhaseReconciler,
 		reconciler.DownloadManifests,
 		reconciler.Load,
+		reconciler.VerifyVariables,
 		reconciler.Fetch,
+		reconciler.Preview,
 		reconciler.Store,
 		reconciler.Upgrade,
 		reconciler.Install,
@@ -154,6 +162,23 @@ func (r *GenericProviderReconciler) Reconcile(ctx context.Context, req reconcile
 		return ctrl.Result{}, err
 	}
 
+	// Skip all the phases while the provider is paused, to not revert the changes made to its components by hand.
+	// The provider and cache hashes are compared again once it is resumed.
+	if annotations.HasPaused(r.Provider) {
+		log.Info("Reconciliation is paused for this provider")
+
+		conditions.Set(r.Provider, metav1.Condition{
+			Type:    clusterv1.PausedCondition,
+			Status:  metav1.ConditionTrue,
+			Reason:  clusterv1.PausedReason,
+			Message: fmt.Sprintf("Reconciliation is paused with the %s annotation", clusterv1.PausedAnnotation),
+		})
+
+		return ctrl.Result{}, patchProvider(ctx, r.Provider, patchHelper)
+	}
+
+	conditions.Delete(r.Provider, clusterv1.PausedCondition)
+
 	defer func() {
 		// Always attempt to patch the object and status after each reconciliation.
 		// Patch ObservedGeneration only if the reconciliation completed successfully
@@ -192,6 +217,18 @@ func (r *GenericProviderReconciler) Reconcile(ctx context.Context, req reconcile
 
 	res, err := r.reconcile(ctx)
 
+	// Re-evaluate the version policy periodically.
+	if err == nil && res.IsZero() && r.Provider.GetSpec().VersionPolicy != nil {
+		res.RequeueAfter = versionPolicyRequeueAfter(r.Provider)
+	}
+
+	// Check the OCI artifact for drift periodically.
+	if after := ociDriftCheckRequeueAfter(r.Provider); err == nil && !res.Requeue && after > 0 {
+		if res.RequeueAfter == 0 || after < res.RequeueAfter {
+			res.RequeueAfter = after
+		}
+	}
+
 	return ctrl.Result{
 		Requeue:      res.Requeue,
 		RequeueAfter: res.RequeueAfter,
@@ -337,6 +374,19 @@ func addConfigMapToHash(ctx context.Context, k8sClient client.Client, hash hash.
 	return processProviderConfigMaps(ctx, k8sClient, hash, provider, spec.FetchConfig.Selector)
 }
 
+func addKustomizationToHash(ctx context.Context, k8sClient client.Client, hash hash.Hash, provider genericprovider.GenericProvider) error {
+	if provider.GetSpec().Kustomization == nil {
+		return nil
+	}
+
+	files, err := fetchKustomizationFiles(ctx, k8sClient, provider)
+	if err != nil {
+		return err
+	}
+
+	return addObjectToHash(hash, files)
+}
+
 func processProviderConfigMaps(ctx context.Context, k8sClient client.Client, hash hash.Hash, provider genericprovider.GenericProvider, selector *metav1.LabelSelector) error {
 	// List ConfigMaps that match the provider's selector
 	labelSelector, err := metav1.LabelSelectorAsSelector(selector)
@@ -400,6 +450,10 @@ func providerHash(ctx context.Context, client client.Client, hash hash.Hash, pro
 		return fmt.Errorf("failed to calculate configmap hash: %w", err)
 	}
 
+	if err := addKustomizationToHash(ctx, client, hash, provider); err != nil {
+		return fmt.Errorf("failed to calculate kustomization hash: %w", err)
+	}
+
 	return nil
 }
 
@@ -437,38 +491,38 @@ func (r *GenericProviderReconciler) providerMapper(ctx context.Context, provider
 func (p *PhaseReconciler) ApplyFromCache(ctx context.Context) (*Result, error) {
 	log := log.FromContext(ctx)
 
-	secret := &corev1.Secret{}
-	if err := p.ctrlClient.Get(ctx, client.ObjectKey{Name: ProviderCacheName(p.provider), Namespace: p.provider.GetNamespace()}, secret); apierrors.IsNotFound(err) {
-		// secret does not exist, nothing to apply
+	if isVersionPolicyCheckDue(p.provider) {
+		log.Info("Version policy has to be re-evaluated, skipping cache")
+
 		return &Result{}, nil
-	} else if err != nil {
-		return &Result{}, fmt.Errorf("failed to get provider cache: %w", err)
 	}
 
-	// calculate combined hash for provider and config map cache
-	hash := sha256.New()
-	if err := providerHash(ctx, p.ctrlClient, hash, p.provider); err != nil {
-		return &Result{}, err
-	}
+	// The drift check only reports whether the OCI reference still resolves to the fetched artifact,
+	// so the cached manifests are still applied.
+	if isOCIDriftCheckDue(p.provider) {
+		log.V(2).Info("Checking OCI artifact for drift")
 
-	if err := addObjectToHash(hash, secret.Data); err != nil {
-		return &Result{}, fmt.Errorf("failed to calculate config map hash: %w", err)
+		if err := p.reconcileOCIArtifact(ctx); err != nil {
+			return &Result{}, wrapPhaseError(err, operatorv1.ComponentsFetchErrorReason, operatorv1.ProviderInstalledCondition)
+		}
 	}
 
-	data, err := os.ReadFile(configPath)
-	if os.IsNotExist(err) {
-		data = []byte{}
+	secret := &corev1.Secret{}
+	if err := p.ctrlClient.Get(ctx, client.ObjectKey{Name: ProviderCacheName(p.provider), Namespace: p.provider.GetNamespace()}, secret); apierrors.IsNotFound(err) {
+		// secret does not exist, nothing to apply
+		return &Result{}, nil
 	} else if err != nil {
-		return &Result{}, err
+		return &Result{}, fmt.Errorf("failed to get provider cache: %w", err)
 	}
 
-	if err := addObjectToHash(hash, data); err != nil {
+	// calculate combined hash for provider and config map cache
+	cacheHash, err := calculateCacheHash(ctx, p.ctrlClient, p.provider, secret.Data)
+	if err != nil {
 		return &Result{}, err
 	}
 
-	cacheHash := fmt.Sprintf("%x", hash.Sum(nil))
-	if secret.GetAnnotations()[appliedSpecHashAnnotation] != cacheHash || p.provider.GetAnnotations()[appliedSpecHashAnnotation] != cacheHash {
-		log.Info("Provider or cache state has changed", "cacheHash", cacheHash, "providerHash", secret.GetAnnotations()[appliedSpecHashAnnotation])
+	if secret.GetAnnotations()[AppliedSpecHashAnnotation] != cacheHash || p.provider.GetAnnotations()[AppliedSpecHashAnnotation] != cacheHash {
+		log.Info("Provider or cache state has changed", "cacheHash", cacheHash, "providerHash", secret.GetAnnotations()[AppliedSpecHashAnnotation])
 
 		return &Result{}, nil
 	}
@@ -504,7 +558,18 @@ func (p *PhaseReconciler) applyManifestsFromData(ctx context.Context, data map[s
 
 	log.V(2).Info("Applying manifests from cache", "entries", len(data), "compressed", compressed)
 
-	var errs []error
+	manifests, err := cachedManifests(data, compressed)
+	if err != nil {
+		return err
+	}
+
+	return applyManifests(ctx, p.ctrlClient, manifests)
+}
+
+// cachedManifests unmarshals the manifests stored in the cache secret data.
+// If compressed is true, each data value is decompressed before processing.
+func cachedManifests(data map[string][]byte, compressed bool) ([]unstructured.Unstructured, error) {
+	var manifests []unstructured.Unstructured
 
 	for _, raw := range data {
 		manifest := raw
@@ -514,20 +579,29 @@ func (p *PhaseReconciler) applyManifestsFromData(ctx context.Context, data map[s
 
 			manifest, err = decompressData(raw)
 			if err != nil {
-				return fmt.Errorf("failed to decompress yaml: %w", err)
+				return nil, fmt.Errorf("failed to decompress yaml: %w", err)
 			}
 		}
 
-		var manifests []unstructured.Unstructured
+		var entries []unstructured.Unstructured
 
-		if err := json.Unmarshal(manifest, &manifests); err != nil {
-			return fmt.Errorf("failed to convert yaml to unstructured: %w", err)
+		if err := json.Unmarshal(manifest, &entries); err != nil {
+			return nil, fmt.Errorf("failed to convert yaml to unstructured: %w", err)
 		}
 
-		for i := range manifests {
-			if err := p.ctrlClient.Patch(ctx, &manifests[i], client.Apply, client.ForceOwnership, client.FieldOwner(cacheOwner)); err != nil {
-				errs = append(errs, err)
-			}
+		manifests = append(manifests, entries...)
+	}
+
+	return manifests, nil
+}
+
+// applyManifests applies the manifests via server-side apply, with the cache field owner.
+func applyManifests(ctx context.Context, cl client.Client, manifests []unstructured.Unstructured) error {
+	var errs []error
+
+	for i := range manifests {
+		if err := cl.Patch(ctx, &manifests[i], client.Apply, client.ForceOwnership, client.FieldOwner(cacheOwner)); err != nil {
+			errs = append(errs, err)
 		}
 	}
 
@@ -548,29 +622,11 @@ func setCacheHash(ctx context.Context, cl client.Client, provider genericprovide
 		return err
 	}
 
-	hash := sha256.New()
-
-	if err := providerHash(ctx, cl, hash, provider); err != nil {
-		return err
-	}
-
-	if err := addObjectToHash(hash, secret.Data); err != nil {
-		return err
-	}
-
-	data, err := os.ReadFile(configPath)
-	if os.IsNotExist(err) {
-		data = []byte{}
-	} else if err != nil {
-		return err
-	}
-
-	if err := addObjectToHash(hash, data); err != nil {
+	cacheHash, err := calculateCacheHash(ctx, cl, provider, secret.Data)
+	if err != nil {
 		return err
 	}
 
-	cacheHash := fmt.Sprintf("%x", hash.Sum(nil))
-
 	log.V(2).Info("Setting cache hash", "hash", cacheHash, "provider", provider.GetName())
 
 	annotations := secret.GetAnnotations()
@@ -578,7 +634,7 @@ func setCacheHash(ctx context.Context, cl client.Client, provider genericprovide
 		annotations = map[string]string{}
 	}
 
-	annotations[appliedSpecHashAnnotation] = cacheHash
+	annotations[AppliedSpecHashAnnotation] = cacheHash
 	secret.SetAnnotations(annotations)
 
 	// Set hash on the provider to avoid cache re-use on re-creation
@@ -587,8 +643,34 @@ func setCacheHash(ctx context.Context, cl client.Client, provider genericprovide
 		annotations = map[string]string{}
 	}
 
-	annotations[appliedSpecHashAnnotation] = cacheHash
+	annotations[AppliedSpecHashAnnotation] = cacheHash
 	provider.SetAnnotations(annotations)
 
 	return helper.Patch(ctx, secret)
 }
+
+// calculateCacheHash calculates the combined hash of the provider, its cached manifests and the operator configuration.
+func calculateCacheHash(ctx context.Context, cl client.Client, provider genericprovider.GenericProvider, cacheData map[string][]byte) (string, error) {
+	hash := sha256.New()
+
+	if err := providerHash(ctx, cl, hash, provider); err != nil {
+		return "", err
+	}
+
+	if err := addObjectToHash(hash, cacheData); err != nil {
+		return "", fmt.Errorf("failed to calculate config map hash: %w", err)
+	}
+
+	data, err := os.ReadFile(configPath)
+	if os.IsNotExist(err) {
+		data = []byte{}
+	} else if err != nil {
+		return "", err
+	}
+
+	if err := addObjectToHash(hash, data); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
@@ -34,6 +34,8 @@ import (
 	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
 	"sigs.k8s.io/cluster-api-operator/internal/controller/genericprovider"
@@ -767,7 +769,7 @@ func TestProviderConfigSecretChanges(t *testing.T) {
 
 			g.Eventually(generateExpectedResultChecker(provider, metav1.ConditionTrue, func(s string) bool { return s != "" }), timeout).Should(BeEquivalentTo(true))
 
-			initialHash := provider.GetAnnotations()[appliedSpecHashAnnotation]
+			initialHash := provider.GetAnnotations()[AppliedSpecHashAnnotation]
 
 			g.Eventually(func() error {
 				if err := env.Client.Get(ctx, client.ObjectKeyFromObject(secret), secret); err != nil {
@@ -785,14 +787,14 @@ func TestProviderConfigSecretChanges(t *testing.T) {
 					err := env.Client.Get(ctx, client.ObjectKeyFromObject(provider), provider)
 					g.Expect(client.IgnoreNotFound(err)).ToNot(HaveOccurred())
 
-					g.Expect(provider.GetAnnotations()[appliedSpecHashAnnotation]).To(Equal(initialHash))
+					g.Expect(provider.GetAnnotations()[AppliedSpecHashAnnotation]).To(Equal(initialHash))
 				}, 15*time.Second).Should(Succeed())
 			} else {
 				g.Eventually(func(g Gomega) {
 					err := env.Client.Get(ctx, client.ObjectKeyFromObject(provider), provider)
 					g.Expect(client.IgnoreNotFound(err)).ToNot(HaveOccurred())
 
-					g.Expect(provider.GetAnnotations()[appliedSpecHashAnnotation]).ToNot(Equal(initialHash))
+					g.Expect(provider.GetAnnotations()[AppliedSpecHashAnnotation]).ToNot(Equal(initialHash))
 				}, 15*time.Second).ShouldNot(Succeed())
 			}
 		})
@@ -923,7 +925,7 @@ func TestProviderSpecChanges(t *testing.T) {
 				return s != ""
 			}), timeout).Should(BeEquivalentTo(true))
 
-			currentHash := provider.GetAnnotations()[appliedSpecHashAnnotation]
+			currentHash := provider.GetAnnotations()[AppliedSpecHashAnnotation]
 
 			g.Eventually(func() error {
 				if err := env.Client.Get(ctx, client.ObjectKeyFromObject(provider), provider); err != nil {
@@ -968,7 +970,7 @@ func generateExpectedResultChecker(provider genericprovider.GenericProvider, con
 		}
 
 		// In case of error we don't want the spec annotation to be updated
-		if !hashCheck(provider.GetAnnotations()[appliedSpecHashAnnotation]) {
+		if !hashCheck(provider.GetAnnotations()[AppliedSpecHashAnnotation]) {
 			return false
 		}
 
@@ -1065,6 +1067,62 @@ func TestReconcile_ErrorStopsExecution(t *testing.T) {
 	g.Expect(thirdPhaseCalled).To(BeFalse())
 }
 
+func TestReconcile_Paused(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	provider := &operatorv1.CoreProvider{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "cluster-api",
+			Namespace:   "test-ns",
+			Finalizers:  []string{operatorv1.ProviderFinalizer},
+			Annotations: map[string]string{clusterv1.PausedAnnotation: ""},
+			Generation:  2,
+		},
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "CoreProvider",
+			APIVersion: "operator.cluster.x-k8s.io/v1alpha2",
+		},
+	}
+
+	cl := fake.NewClientBuilder().WithScheme(setupScheme()).WithObjects(provider).WithStatusSubresource(provider).Build()
+
+	phaseCalled := false
+
+	r := &GenericProviderReconciler{
+		Provider: &operatorv1.CoreProvider{},
+		Client:   cl,
+		ReconcilePhases: []PhaseFn{
+			func(ctx context.Context) (*Result, error) {
+				phaseCalled = true
+				return &Result{}, nil
+			},
+		},
+	}
+
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(provider)}
+
+	_, err := r.Reconcile(ctx, req)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(phaseCalled).To(BeFalse())
+
+	g.Expect(cl.Get(ctx, client.ObjectKeyFromObject(provider), provider)).To(Succeed())
+	g.Expect(conditions.IsTrue(provider, clusterv1.PausedCondition)).To(BeTrue())
+	g.Expect(provider.Status.ObservedGeneration).To(BeZero())
+
+	// Removing the annotation resumes the reconciliation.
+	provider.SetAnnotations(nil)
+	g.Expect(cl.Update(ctx, provider)).To(Succeed())
+
+	_, err = r.Reconcile(ctx, req)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(phaseCalled).To(BeTrue())
+
+	g.Expect(cl.Get(ctx, client.ObjectKeyFromObject(provider), provider)).To(Succeed())
+	g.Expect(conditions.Get(provider, clusterv1.PausedCondition)).To(BeNil())
+	g.Expect(provider.Status.ObservedGeneration).To(Equal(provider.Generation))
+}
+
 func TestNormalizeExistingConditions(t *testing.T) {
 	tests := []struct {
 		name             string
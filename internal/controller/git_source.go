@@ -0,0 +1,131 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"path"
+
+	corev1 "k8s.io/api/core/v1"
+	configclient "sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	utilyaml "sigs.k8s.io/cluster-api/util/yaml"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
+	"sigs.k8s.io/cluster-api-operator/internal/git"
+	"sigs.k8s.io/cluster-api-operator/internal/kustomize"
+)
+
+const (
+	GitUsernameKey = "GIT_USERNAME"
+	GitPasswordKey = "GIT_PASSWORD" // #nosec G101
+
+	// gitCommitAnnotation records the commit the manifests were built from.
+	gitCommitAnnotation = "provider.cluster.x-k8s.io/git-commit"
+
+	defaultGitPath         = "config/default"
+	defaultGitMetadataPath = "metadata.yaml"
+)
+
+// isGitProvider returns true if the provider components are built from a git repository.
+func isGitProvider(provider operatorv1.GenericProvider) bool {
+	return provider.GetSpec().FetchConfig != nil && provider.GetSpec().FetchConfig.Git != nil
+}
+
+// gitRef returns the git reference the provider components are built from.
+func gitRef(provider operatorv1.GenericProvider) string {
+	return cmp.Or(provider.GetSpec().FetchConfig.Git.Ref, providerVersion(provider))
+}
+
+// GitConfigMap fetches the git repository of the provider, renders the components from the configured directory,
+// and returns the ConfigMap with the provider manifests.
+func GitConfigMap(ctx context.Context, provider operatorv1.GenericProvider, variables configclient.VariablesClient) (*corev1.ConfigMap, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	gitConfig := provider.GetSpec().FetchConfig.Git
+
+	username, _ := variables.Get(GitUsernameKey)
+	password, _ := variables.Get(GitPasswordKey)
+
+	// Kustomizations refer to the directories next to them, like ../crd, so the parent directory of the
+	// rendered directory is read.
+	checkout, err := git.Clone(ctx, git.Options{
+		URL:      gitConfig.URL,
+		Ref:      gitRef(provider),
+		Paths:    []string{cmp.Or(gitConfig.MetadataPath, defaultGitMetadataPath), path.Dir(path.Clean(cmp.Or(gitConfig.Path, defaultGitPath)))},
+		Username: username,
+		Password: password,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %q from git repository %s: %w", gitRef(provider), gitConfig.URL, err)
+	}
+
+	log.Info("Fetched git repository", "url", gitConfig.URL, "ref", gitRef(provider), "commit", checkout.Commit)
+
+	metadata, components, err := renderGitManifests(checkout.Files, gitConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render manifests from git repository %s at commit %s: %w", gitConfig.URL, checkout.Commit, err)
+	}
+
+	configMap, err := TemplateManifestsConfigMap(provider, ProviderLabels(provider), metadata, components, needToCompress(metadata, components))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config map for provider %q: %w", provider.GetName(), err)
+	}
+
+	configMap.Annotations[gitCommitAnnotation] = checkout.Commit
+
+	if provider.GetUID() == "" {
+		// Unset owner references due to lack of existing provider owner object
+		configMap.OwnerReferences = nil
+	}
+
+	return configMap, nil
+}
+
+// renderGitManifests returns the metadata, and the components rendered from the kustomization or the plain
+// manifests of the configured directory.
+func renderGitManifests(files map[string][]byte, gitConfig *operatorv1.GitConfiguration) ([]byte, []byte, error) {
+	metadataPath := path.Clean(cmp.Or(gitConfig.MetadataPath, defaultGitMetadataPath))
+
+	metadata, ok := files[metadataPath]
+	if !ok {
+		return nil, nil, fmt.Errorf("metadata file %q not found", metadataPath)
+	}
+
+	// The metadata file is not a component, even if it is in the rendered directory.
+	manifests := kustomize.Files{}
+
+	for name, data := range files {
+		if name != metadataPath {
+			manifests[name] = data
+		}
+	}
+
+	objs, err := kustomize.Render(manifests, cmp.Or(gitConfig.Path, defaultGitPath))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	components, err := utilyaml.FromUnstructured(objs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return metadata, components, nil
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	utilyaml "sigs.k8s.io/cluster-api/util/yaml"
+
+	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
+)
+
+func TestRenderGitManifests(t *testing.T) {
+	metadata := []byte("apiVersion: clusterctl.cluster.x-k8s.io/v1alpha3\nkind: Metadata\n")
+	namespace := []byte("apiVersion: v1\nkind: Namespace\nmetadata:\n  name: system\n")
+	deployment := []byte("apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: controller-manager\n  namespace: system\n")
+
+	testCases := []struct {
+		name          string
+		files         map[string][]byte
+		gitConfig     *operatorv1.GitConfiguration
+		expectedKinds []string
+		expectedErr   bool
+	}{
+		{
+			name: "kustomization in the default path",
+			files: map[string][]byte{
+				"metadata.yaml":                     metadata,
+				"config/default/kustomization.yaml": []byte("namespace: capi-system\nresources:\n- ../manager\n"),
+				"config/manager/kustomization.yaml": []byte("resources:\n- manager.yaml\n- namespace.yaml\n"),
+				"config/manager/namespace.yaml":     namespace,
+				"config/manager/manager.yaml":       deployment,
+			},
+			gitConfig:     &operatorv1.GitConfiguration{URL: "https://example.com/provider.git"},
+			expectedKinds: []string{"Deployment", "Namespace"},
+		},
+		{
+			name: "plain manifests next to the metadata file",
+			files: map[string][]byte{
+				"release/metadata.yaml":                  metadata,
+				"release/infrastructure-components.yaml": append(append(namespace, []byte("---\n")...), deployment...),
+			},
+			gitConfig: &operatorv1.GitConfiguration{
+				URL:          "https://example.com/provider.git",
+				Path:         "release",
+				MetadataPath: "release/metadata.yaml",
+			},
+			expectedKinds: []string{"Namespace", "Deployment"},
+		},
+		{
+			name: "missing metadata file",
+			files: map[string][]byte{
+				"config/default/manager.yaml": deployment,
+			},
+			gitConfig:   &operatorv1.GitConfiguration{URL: "https://example.com/provider.git"},
+			expectedErr: true,
+		},
+		{
+			name: "missing components directory",
+			files: map[string][]byte{
+				"metadata.yaml": metadata,
+			},
+			gitConfig:   &operatorv1.GitConfiguration{URL: "https://example.com/provider.git"},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			renderedMetadata, components, err := renderGitManifests(tc.files, tc.gitConfig)
+			if tc.expectedErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(renderedMetadata).To(Equal(metadata))
+
+			objs, err := utilyaml.ToUnstructured(components)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			kinds := []string{}
+			for _, obj := range objs {
+				kinds = append(kinds, obj.GetKind())
+			}
+
+			g.Expect(kinds).To(Equal(tc.expectedKinds))
+		})
+	}
+}
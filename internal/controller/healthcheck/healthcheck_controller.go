@@ -29,6 +29,7 @@ import (
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
 	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -157,6 +158,13 @@ func (r *GenericProviderHealthCheckReconciler) Reconcile(ctx context.Context, de
 		return result, err
 	}
 
+	// The provider health is not updated while the provider is paused.
+	if annotations.HasPaused(typedProvider) {
+		log.V(2).Info("Provider is paused, skipping health check")
+
+		return result, nil
+	}
+
 	deploymentAvailableCondition := getDeploymentCondition(deployment.Status, appsv1.DeploymentAvailable)
 
 	// Stop earlier if this provider is not fully installed yet.
@@ -23,9 +23,12 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
 )
@@ -198,3 +201,51 @@ func TestReconcilerReadyConditions(t *testing.T) {
 		})
 	}
 }
+
+func TestReconcilePausedProvider(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(appsv1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(operatorv1.AddToScheme(scheme)).To(Succeed())
+
+	provider := &operatorv1.CoreProvider{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "cluster-api",
+			Namespace:   "capi-system",
+			Annotations: map[string]string{clusterv1.PausedAnnotation: ""},
+		},
+		Status: operatorv1.CoreProviderStatus{ProviderStatus: operatorv1.ProviderStatus{
+			Conditions: []metav1.Condition{{
+				Type:   operatorv1.ProviderInstalledCondition,
+				Status: metav1.ConditionTrue,
+				Reason: "ProviderInstalled",
+			}},
+		}},
+	}
+
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "capi-controller-manager",
+			Namespace:       "capi-system",
+			OwnerReferences: []metav1.OwnerReference{{Kind: "CoreProvider", Name: "cluster-api"}},
+		},
+		Status: appsv1.DeploymentStatus{
+			Conditions: []appsv1.DeploymentCondition{{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue}},
+		},
+	}
+
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(provider, deployment).WithStatusSubresource(provider).Build()
+
+	r := &GenericProviderHealthCheckReconciler{
+		Client:      cl,
+		Provider:    &operatorv1.CoreProvider{},
+		providerGVK: operatorv1.GroupVersion.WithKind("CoreProvider"),
+	}
+
+	_, err := r.Reconcile(ctx, deployment)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(cl.Get(ctx, client.ObjectKeyFromObject(provider), provider)).To(Succeed())
+	g.Expect(conditions.Get(provider, clusterv1.ReadyCondition)).To(BeNil())
+}
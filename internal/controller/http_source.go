@@ -0,0 +1,352 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	versionutil "k8s.io/apimachinery/pkg/util/version"
+	configclient "sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/repository"
+
+	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
+	"sigs.k8s.io/cluster-api-operator/util"
+)
+
+const (
+	HTTPUsernameKey    = "HTTP_USERNAME"
+	HTTPPasswordKey    = "HTTP_PASSWORD"
+	HTTPBearerTokenKey = "HTTP_BEARER_TOKEN" // #nosec G101
+
+	S3AccessKeyIDKey     = "S3_ACCESS_KEY_ID"
+	S3SecretAccessKeyKey = "S3_SECRET_ACCESS_KEY" // #nosec G101
+	S3SessionTokenKey    = "S3_SESSION_TOKEN"     // #nosec G101
+	S3RegionKey          = "S3_REGION"
+
+	defaultS3Region = "us-east-1"
+
+	httpSourceTimeout = 30 * time.Second
+
+	// httpSourceMaxFileSize is the maximum size of a file downloaded from a HTTP(S) server or an S3-compatible bucket.
+	httpSourceMaxFileSize = 64 << 20
+)
+
+// httpIndexVersionLink matches the links to version directories in an HTML directory listing.
+var httpIndexVersionLink = regexp.MustCompile(`href="(?:[^"]*/)?(v?[0-9][^"/]*)/"`)
+
+// httpRepository is a repository.Repository fetching the provider files from "<url>/<version>/<file>"
+// on a HTTP(S) file server or an S3-compatible bucket.
+type httpRepository struct {
+	baseURL        *url.URL
+	componentsPath string
+	defaultVersion string
+	variables      configclient.VariablesClient
+	client         *http.Client
+
+	// s3 is the client of the S3-compatible endpoint, with the bucket and the prefix of the base URL.
+	s3       *minio.Client
+	bucket   string
+	s3Prefix string
+}
+
+var _ repository.Repository = &httpRepository{}
+
+// isHTTPProvider returns true if the provider manifests are fetched from a HTTP(S) server or an S3-compatible bucket.
+func isHTTPProvider(provider operatorv1.GenericProvider) bool {
+	return provider.GetSpec().FetchConfig != nil && provider.GetSpec().FetchConfig.HTTP != nil
+}
+
+// NewHTTPRepository returns a repository fetching the provider files from the HTTP(S) server or the S3-compatible
+// bucket of the provider fetch configuration, authenticated with the provider variables. The TLS configuration,
+// if set, is used to verify the server certificate and to present a client certificate.
+func NewHTTPRepository(ctx context.Context, provider operatorv1.GenericProvider, variables configclient.VariablesClient, tlsConfig *tls.Config) (repository.Repository, error) {
+	httpConfig := provider.GetSpec().FetchConfig.HTTP
+
+	baseURL, err := url.Parse(strings.TrimSuffix(httpConfig.URL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid HTTP source URL %q: %w", httpConfig.URL, err)
+	}
+
+	if baseURL.Scheme != "http" && baseURL.Scheme != "https" {
+		return nil, fmt.Errorf("invalid HTTP source URL %q: scheme must be http or https", httpConfig.URL)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	repo := &httpRepository{
+		baseURL:        baseURL,
+		componentsPath: fmt.Sprintf(typedComponentsFile, provider.GetType()),
+		defaultVersion: providerVersion(provider),
+		variables:      variables,
+		client:         &http.Client{Timeout: httpSourceTimeout, Transport: transport},
+	}
+
+	if httpConfig.S3 {
+		if err := repo.setS3Client(transport); err != nil {
+			return nil, fmt.Errorf("invalid S3 source URL %q: %w", httpConfig.URL, err)
+		}
+	}
+
+	if repo.defaultVersion == "" {
+		versions, err := repo.GetVersions(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		repo.defaultVersion, err = getLatestVersion(versions)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return repo, nil
+}
+
+// setS3Client configures the client of the S3-compatible endpoint of the base URL, which is expected in the
+// path-style "<endpoint>/<bucket>/<prefix>" format. The requests are signed with the S3 variables, if set.
+func (r *httpRepository) setS3Client(transport http.RoundTripper) error {
+	bucket, prefix, _ := strings.Cut(strings.TrimPrefix(r.baseURL.Path, "/"), "/")
+	if bucket == "" {
+		return errors.New("URL has no bucket")
+	}
+
+	accessKeyID, _ := r.variables.Get(S3AccessKeyIDKey)
+	secretAccessKey, _ := r.variables.Get(S3SecretAccessKeyKey)
+	sessionToken, _ := r.variables.Get(S3SessionTokenKey)
+
+	region, _ := r.variables.Get(S3RegionKey)
+	if region == "" {
+		region = defaultS3Region
+	}
+
+	client, err := minio.New(r.baseURL.Host, &minio.Options{
+		Creds:        credentials.NewStaticV4(accessKeyID, secretAccessKey, sessionToken),
+		Secure:       r.baseURL.Scheme == "https",
+		Transport:    transport,
+		Region:       region,
+		BucketLookup: minio.BucketLookupPath,
+	})
+	if err != nil {
+		return err
+	}
+
+	r.s3 = client
+	r.bucket = bucket
+	r.s3Prefix = prefix
+
+	if r.s3Prefix != "" {
+		r.s3Prefix += "/"
+	}
+
+	return nil
+}
+
+// DefaultVersion implements repository.Repository.
+func (r *httpRepository) DefaultVersion() string {
+	return r.defaultVersion
+}
+
+// RootPath implements repository.Repository.
+func (r *httpRepository) RootPath() string {
+	return ""
+}
+
+// ComponentsPath implements repository.Repository.
+func (r *httpRepository) ComponentsPath() string {
+	return r.componentsPath
+}
+
+// URL implements repository.Repository.
+func (r *httpRepository) URL() string {
+	return r.baseURL.String()
+}
+
+// GetFile implements repository.Repository.
+func (r *httpRepository) GetFile(ctx context.Context, version, path string) ([]byte, error) {
+	fileURL := r.baseURL.JoinPath(version, path)
+
+	var (
+		data []byte
+		err  error
+	)
+
+	if r.s3 != nil {
+		data, err = r.getS3Object(ctx, r.s3Prefix+version+"/"+path)
+	} else {
+		data, err = r.get(ctx, fileURL)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %q: %w", fileURL.Redacted(), err)
+	}
+
+	return data, nil
+}
+
+// GetVersions implements repository.Repository. The versions are the directories of the HTML index of a HTTP(S)
+// server, or the prefixes under the URL of an S3-compatible bucket.
+func (r *httpRepository) GetVersions(ctx context.Context) ([]string, error) {
+	var (
+		candidates []string
+		err        error
+	)
+
+	if r.s3 != nil {
+		candidates, err = r.listS3Prefixes(ctx)
+	} else {
+		candidates, err = r.listIndexDirectories(ctx)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list versions from %q: %w", r.baseURL.Redacted(), err)
+	}
+
+	versions := []string{}
+
+	for _, candidate := range candidates {
+		if _, err := versionutil.ParseSemantic(candidate); err == nil {
+			versions = append(versions, candidate)
+		}
+	}
+
+	return versions, nil
+}
+
+// listIndexDirectories returns the directories linked from the HTML index of the base URL.
+func (r *httpRepository) listIndexDirectories(ctx context.Context) ([]string, error) {
+	index, err := r.get(ctx, r.baseURL.JoinPath("/"))
+	if err != nil {
+		return nil, err
+	}
+
+	directories := []string{}
+
+	for _, match := range httpIndexVersionLink.FindAllStringSubmatch(string(index), -1) {
+		directories = append(directories, match[1])
+	}
+
+	return directories, nil
+}
+
+// listS3Prefixes returns the prefixes directly under the prefix of the base URL in the bucket.
+func (r *httpRepository) listS3Prefixes(ctx context.Context) ([]string, error) {
+	prefixes := []string{}
+
+	for object := range r.s3.ListObjects(ctx, r.bucket, minio.ListObjectsOptions{Prefix: r.s3Prefix}) {
+		if object.Err != nil {
+			return nil, object.Err
+		}
+
+		// Common prefixes are listed as keys ending with the delimiter.
+		if prefix, ok := strings.CutSuffix(strings.TrimPrefix(object.Key, r.s3Prefix), "/"); ok {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+
+	return prefixes, nil
+}
+
+// getS3Object downloads the content of the object in the bucket.
+func (r *httpRepository) getS3Object(ctx context.Context, key string) ([]byte, error) {
+	object, err := r.s3.GetObject(ctx, r.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer object.Close()
+
+	return readHTTPSourceFile(object)
+}
+
+// get downloads the content of the URL.
+func (r *httpRepository) get(ctx context.Context, target *url.URL) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	r.authenticate(req)
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response status %q", resp.Status)
+	}
+
+	return readHTTPSourceFile(resp.Body)
+}
+
+// readHTTPSourceFile reads the downloaded file, up to the maximum file size.
+func readHTTPSourceFile(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, httpSourceMaxFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) > httpSourceMaxFileSize {
+		return nil, fmt.Errorf("file is larger than %d bytes", httpSourceMaxFileSize)
+	}
+
+	return data, nil
+}
+
+// authenticate adds the credentials of the provider variables to the request.
+func (r *httpRepository) authenticate(req *http.Request) {
+	if token, _ := r.variables.Get(HTTPBearerTokenKey); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		return
+	}
+
+	username, _ := r.variables.Get(HTTPUsernameKey)
+	password, _ := r.variables.Get(HTTPPasswordKey)
+
+	if username != "" || password != "" {
+		req.SetBasicAuth(username, password)
+	}
+}
+
+// fetchRepository returns the repository the provider manifests are downloaded from.
+func (p *PhaseReconciler) fetchRepository(ctx context.Context, provider operatorv1.GenericProvider, providerConfig configclient.Provider) (repository.Repository, error) {
+	if isHTTPProvider(provider) {
+		httpConfig := provider.GetSpec().FetchConfig.HTTP
+
+		tlsConfig, err := secretTLSConfig(ctx, p.ctrlClient, provider, httpConfig.CASecretRef, httpConfig.ClientCertSecretRef)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewHTTPRepository(ctx, provider, p.configClient.Variables(), tlsConfig)
+	}
+
+	return util.RepositoryFactory(ctx, providerConfig, p.configClient.Variables())
+}
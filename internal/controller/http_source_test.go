@@ -0,0 +1,258 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	configclient "sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+
+	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
+)
+
+const (
+	httpTestMetadata   = "apiVersion: clusterctl.cluster.x-k8s.io/v1alpha3\nkind: Metadata\n"
+	httpTestComponents = "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: capi-system\n"
+)
+
+// httpTestFiles are the provider files served by the test servers.
+var httpTestFiles = map[string]string{
+	"v1.0.0/metadata.yaml":        httpTestMetadata,
+	"v1.0.0/core-components.yaml": httpTestComponents,
+	"v1.1.0/metadata.yaml":        httpTestMetadata,
+	"v1.1.0/core-components.yaml": httpTestComponents,
+}
+
+// newHTTPTestServer starts a file server with a directory index, requiring basic authentication.
+func newHTTPTestServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username, password, ok := r.BasicAuth(); !ok || username != "user" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if r.URL.Path == "/providers/core/" {
+			fmt.Fprint(w, `<html><body><a href="../">../</a><a href="v1.0.0/">v1.0.0/</a><a href="v1.1.0/">v1.1.0/</a><a href="latest/">latest/</a></body></html>`)
+			return
+		}
+
+		file, ok := httpTestFiles[strings.TrimPrefix(r.URL.Path, "/providers/core/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		fmt.Fprint(w, file)
+	}))
+}
+
+// newS3TestServer starts an S3-compatible endpoint serving the "artifacts" bucket, requiring signed requests.
+func newS3TestServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=access-key/") ||
+			r.Header.Get("X-Amz-Security-Token") != "session-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		if strings.TrimSuffix(r.URL.Path, "/") == "/artifacts" && r.URL.Query().Get("list-type") == "2" {
+			query := r.URL.Query()
+			if query.Get("prefix") != "providers/core/" || query.Get("delimiter") != "/" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			// Return the prefixes over two pages.
+			if query.Get("continuation-token") == "" {
+				fmt.Fprint(w, `<ListBucketResult><IsTruncated>true</IsTruncated><NextContinuationToken>next</NextContinuationToken>`+
+					`<CommonPrefixes><Prefix>providers/core/v1.0.0/</Prefix></CommonPrefixes></ListBucketResult>`)
+
+				return
+			}
+
+			fmt.Fprint(w, `<ListBucketResult><IsTruncated>false</IsTruncated>`+
+				`<CommonPrefixes><Prefix>providers/core/v1.1.0/</Prefix></CommonPrefixes>`+
+				`<CommonPrefixes><Prefix>providers/core/latest/</Prefix></CommonPrefixes></ListBucketResult>`)
+
+			return
+		}
+
+		file, ok := httpTestFiles[strings.TrimPrefix(r.URL.Path, "/artifacts/providers/core/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		// Objects are served with the metadata headers of S3.
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		w.Header().Set("ETag", fmt.Sprintf("%q", fmt.Sprintf("%x", sha256.Sum256([]byte(file)))))
+		fmt.Fprint(w, file)
+	}))
+}
+
+func TestHTTPRepository(t *testing.T) {
+	httpServer := newHTTPTestServer()
+	defer httpServer.Close()
+
+	s3Server := newS3TestServer()
+	defer s3Server.Close()
+
+	httpsServer := httptest.NewTLSServer(newHTTPTestServer().Config.Handler)
+	defer httpsServer.Close()
+
+	testCases := []struct {
+		name            string
+		httpConfig      *operatorv1.HTTPConfiguration
+		version         string
+		variables       map[string]string
+		tlsConfig       *tls.Config
+		expectedVersion string
+		expectedErr     bool
+	}{
+		{
+			name:            "HTTP server with basic authentication",
+			httpConfig:      &operatorv1.HTTPConfiguration{URL: httpServer.URL + "/providers/core/"},
+			version:         "v1.0.0",
+			variables:       map[string]string{HTTPUsernameKey: "user", HTTPPasswordKey: "secret"},
+			expectedVersion: "v1.0.0",
+		},
+		{
+			name:            "HTTP server latest version from the directory index",
+			httpConfig:      &operatorv1.HTTPConfiguration{URL: httpServer.URL + "/providers/core"},
+			variables:       map[string]string{HTTPUsernameKey: "user", HTTPPasswordKey: "secret"},
+			expectedVersion: "v1.1.0",
+		},
+		{
+			name:        "HTTP server with invalid credentials",
+			httpConfig:  &operatorv1.HTTPConfiguration{URL: httpServer.URL + "/providers/core"},
+			variables:   map[string]string{HTTPUsernameKey: "user", HTTPPasswordKey: "wrong"},
+			expectedErr: true,
+		},
+		{
+			name:       "S3 bucket with signed requests",
+			httpConfig: &operatorv1.HTTPConfiguration{URL: s3Server.URL + "/artifacts/providers/core", S3: true},
+			variables: map[string]string{
+				S3AccessKeyIDKey:     "access-key",
+				S3SecretAccessKeyKey: "secret-key",
+				S3SessionTokenKey:    "session-token",
+				S3RegionKey:          "eu-west-1",
+			},
+			expectedVersion: "v1.1.0",
+		},
+		{
+			name:       "HTTPS server verified with the CA bundle",
+			httpConfig: &operatorv1.HTTPConfiguration{URL: httpsServer.URL + "/providers/core"},
+			variables:  map[string]string{HTTPUsernameKey: "user", HTTPPasswordKey: "secret"},
+			tlsConfig: func() *tls.Config {
+				pool := x509.NewCertPool()
+				pool.AddCert(httpsServer.Certificate())
+
+				return &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+			}(),
+			expectedVersion: "v1.1.0",
+		},
+		{
+			name:        "HTTPS server with an unknown certificate",
+			httpConfig:  &operatorv1.HTTPConfiguration{URL: httpsServer.URL + "/providers/core"},
+			variables:   map[string]string{HTTPUsernameKey: "user", HTTPPasswordKey: "secret"},
+			expectedErr: true,
+		},
+		{
+			name:        "S3 bucket without credentials",
+			httpConfig:  &operatorv1.HTTPConfiguration{URL: s3Server.URL + "/artifacts/providers/core", S3: true},
+			expectedErr: true,
+		},
+		{
+			name:        "unsupported scheme",
+			httpConfig:  &operatorv1.HTTPConfiguration{URL: "ftp://example.com/providers/core"},
+			version:     "v1.0.0",
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			reader := configclient.NewMemoryReader()
+			for key, value := range tc.variables {
+				reader.Set(key, value)
+			}
+
+			configClient, err := configclient.New(context.Background(), "", configclient.InjectReader(reader))
+			g.Expect(err).NotTo(HaveOccurred())
+
+			provider := &operatorv1.CoreProvider{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-api", Namespace: "capi-system"},
+				Spec: operatorv1.CoreProviderSpec{ProviderSpec: operatorv1.ProviderSpec{
+					Version:     tc.version,
+					FetchConfig: &operatorv1.FetchConfiguration{HTTP: tc.httpConfig},
+				}},
+			}
+
+			repo, err := NewHTTPRepository(context.Background(), provider, configClient.Variables(), tc.tlsConfig)
+			if tc.expectedErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(repo.DefaultVersion()).To(Equal(tc.expectedVersion))
+			g.Expect(repo.ComponentsPath()).To(Equal("core-components.yaml"))
+
+			provider.Spec.Version = repo.DefaultVersion()
+
+			configMap, err := RepositoryConfigMap(context.Background(), provider, repo)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(configMap.Data).To(HaveKeyWithValue(operatorv1.MetadataConfigMapKey, httpTestMetadata))
+			g.Expect(configMap.Data).To(HaveKeyWithValue(operatorv1.ComponentsConfigMapKey, httpTestComponents))
+			g.Expect(configMap.Annotations).To(HaveKeyWithValue(configMapSourceAnnotation, tc.httpConfig.URL))
+		})
+	}
+}
+
+func TestReadHTTPSourceFile(t *testing.T) {
+	g := NewWithT(t)
+
+	data, err := readHTTPSourceFile(strings.NewReader(httpTestComponents))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(data)).To(Equal(httpTestComponents))
+
+	_, err = readHTTPSourceFile(io.LimitReader(zeroReader{}, httpSourceMaxFileSize+1))
+	g.Expect(err).To(MatchError(ContainSubstring("file is larger than")))
+}
+
+// zeroReader is an endless reader of zero bytes.
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+
+	return len(p), nil
+}
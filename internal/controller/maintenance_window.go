@@ -0,0 +1,226 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
+)
+
+// maxMaintenanceWindowLookahead limits the search for the next maintenance window opening.
+const maxMaintenanceWindowLookahead = 31 * 24 * time.Hour
+
+// WaitForMaintenanceWindow holds a version change of the installed provider until its maintenance window opens,
+// before the manifests of the new version are downloaded.
+func (p *PhaseReconciler) WaitForMaintenanceWindow(ctx context.Context) (*Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	installedVersion := p.provider.GetStatus().InstalledVersion
+	window := p.provider.GetSpec().MaintenanceWindow
+
+	if window == nil || installedVersion == nil || *installedVersion == providerVersion(p.provider) {
+		conditions.Delete(p.provider, operatorv1.UpgradePendingCondition)
+
+		return &Result{}, nil
+	}
+
+	open, nextOpen, err := maintenanceWindowState(window, time.Now())
+	if err != nil {
+		return &Result{}, wrapPhaseError(err, operatorv1.InvalidMaintenanceWindowReason, operatorv1.UpgradePendingCondition)
+	}
+
+	if !open {
+		log.Info("Holding upgrade until the maintenance window opens", "version", providerVersion(p.provider), "windowOpens", nextOpen)
+		conditions.Set(p.provider, metav1.Condition{
+			Type:    operatorv1.UpgradePendingCondition,
+			Status:  metav1.ConditionTrue,
+			Reason:  operatorv1.WaitingForMaintenanceWindowReason,
+			Message: fmt.Sprintf("Upgrade to %s is held until the maintenance window opens at %s", providerVersion(p.provider), nextOpen.Format(time.RFC3339)),
+		})
+
+		return &Result{RequeueAfter: time.Until(nextOpen)}, nil
+	}
+
+	conditions.Delete(p.provider, operatorv1.UpgradePendingCondition)
+
+	return &Result{}, nil
+}
+
+// maintenanceWindowState returns whether the maintenance window is open at the given time, and if it's not,
+// when it opens next. If the window doesn't open within the lookahead, the end of the lookahead is returned.
+func maintenanceWindowState(window *operatorv1.MaintenanceWindow, now time.Time) (bool, time.Time, error) {
+	schedule, err := parseCronSchedule(window.Schedule)
+	if err != nil {
+		return false, time.Time{}, err
+	}
+
+	location := time.UTC
+
+	if window.TimeZone != "" {
+		location, err = time.LoadLocation(window.TimeZone)
+		if err != nil {
+			return false, time.Time{}, fmt.Errorf("invalid maintenance window time zone %q: %w", window.TimeZone, err)
+		}
+	}
+
+	now = now.In(location)
+	current := now.Truncate(time.Minute)
+
+	// The window is open if it was opened less than the window duration ago.
+	for start := current; now.Sub(start) < window.Duration.Duration; start = start.Add(-time.Minute) {
+		if schedule.matches(start) {
+			return true, time.Time{}, nil
+		}
+	}
+
+	limit := now.Add(maxMaintenanceWindowLookahead)
+
+	for start := current.Add(time.Minute); start.Before(limit); start = start.Add(time.Minute) {
+		if schedule.matches(start) {
+			return false, start, nil
+		}
+	}
+
+	return false, limit, nil
+}
+
+// cronSchedule is a parsed cron expression with five fields: minute, hour, day of month, month and day of week.
+type cronSchedule struct {
+	minutes, hours, daysOfMonth, months, daysOfWeek map[int]bool
+
+	// Following cron, if both day fields are restricted, a time matches if either of them matches.
+	anyDayOfMonth, anyDayOfWeek bool
+}
+
+// parseCronSchedule parses a standard cron expression, supporting "*", lists, ranges and steps.
+func parseCronSchedule(expression string) (*cronSchedule, error) {
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid maintenance window schedule %q: expected 5 fields, found %d", expression, len(fields))
+	}
+
+	bounds := []struct{ min, max int }{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+	sets := make([]map[int]bool, len(fields))
+
+	for i, field := range fields {
+		set, err := parseCronField(field, bounds[i].min, bounds[i].max)
+		if err != nil {
+			return nil, fmt.Errorf("invalid maintenance window schedule %q: %w", expression, err)
+		}
+
+		sets[i] = set
+	}
+
+	// Sunday can be set as 0 or 7.
+	if sets[4][7] {
+		sets[4][0] = true
+	}
+
+	return &cronSchedule{
+		minutes:       sets[0],
+		hours:         sets[1],
+		daysOfMonth:   sets[2],
+		months:        sets[3],
+		daysOfWeek:    sets[4],
+		anyDayOfMonth: strings.HasPrefix(fields[2], "*"),
+		anyDayOfWeek:  strings.HasPrefix(fields[4], "*"),
+	}, nil
+}
+
+// parseCronField returns the values matched by a cron field.
+func parseCronField(field string, minValue, maxValue int) (map[int]bool, error) {
+	values := map[int]bool{}
+
+	for _, part := range strings.Split(field, ",") {
+		valueRange, stepValue, hasStep := strings.Cut(part, "/")
+
+		step := 1
+
+		if hasStep {
+			var err error
+
+			step, err = strconv.Atoi(stepValue)
+			if err != nil || step <= 0 {
+				return nil, fmt.Errorf("invalid step in %q", part)
+			}
+		}
+
+		low, high := minValue, maxValue
+
+		if valueRange != "*" {
+			lowValue, highValue, isRange := strings.Cut(valueRange, "-")
+
+			var err error
+
+			low, err = strconv.Atoi(lowValue)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value in %q", part)
+			}
+
+			high = low
+
+			if isRange {
+				high, err = strconv.Atoi(highValue)
+				if err != nil {
+					return nil, fmt.Errorf("invalid value in %q", part)
+				}
+			} else if hasStep {
+				high = maxValue
+			}
+		}
+
+		if low < minValue || high > maxValue || low > high {
+			return nil, fmt.Errorf("value out of range [%d-%d] in %q", minValue, maxValue, part)
+		}
+
+		for v := low; v <= high; v += step {
+			values[v] = true
+		}
+	}
+
+	return values, nil
+}
+
+// matches returns true if the schedule fires at the given minute.
+func (s *cronSchedule) matches(t time.Time) bool {
+	if !s.minutes[t.Minute()] || !s.hours[t.Hour()] || !s.months[int(t.Month())] {
+		return false
+	}
+
+	dayOfMonth := s.daysOfMonth[t.Day()]
+	dayOfWeek := s.daysOfWeek[int(t.Weekday())]
+
+	switch {
+	case s.anyDayOfMonth && s.anyDayOfWeek:
+		return true
+	case s.anyDayOfMonth:
+		return dayOfWeek
+	case s.anyDayOfWeek:
+		return dayOfMonth
+	default:
+		return dayOfMonth || dayOfWeek
+	}
+}
@@ -0,0 +1,298 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
+)
+
+func TestParseCronSchedule(t *testing.T) {
+	testCases := []struct {
+		schedule    string
+		matching    []string
+		notMatching []string
+		expectedErr bool
+	}{
+		{
+			schedule:    "0 2 * * 6",
+			matching:    []string{"2026-10-17T02:00:00Z"},
+			notMatching: []string{"2026-10-17T02:01:00Z", "2026-10-16T02:00:00Z"},
+		},
+		{
+			schedule:    "*/15 22-23 * * *",
+			matching:    []string{"2026-10-16T22:00:00Z", "2026-10-16T23:45:00Z"},
+			notMatching: []string{"2026-10-16T22:10:00Z", "2026-10-16T21:00:00Z"},
+		},
+		{
+			schedule:    "30 1 1,15 * *",
+			matching:    []string{"2026-10-01T01:30:00Z", "2026-10-15T01:30:00Z"},
+			notMatching: []string{"2026-10-02T01:30:00Z"},
+		},
+		{
+			schedule:    "0 0 * * 7",
+			matching:    []string{"2026-10-18T00:00:00Z"},
+			notMatching: []string{"2026-10-17T00:00:00Z"},
+		},
+		{
+			schedule:    "0 0 1 * 1",
+			matching:    []string{"2026-10-01T00:00:00Z", "2026-10-05T00:00:00Z"},
+			notMatching: []string{"2026-10-06T00:00:00Z"},
+		},
+		{
+			schedule:    "0 2 * *",
+			expectedErr: true,
+		},
+		{
+			schedule:    "60 2 * * *",
+			expectedErr: true,
+		},
+		{
+			schedule:    "*/0 2 * * *",
+			expectedErr: true,
+		},
+		{
+			schedule:    "0 5-2 * * *",
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.schedule, func(t *testing.T) {
+			g := NewWithT(t)
+
+			schedule, err := parseCronSchedule(tc.schedule)
+			if tc.expectedErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+
+			for _, v := range tc.matching {
+				ts, err := time.Parse(time.RFC3339, v)
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(schedule.matches(ts)).To(BeTrue(), v)
+			}
+
+			for _, v := range tc.notMatching {
+				ts, err := time.Parse(time.RFC3339, v)
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(schedule.matches(ts)).To(BeFalse(), v)
+			}
+		})
+	}
+}
+
+func TestMaintenanceWindowState(t *testing.T) {
+	testCases := []struct {
+		name             string
+		window           *operatorv1.MaintenanceWindow
+		now              string
+		expectedOpen     bool
+		expectedNextOpen string
+		expectedErr      bool
+	}{
+		{
+			name:         "window open",
+			window:       &operatorv1.MaintenanceWindow{Schedule: "0 2 * * 6", Duration: metav1.Duration{Duration: 2 * time.Hour}},
+			now:          "2026-10-17T03:30:00Z",
+			expectedOpen: true,
+		},
+		{
+			name:             "window closed",
+			window:           &operatorv1.MaintenanceWindow{Schedule: "0 2 * * 6", Duration: metav1.Duration{Duration: 2 * time.Hour}},
+			now:              "2026-10-17T04:00:00Z",
+			expectedNextOpen: "2026-10-24T02:00:00Z",
+		},
+		{
+			name:             "window in time zone",
+			window:           &operatorv1.MaintenanceWindow{Schedule: "0 2 * * *", Duration: metav1.Duration{Duration: time.Hour}, TimeZone: "Europe/Berlin"},
+			now:              "2026-10-16T02:30:00Z",
+			expectedNextOpen: "2026-10-17T00:00:00Z",
+		},
+		{
+			name:         "window open in time zone",
+			window:       &operatorv1.MaintenanceWindow{Schedule: "0 2 * * *", Duration: metav1.Duration{Duration: time.Hour}, TimeZone: "Europe/Berlin"},
+			now:          "2026-10-16T00:30:00Z",
+			expectedOpen: true,
+		},
+		{
+			name:        "invalid time zone",
+			window:      &operatorv1.MaintenanceWindow{Schedule: "0 2 * * *", Duration: metav1.Duration{Duration: time.Hour}, TimeZone: "Mars/Olympus"},
+			now:         "2026-10-16T00:30:00Z",
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			now, err := time.Parse(time.RFC3339, tc.now)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			open, nextOpen, err := maintenanceWindowState(tc.window, now)
+			if tc.expectedErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(open).To(Equal(tc.expectedOpen))
+
+			if !tc.expectedOpen {
+				expected, err := time.Parse(time.RFC3339, tc.expectedNextOpen)
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(nextOpen.Equal(expected)).To(BeTrue(), nextOpen.String())
+			}
+		})
+	}
+}
+
+func TestUpgradeHeldUntilMaintenanceWindow(t *testing.T) {
+	g := NewWithT(t)
+
+	provider := &operatorv1.CoreProvider{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-api", Namespace: "capi-system"},
+		Spec: operatorv1.CoreProviderSpec{
+			ProviderSpec: operatorv1.ProviderSpec{
+				Version: "v1.12.0",
+				MaintenanceWindow: &operatorv1.MaintenanceWindow{
+					// The window opened a day ago and is already closed.
+					Schedule: time.Now().UTC().Add(-24 * time.Hour).Format("4 15 2 1 *"),
+					Duration: metav1.Duration{Duration: time.Minute},
+				},
+			},
+		},
+		Status: operatorv1.CoreProviderStatus{
+			ProviderStatus: operatorv1.ProviderStatus{InstalledVersion: ptr.To("v1.11.0")},
+		},
+	}
+
+	p := &PhaseReconciler{provider: provider}
+
+	// The version change is held before the manifests of the new version are downloaded.
+	result, err := p.WaitForMaintenanceWindow(context.Background())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+
+	condition := conditions.Get(provider, operatorv1.UpgradePendingCondition)
+	g.Expect(condition).NotTo(BeNil())
+	g.Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+	g.Expect(condition.Reason).To(Equal(operatorv1.WaitingForMaintenanceWindowReason))
+
+	// The upgrade is held too, if the version changed after the maintenance window was checked.
+	result, err = p.Upgrade(context.Background())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+	g.Expect(provider.Status.InstalledVersion).To(HaveValue(Equal("v1.11.0")))
+
+	// The condition is removed once the installed version matches the spec.
+	provider.Status.InstalledVersion = ptr.To("v1.12.0")
+
+	result, err = p.WaitForMaintenanceWindow(context.Background())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.IsZero()).To(BeTrue())
+	g.Expect(conditions.Get(provider, operatorv1.UpgradePendingCondition)).To(BeNil())
+}
+
+func TestWaitForMaintenanceWindow(t *testing.T) {
+	window := &operatorv1.MaintenanceWindow{
+		// The window opened a day ago and is already closed.
+		Schedule: time.Now().UTC().Add(-24 * time.Hour).Format("4 15 2 1 *"),
+		Duration: metav1.Duration{Duration: time.Minute},
+	}
+
+	testCases := []struct {
+		name             string
+		window           *operatorv1.MaintenanceWindow
+		installedVersion *string
+		expectedHold     bool
+		expectedErr      bool
+	}{
+		{
+			name:             "no maintenance window",
+			installedVersion: ptr.To("v1.11.0"),
+		},
+		{
+			name:   "fresh installation",
+			window: window,
+		},
+		{
+			name:             "version unchanged",
+			window:           window,
+			installedVersion: ptr.To("v1.12.0"),
+		},
+		{
+			name:             "version change outside of the window",
+			window:           window,
+			installedVersion: ptr.To("v1.11.0"),
+			expectedHold:     true,
+		},
+		{
+			name: "version change inside of the window",
+			window: &operatorv1.MaintenanceWindow{
+				Schedule: "* * * * *",
+				Duration: metav1.Duration{Duration: time.Hour},
+			},
+			installedVersion: ptr.To("v1.11.0"),
+		},
+		{
+			name:             "invalid window",
+			window:           &operatorv1.MaintenanceWindow{Schedule: "invalid", Duration: metav1.Duration{Duration: time.Hour}},
+			installedVersion: ptr.To("v1.11.0"),
+			expectedErr:      true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			provider := &operatorv1.CoreProvider{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-api", Namespace: "capi-system"},
+				Spec: operatorv1.CoreProviderSpec{
+					ProviderSpec: operatorv1.ProviderSpec{Version: "v1.12.0", MaintenanceWindow: tc.window},
+				},
+				Status: operatorv1.CoreProviderStatus{
+					ProviderStatus: operatorv1.ProviderStatus{InstalledVersion: tc.installedVersion},
+				},
+			}
+
+			p := &PhaseReconciler{provider: provider}
+
+			result, err := p.WaitForMaintenanceWindow(context.Background())
+			if tc.expectedErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(result.RequeueAfter > 0).To(Equal(tc.expectedHold))
+			g.Expect(conditions.Has(provider, operatorv1.UpgradePendingCondition)).To(Equal(tc.expectedHold))
+		})
+	}
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/repository"
+
+	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
+)
+
+const (
+	componentsChecksumAnnotation = "provider.cluster.x-k8s.io/components-sha256"
+	metadataChecksumAnnotation   = "provider.cluster.x-k8s.io/metadata-sha256"
+)
+
+// ErrChecksumVerification is returned when the checksum of the provider manifests doesn't match the expected one.
+var ErrChecksumVerification = errors.New("manifests checksum verification failed")
+
+// manifestChecksums are the expected sha256 checksums of the provider manifests, as hex strings.
+type manifestChecksums struct {
+	components string
+	metadata   string
+}
+
+// expectedManifestChecksums returns the checksums the provider manifests are verified against, or nil if
+// the verification is not configured. Checksums set in the spec take precedence over the checksum file.
+func expectedManifestChecksums(ctx context.Context, provider operatorv1.GenericProvider, repo repository.Repository) (*manifestChecksums, error) {
+	fetchConfig := provider.GetSpec().FetchConfig
+	if fetchConfig == nil || fetchConfig.Checksums == nil {
+		return nil, nil
+	}
+
+	checksums := &manifestChecksums{
+		components: fetchConfig.Checksums.Components,
+		metadata:   fetchConfig.Checksums.Metadata,
+	}
+
+	if fetchConfig.Checksums.File == "" || (checksums.components != "" && checksums.metadata != "") {
+		return checksums, nil
+	}
+
+	file, err := repo.GetFile(ctx, providerVersion(provider), fetchConfig.Checksums.File)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read checksum file %q: %w", ErrChecksumVerification, fetchConfig.Checksums.File, err)
+	}
+
+	published, err := parseChecksumFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid checksum file %q: %w", ErrChecksumVerification, fetchConfig.Checksums.File, err)
+	}
+
+	for name, checksum := range map[string]*string{
+		path.Base(repo.ComponentsPath()): &checksums.components,
+		metadataFile:                     &checksums.metadata,
+	} {
+		if *checksum != "" {
+			continue
+		}
+
+		*checksum = published[name]
+		if *checksum == "" {
+			return nil, fmt.Errorf("%w: checksum file %q has no entry for %q", ErrChecksumVerification, fetchConfig.Checksums.File, name)
+		}
+	}
+
+	return checksums, nil
+}
+
+// parseChecksumFile parses a file in the sha256sum format and returns the checksums by file name.
+func parseChecksumFile(data []byte) (map[string]string, error) {
+	checksums := map[string]string{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("unexpected line %q", line)
+		}
+
+		// sha256sum marks files read in binary mode with a leading "*".
+		checksums[path.Base(strings.TrimPrefix(fields[1], "*"))] = strings.ToLower(fields[0])
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return checksums, nil
+}
+
+// verifyChecksum compares the sha256 checksum of the data with the expected one, if set,
+// and returns the checksum of the data.
+func verifyChecksum(name string, data []byte, expected string) (string, error) {
+	sum := sha256.Sum256(data)
+	checksum := hex.EncodeToString(sum[:])
+
+	if expected != "" && checksum != expected {
+		return "", fmt.Errorf("%w: %s has sha256 checksum %s, expected %s", ErrChecksumVerification, name, checksum, expected)
+	}
+
+	return checksum, nil
+}
+
+// verifyManifests verifies the checksums of the provider metadata and components, and records
+// the verified checksums as annotations on the ConfigMap.
+func verifyManifests(configMap *corev1.ConfigMap, checksums *manifestChecksums, componentsPath string, metadata, components []byte) error {
+	metadataChecksum, err := verifyChecksum(metadataFile, metadata, checksums.metadata)
+	if err != nil {
+		return err
+	}
+
+	componentsChecksum, err := verifyChecksum(componentsPath, components, checksums.components)
+	if err != nil {
+		return err
+	}
+
+	annotations := configMap.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	annotations[metadataChecksumAnnotation] = metadataChecksum
+	annotations[componentsChecksumAnnotation] = componentsChecksum
+
+	configMap.SetAnnotations(annotations)
+
+	return nil
+}
+
+// checksumsOutdated returns true if the checksums recorded on the ConfigMap don't match the expected checksums.
+func checksumsOutdated(configMap *corev1.ConfigMap, checksums *manifestChecksums) bool {
+	if checksums == nil {
+		return false
+	}
+
+	annotations := configMap.GetAnnotations()
+
+	components, ok := annotations[componentsChecksumAnnotation]
+	if !ok {
+		return true
+	}
+
+	return checksums.components != "" && components != checksums.components ||
+		checksums.metadata != "" && annotations[metadataChecksumAnnotation] != checksums.metadata
+}
+
+// setVerifiedChecksums records the checksums verified at download in the provider status.
+func setVerifiedChecksums(provider operatorv1.GenericProvider, configMap *corev1.ConfigMap) {
+	status := provider.GetStatus()
+
+	components, ok := configMap.GetAnnotations()[componentsChecksumAnnotation]
+	if !ok {
+		status.VerifiedChecksums = nil
+	} else {
+		status.VerifiedChecksums = &operatorv1.VerifiedChecksums{
+			Version:    providerVersion(provider),
+			Components: components,
+			Metadata:   configMap.GetAnnotations()[metadataChecksumAnnotation],
+		}
+	}
+
+	provider.SetStatus(status)
+}
@@ -0,0 +1,331 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	configclient "sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/repository"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
+	"sigs.k8s.io/cluster-api-operator/util"
+)
+
+func sha256Hex(data string) string {
+	sum := sha256.Sum256([]byte(data))
+
+	return hex.EncodeToString(sum[:])
+}
+
+func TestRepositoryConfigMapChecksums(t *testing.T) {
+	const (
+		version    = "v1.0.0"
+		metadata   = "apiVersion: clusterctl.cluster.x-k8s.io/v1alpha3\nkind: Metadata"
+		components = "kind: Namespace"
+	)
+
+	wrongChecksum := sha256Hex("wrong")
+
+	testCases := []struct {
+		name         string
+		checksums    *operatorv1.ManifestChecksums
+		checksumFile string
+		expectedErr  bool
+		annotated    bool
+	}{
+		{
+			name: "verification not configured",
+		},
+		{
+			name:      "matching checksums in spec",
+			checksums: &operatorv1.ManifestChecksums{Components: sha256Hex(components), Metadata: sha256Hex(metadata)},
+			annotated: true,
+		},
+		{
+			name:      "only components checksum in spec",
+			checksums: &operatorv1.ManifestChecksums{Components: sha256Hex(components)},
+			annotated: true,
+		},
+		{
+			name:        "components checksum mismatch",
+			checksums:   &operatorv1.ManifestChecksums{Components: wrongChecksum},
+			expectedErr: true,
+		},
+		{
+			name:        "metadata checksum mismatch",
+			checksums:   &operatorv1.ManifestChecksums{Components: sha256Hex(components), Metadata: wrongChecksum},
+			expectedErr: true,
+		},
+		{
+			name:         "matching checksum file",
+			checksums:    &operatorv1.ManifestChecksums{File: "checksums.txt"},
+			checksumFile: fmt.Sprintf("%s  components.yaml\n%s *metadata.yaml\n", sha256Hex(components), sha256Hex(metadata)),
+			annotated:    true,
+		},
+		{
+			name:         "spec takes precedence over checksum file",
+			checksums:    &operatorv1.ManifestChecksums{Components: sha256Hex(components), File: "checksums.txt"},
+			checksumFile: fmt.Sprintf("%s  components.yaml\n%s  metadata.yaml\n", wrongChecksum, sha256Hex(metadata)),
+			annotated:    true,
+		},
+		{
+			name:         "checksum file mismatch",
+			checksums:    &operatorv1.ManifestChecksums{File: "checksums.txt"},
+			checksumFile: fmt.Sprintf("%s  components.yaml\n%s  metadata.yaml\n", wrongChecksum, sha256Hex(metadata)),
+			expectedErr:  true,
+		},
+		{
+			name:         "checksum file without components entry",
+			checksums:    &operatorv1.ManifestChecksums{File: "checksums.txt"},
+			checksumFile: fmt.Sprintf("%s  metadata.yaml\n", sha256Hex(metadata)),
+			expectedErr:  true,
+		},
+		{
+			name:        "missing checksum file",
+			checksums:   &operatorv1.ManifestChecksums{File: "checksums.txt"},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			repo := repository.NewMemoryRepository().
+				WithPaths("", "components.yaml").
+				WithFile(version, metadataFile, []byte(metadata)).
+				WithFile(version, "components.yaml", []byte(components))
+
+			if tc.checksumFile != "" {
+				repo.WithFile(version, "checksums.txt", []byte(tc.checksumFile))
+			}
+
+			provider := &operatorv1.CoreProvider{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-api", Namespace: "capi-system"},
+				Spec: operatorv1.CoreProviderSpec{ProviderSpec: operatorv1.ProviderSpec{
+					Version: version,
+					FetchConfig: &operatorv1.FetchConfiguration{
+						URL:       "https://github.com/kubernetes-sigs/cluster-api/releases",
+						Checksums: tc.checksums,
+					},
+				}},
+			}
+
+			configMap, err := RepositoryConfigMap(context.Background(), provider, repo)
+			if tc.expectedErr {
+				g.Expect(err).To(MatchError(ErrChecksumVerification))
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+
+			setVerifiedChecksums(provider, configMap)
+
+			if !tc.annotated {
+				g.Expect(configMap.GetAnnotations()).NotTo(HaveKey(componentsChecksumAnnotation))
+				g.Expect(provider.Status.VerifiedChecksums).To(BeNil())
+
+				return
+			}
+
+			g.Expect(configMap.GetAnnotations()).To(HaveKeyWithValue(componentsChecksumAnnotation, sha256Hex(components)))
+			g.Expect(configMap.GetAnnotations()).To(HaveKeyWithValue(metadataChecksumAnnotation, sha256Hex(metadata)))
+			g.Expect(provider.Status.VerifiedChecksums).To(Equal(&operatorv1.VerifiedChecksums{
+				Version:    version,
+				Components: sha256Hex(components),
+				Metadata:   sha256Hex(metadata),
+			}))
+		})
+	}
+}
+
+func TestDownloadManifestsVerifiesExistingChecksums(t *testing.T) {
+	server := newHTTPTestServer()
+	defer server.Close()
+
+	reader := configclient.NewMemoryReader()
+	reader.Set(HTTPUsernameKey, "user")
+	reader.Set(HTTPPasswordKey, "secret")
+
+	configClient, err := configclient.New(context.Background(), "", configclient.InjectReader(reader))
+	NewWithT(t).Expect(err).NotTo(HaveOccurred())
+
+	const staleComponents = "kind: Stale"
+
+	testCases := []struct {
+		name               string
+		checksums          *operatorv1.ManifestChecksums
+		annotations        map[string]string
+		expectedComponents string
+		expectedReason     string
+	}{
+		{
+			name:               "checksums configured after the download",
+			checksums:          &operatorv1.ManifestChecksums{Components: sha256Hex(httpTestComponents)},
+			expectedComponents: httpTestComponents,
+		},
+		{
+			name:      "manifests verified with the expected checksums",
+			checksums: &operatorv1.ManifestChecksums{Components: sha256Hex(httpTestComponents)},
+			annotations: map[string]string{
+				componentsChecksumAnnotation: sha256Hex(httpTestComponents),
+				metadataChecksumAnnotation:   sha256Hex(httpTestMetadata),
+			},
+			expectedComponents: staleComponents,
+		},
+		{
+			name:      "expected checksums changed after the download",
+			checksums: &operatorv1.ManifestChecksums{Components: sha256Hex("wrong")},
+			annotations: map[string]string{
+				componentsChecksumAnnotation: sha256Hex(httpTestComponents),
+				metadataChecksumAnnotation:   sha256Hex(httpTestMetadata),
+			},
+			expectedReason: operatorv1.ChecksumVerificationFailedReason,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			provider := &operatorv1.CoreProvider{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-api", Namespace: "capi-system"},
+				Spec: operatorv1.CoreProviderSpec{ProviderSpec: operatorv1.ProviderSpec{
+					Version: "v1.0.0",
+					FetchConfig: &operatorv1.FetchConfiguration{
+						HTTP:      &operatorv1.HTTPConfiguration{URL: server.URL + "/providers/core"},
+						Checksums: tc.checksums,
+					},
+				}},
+			}
+
+			annotations := map[string]string{configMapSourceAnnotation: server.URL + "/providers/core"}
+			for key, value := range tc.annotations {
+				annotations[key] = value
+			}
+
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "core-cluster-api-v1.0.0",
+					Namespace:   "capi-system",
+					Labels:      ProviderLabels(provider),
+					Annotations: annotations,
+				},
+				Data: map[string]string{
+					operatorv1.MetadataConfigMapKey:   httpTestMetadata,
+					operatorv1.ComponentsConfigMapKey: staleComponents,
+				},
+			}
+
+			fakeclient := fake.NewClientBuilder().WithScheme(cacheTestScheme()).WithObjects(configMap).Build()
+
+			p := &PhaseReconciler{
+				ctrlClient:         fakeclient,
+				provider:           provider,
+				providerConfig:     configclient.NewProvider("cluster-api", fakeURL, clusterctlv1.CoreProviderType),
+				configClient:       configClient,
+				providerTypeMapper: util.ClusterctlProviderType,
+			}
+
+			_, err := p.DownloadManifests(context.Background())
+			if tc.expectedReason != "" {
+				var phaseErr *PhaseError
+
+				g.Expect(errors.As(err, &phaseErr)).To(BeTrue())
+				g.Expect(phaseErr.Reason).To(Equal(tc.expectedReason))
+
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(fakeclient.Get(context.Background(), client.ObjectKeyFromObject(configMap), configMap)).To(Succeed())
+			g.Expect(configMap.Data).To(HaveKeyWithValue(operatorv1.ComponentsConfigMapKey, tc.expectedComponents))
+			g.Expect(provider.Status.VerifiedChecksums).To(Equal(&operatorv1.VerifiedChecksums{
+				Version:    "v1.0.0",
+				Components: sha256Hex(httpTestComponents),
+				Metadata:   sha256Hex(httpTestMetadata),
+			}))
+		})
+	}
+}
+
+func TestChecksumsOutdated(t *testing.T) {
+	verified := map[string]string{
+		componentsChecksumAnnotation: sha256Hex("components"),
+		metadataChecksumAnnotation:   sha256Hex("metadata"),
+	}
+
+	testCases := []struct {
+		name        string
+		checksums   *manifestChecksums
+		annotations map[string]string
+		expected    bool
+	}{
+		{
+			name: "verification not configured",
+		},
+		{
+			name:        "matching checksums",
+			checksums:   &manifestChecksums{components: sha256Hex("components"), metadata: sha256Hex("metadata")},
+			annotations: verified,
+		},
+		{
+			name:        "only components checksum expected",
+			checksums:   &manifestChecksums{components: sha256Hex("components")},
+			annotations: verified,
+		},
+		{
+			name:      "manifests not verified",
+			checksums: &manifestChecksums{components: sha256Hex("components")},
+			expected:  true,
+		},
+		{
+			name:        "components checksum mismatch",
+			checksums:   &manifestChecksums{components: sha256Hex("other")},
+			annotations: verified,
+			expected:    true,
+		},
+		{
+			name:        "metadata checksum mismatch",
+			checksums:   &manifestChecksums{components: sha256Hex("components"), metadata: sha256Hex("other")},
+			annotations: verified,
+			expected:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+
+			g.Expect(checksumsOutdated(configMap, tc.checksums)).To(Equal(tc.expected))
+		})
+	}
+}
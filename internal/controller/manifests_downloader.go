@@ -20,26 +20,32 @@ import (
 	"bytes"
 	"compress/gzip"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
-	"oras.land/oras-go/v2/registry/remote/auth"
 
+	configclient "sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/repository"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
-	"sigs.k8s.io/cluster-api-operator/util"
 )
 
 const (
 	configMapSourceLabel      = "provider.cluster.x-k8s.io/source"
 	configMapSourceAnnotation = "provider.cluster.x-k8s.io/source"
+	ociDigestAnnotation       = "provider.cluster.x-k8s.io/oci-digest"
+	ociPublicKeyAnnotation    = "provider.cluster.x-k8s.io/oci-public-key-sha256"
 	operatorManagedLabel      = "managed-by.operator.cluster.x-k8s.io"
 
 	maxConfigMapSize = 1 * 1024 * 1024
@@ -57,71 +63,334 @@ func (p *PhaseReconciler) DownloadManifests(ctx context.Context) (*Result, error
 		return &Result{}, nil
 	}
 
-	// Check if manifests are already downloaded and stored in a configmap
-	labelSelector := metav1.LabelSelector{
-		MatchLabels: p.prepareConfigMapLabels(),
+	// Resolve the version policy before looking up the manifests for the provider version.
+	if isVersionPolicyCheckDue(p.provider) {
+		if err := p.resolveVersionPolicy(ctx); err != nil {
+			return &Result{}, wrapPhaseError(err, operatorv1.VersionPolicyResolutionErrorReason, operatorv1.ProviderInstalledCondition)
+		}
 	}
 
-	exists, err := p.checkConfigMapExists(ctx, labelSelector, p.provider.GetNamespace())
+	// Check if manifests are already downloaded and stored in a configmap
+	existing, err := p.manifestsConfigMap(ctx)
 	if err != nil {
 		return &Result{}, wrapPhaseError(err, "failed to check that config map with manifests exists", operatorv1.ProviderInstalledCondition)
 	}
 
-	if exists {
-		log.V(5).Info("Config map with downloaded manifests already exists, skip downloading provider manifests")
+	if existing != nil {
+		// The manifests are verified again when the verification was changed after they were downloaded.
+		outdated, err := p.manifestsVerificationOutdated(ctx, existing)
+		if err != nil {
+			return &Result{}, err
+		}
 
-		return &Result{}, nil
+		if !outdated {
+			log.V(5).Info("Config map with downloaded manifests already exists, skip downloading provider manifests")
+
+			setVerifiedChecksums(p.provider, existing)
+
+			if isOCIProvider(p.provider) {
+				if err := p.reconcileOCIArtifact(ctx); err != nil {
+					return &Result{}, wrapPhaseError(err, operatorv1.ComponentsFetchErrorReason, operatorv1.ProviderInstalledCondition)
+				}
+			}
+
+			return &Result{}, nil
+		}
+
+		log.Info("Provider manifests were not verified with the current fetch configuration, downloading them again", "configMap", existing.GetName())
 	}
 
-	log.Info("Downloading provider manifests", "version", p.provider.GetSpec().Version)
+	log.Info("Downloading provider manifests", "version", providerVersion(p.provider))
 
-	if p.providerConfig.URL() != fakeURL {
-		p.repo, err = util.RepositoryFactory(ctx, p.providerConfig, p.configClient.Variables())
-		if err != nil {
-			err = fmt.Errorf("failed to create repo from provider url for provider %q: %w", p.provider.GetName(), err)
+	configMap, err := p.fetchManifests(ctx)
+	if err != nil {
+		return &Result{}, err
+	}
 
+	if existing != nil {
+		existing.Labels = configMap.Labels
+		existing.Annotations = configMap.Annotations
+		existing.Data = configMap.Data
+		existing.BinaryData = configMap.BinaryData
+
+		if err := p.ctrlClient.Update(ctx, existing); err != nil {
 			return &Result{}, wrapPhaseError(err, operatorv1.ComponentsFetchErrorReason, operatorv1.ProviderInstalledCondition)
 		}
+	} else if err := p.ctrlClient.Create(ctx, configMap); client.IgnoreAlreadyExists(err) != nil {
+		return &Result{}, wrapPhaseError(err, operatorv1.ComponentsFetchErrorReason, operatorv1.ProviderInstalledCondition)
+	}
+
+	setVerifiedChecksums(p.provider, configMap)
+	setOCIArtifact(p.provider, configMap)
+
+	return &Result{}, nil
+}
+
+// manifestsConfigMap returns the ConfigMap with the downloaded manifests of the provider version, or nil if
+// the manifests are not downloaded yet.
+func (p *PhaseReconciler) manifestsConfigMap(ctx context.Context) (*corev1.ConfigMap, error) {
+	configMapList := &corev1.ConfigMapList{}
+
+	if err := p.ctrlClient.List(ctx, configMapList, client.InNamespace(p.provider.GetNamespace()), client.MatchingLabels(p.prepareConfigMapLabels())); err != nil {
+		return nil, fmt.Errorf("failed to list ConfigMaps: %w", err)
+	}
+
+	switch len(configMapList.Items) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &configMapList.Items[0], nil
+	}
+
+	return nil, fmt.Errorf("more than one config maps were found for labels: %v", p.prepareConfigMapLabels())
+}
+
+// manifestsVerificationOutdated returns true if the manifests of the ConfigMap were not verified against the pinned
+// OCI digest, the OCI signature key or the checksums of the source they were fetched from.
+func (p *PhaseReconciler) manifestsVerificationOutdated(ctx context.Context, configMap *corev1.ConfigMap) (bool, error) {
+	source, providerConfig := p.configMapFetchSource(configMap)
+
+	switch {
+	case isOCIProvider(source):
+		publicKey, err := OCIVerificationPublicKey(ctx, p.ctrlClient, source)
+		if err != nil {
+			return false, wrapPhaseError(err, operatorv1.OCIVerificationFailedReason, operatorv1.PreflightCheckCondition)
+		}
+
+		return ociVerificationOutdated(source, configMap, publicKey), nil
+	case source.GetSpec().FetchConfig == nil || source.GetSpec().FetchConfig.Checksums == nil:
+		return false, nil
+	}
+
+	var repo repository.Repository
+
+	// The repository is only needed to read the checksum file.
+	if source.GetSpec().FetchConfig.Checksums.File != "" {
+		var err error
+
+		repo, err = p.fetchRepository(ctx, source, providerConfig)
+		if err != nil {
+			err = fmt.Errorf("failed to create repo from provider url for provider %q: %w", source.GetName(), err)
+
+			return false, wrapPhaseError(err, operatorv1.ComponentsFetchErrorReason, operatorv1.ProviderInstalledCondition)
+		}
+	}
+
+	checksums, err := expectedManifestChecksums(ctx, source, repo)
+	if err != nil {
+		return false, wrapPhaseError(err, operatorv1.ChecksumVerificationFailedReason, operatorv1.PreflightCheckCondition)
+	}
+
+	return checksumsOutdated(configMap, checksums), nil
+}
+
+// configMapFetchSource returns the fetch source of the provider the ConfigMap manifests were fetched from, with
+// its provider configuration. The provider itself is returned if the source is not found.
+func (p *PhaseReconciler) configMapFetchSource(configMap *corev1.ConfigMap) (operatorv1.GenericProvider, configclient.Provider) {
+	sources := fetchSourceProviders(p.provider)
+
+	fetchedFrom, ok := configMap.GetAnnotations()[configMapSourceAnnotation]
+	if !ok {
+		return sources[0], p.providerConfig
+	}
+
+	for i, source := range sources {
+		providerConfig := p.providerConfig
+		if i > 0 && source.GetSpec().FetchConfig.URL != "" {
+			providerConfig = configclient.NewProvider(p.provider.ProviderName(), source.GetSpec().FetchConfig.URL, p.providerTypeMapper(p.provider))
+		}
+
+		var url string
+
+		switch fetchConfig := source.GetSpec().FetchConfig; {
+		case isOCIProvider(source):
+			url = fetchConfig.OCI
+		case isHTTPProvider(source):
+			url = fetchConfig.HTTP.URL
+		case isGitProvider(source):
+			url = fetchConfig.Git.URL
+		case providerConfig != nil:
+			url = providerConfig.URL()
+		}
+
+		if url == fetchedFrom {
+			return source, providerConfig
+		}
+	}
+
+	return sources[0], p.providerConfig
+}
+
+// fetchManifests returns the ConfigMap with the provider manifests fetched from the first source that succeeds,
+// trying the fallback sources in order when fetching from the provider source fails.
+func (p *PhaseReconciler) fetchManifests(ctx context.Context) (*corev1.ConfigMap, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	sources := fetchSourceProviders(p.provider)
+	errs := []error{}
+
+	for i, source := range sources {
+		providerConfig := p.providerConfig
+		if i > 0 && source.GetSpec().FetchConfig.URL != "" {
+			providerConfig = configclient.NewProvider(p.provider.ProviderName(), source.GetSpec().FetchConfig.URL, p.providerTypeMapper(p.provider))
+		}
+
+		configMap, err := p.fetchManifestsFromSource(ctx, source, providerConfig)
+		if err == nil {
+			if i > 0 {
+				log.Info("Fetched provider manifests from fallback source", "source", configMap.Annotations[configMapSourceAnnotation])
+			}
+
+			// The version may have been resolved from the source repository. The ConfigMap keeps the labels of
+			// the source it was fetched from, which identify the provider version whatever the source is.
+			spec := p.provider.GetSpec()
+			spec.Version = source.GetSpec().Version
+			p.provider.SetSpec(spec)
+
+			return configMap, nil
+		}
+
+		// Manifests failing the verification are not fetched from another source.
+		var phaseErr *PhaseError
+		if len(sources) == 1 || errors.As(err, &phaseErr) && phaseErr.Type == operatorv1.PreflightCheckCondition {
+			return nil, err
+		}
+
+		log.Error(err, "Failed to fetch provider manifests", "source", i)
+
+		errs = append(errs, fmt.Errorf("source %d: %w", i, err))
+	}
+
+	return nil, wrapPhaseError(kerrors.NewAggregate(errs), operatorv1.ComponentsFetchErrorReason, operatorv1.ProviderInstalledCondition)
+}
+
+// fetchSourceProviders returns a copy of the provider for each of its fetch sources, with the fetch configuration
+// set to the source. The provider itself is returned first.
+func fetchSourceProviders(provider operatorv1.GenericProvider) []operatorv1.GenericProvider {
+	sources := []operatorv1.GenericProvider{provider.DeepCopyObject().(operatorv1.GenericProvider)}
+
+	if provider.GetSpec().FetchConfig == nil {
+		return sources
+	}
+
+	for _, fallback := range provider.GetSpec().FetchConfig.Fallbacks {
+		source := provider.DeepCopyObject().(operatorv1.GenericProvider)
+
+		spec := source.GetSpec()
+		spec.FetchConfig = &operatorv1.FetchConfiguration{
+			OCIConfiguration: fallback.OCIConfiguration,
+			URL:              fallback.URL,
+			HTTP:             fallback.HTTP,
+			Git:              fallback.Git,
+			Checksums:        fallback.Checksums,
+		}
+		source.SetSpec(spec)
+
+		sources = append(sources, source)
 	}
 
-	spec := p.provider.GetSpec()
+	return sources
+}
+
+// fetchManifestsFromSource returns the ConfigMap with the provider manifests fetched from the provider fetch
+// configuration, or from the repository of the provider configuration.
+func (p *PhaseReconciler) fetchManifestsFromSource(ctx context.Context, provider operatorv1.GenericProvider, providerConfig configclient.Provider) (*corev1.ConfigMap, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	var (
+		repo repository.Repository
+		err  error
+	)
+
+	if !isGitProvider(provider) && (isHTTPProvider(provider) || providerConfig.URL() != fakeURL) {
+		repo, err = p.fetchRepository(ctx, provider, providerConfig)
+		if err != nil {
+			err = fmt.Errorf("failed to create repo from provider url for provider %q: %w", provider.GetName(), err)
 
-	if spec.Version == "" && p.repo != nil {
+			return nil, wrapPhaseError(err, operatorv1.ComponentsFetchErrorReason, operatorv1.ProviderInstalledCondition)
+		}
+	}
+
+	spec := provider.GetSpec()
+
+	if providerVersion(provider) == "" && repo != nil {
 		// User didn't set the version, try to get repository default.
-		spec.Version = p.repo.DefaultVersion()
+		spec.Version = repo.DefaultVersion()
 
 		log.Info("Using repository default version", "version", spec.Version)
 
 		// Add version to the provider spec.
-		p.provider.SetSpec(spec)
+		provider.SetSpec(spec)
 	}
 
 	var configMap *corev1.ConfigMap
 
 	// Fetch the provider metadata and components yaml files from the provided repository GitHub/GitLab or OCI source
-	if p.provider.GetSpec().FetchConfig != nil && p.provider.GetSpec().FetchConfig.OCI != "" {
-		log.Info("Downloading manifests from OCI source", "oci", p.provider.GetSpec().FetchConfig.OCI)
+	switch {
+	case isOCIProvider(provider):
+		log.Info("Downloading manifests from OCI source", "oci", provider.GetSpec().FetchConfig.OCI)
 
-		configMap, err = OCIConfigMap(ctx, p.provider, OCIAuthentication(p.configClient.Variables()))
+		registry, err := p.ociRegistryOptions(ctx, provider)
 		if err != nil {
-			return &Result{}, wrapPhaseError(err, operatorv1.ComponentsFetchErrorReason, operatorv1.ProviderInstalledCondition)
+			return nil, wrapPhaseError(err, operatorv1.ComponentsFetchErrorReason, operatorv1.ProviderInstalledCondition)
+		}
+
+		publicKey, err := OCIVerificationPublicKey(ctx, p.ctrlClient, provider)
+		if err == nil {
+			configMap, err = OCIConfigMap(ctx, provider, registry, publicKey)
+		}
+
+		if errors.Is(err, ErrOCIVerification) {
+			return nil, wrapPhaseError(err, operatorv1.OCIVerificationFailedReason, operatorv1.PreflightCheckCondition)
 		}
-	} else {
-		log.Info("Downloading manifests from repository", "url", p.providerConfig.URL())
 
-		configMap, err = RepositoryConfigMap(ctx, p.provider, p.repo)
 		if err != nil {
-			err = fmt.Errorf("failed to create config map for provider %q: %w", p.provider.GetName(), err)
+			return nil, wrapPhaseError(err, operatorv1.ComponentsFetchErrorReason, operatorv1.ProviderInstalledCondition)
+		}
+	case isGitProvider(provider):
+		log.Info("Building manifests from git repository", "url", provider.GetSpec().FetchConfig.Git.URL, "ref", gitRef(provider))
 
-			return &Result{}, wrapPhaseError(err, operatorv1.ComponentsFetchErrorReason, operatorv1.ProviderInstalledCondition)
+		configMap, err = GitConfigMap(ctx, provider, p.configClient.Variables())
+		if err != nil {
+			return nil, wrapPhaseError(err, operatorv1.ComponentsFetchErrorReason, operatorv1.ProviderInstalledCondition)
+		}
+	default:
+		url := providerConfig.URL()
+		if isHTTPProvider(provider) {
+			url = provider.GetSpec().FetchConfig.HTTP.URL
+		}
+
+		log.Info("Downloading manifests from repository", "url", url)
+
+		configMap, err = RepositoryConfigMap(ctx, provider, repo)
+		if errors.Is(err, ErrChecksumVerification) {
+			return nil, wrapPhaseError(err, operatorv1.ChecksumVerificationFailedReason, operatorv1.PreflightCheckCondition)
+		}
+
+		if err != nil {
+			err = fmt.Errorf("failed to create config map for provider %q: %w", provider.GetName(), err)
+
+			return nil, wrapPhaseError(err, operatorv1.ComponentsFetchErrorReason, operatorv1.ProviderInstalledCondition)
 		}
+
+		if configMap.Annotations == nil {
+			configMap.Annotations = map[string]string{}
+		}
+
+		configMap.Annotations[configMapSourceAnnotation] = url
 	}
 
-	if err := p.ctrlClient.Create(ctx, configMap); client.IgnoreAlreadyExists(err) != nil {
-		return &Result{}, wrapPhaseError(err, operatorv1.ComponentsFetchErrorReason, operatorv1.ProviderInstalledCondition)
+	return configMap, nil
+}
+
+// ociRegistryOptions returns the options to connect to the OCI registry of the provider.
+func (p *PhaseReconciler) ociRegistryOptions(ctx context.Context, provider operatorv1.GenericProvider) (OCIRegistryOptions, error) {
+	defaults := p.ociRegistryDefaults
+	if defaults.Credential == nil {
+		defaults.Credential = OCIAuthentication(p.configClient.Variables())
 	}
 
-	return &Result{}, nil
+	return OCIRegistryAuthentication(ctx, p.ctrlClient, provider, defaults)
 }
 
 // checkConfigMapExists checks if a config map exists in Kubernetes with the given LabelSelector.
@@ -166,7 +435,7 @@ func (p *PhaseReconciler) prepareConfigMapLabels() map[string]string {
 
 // TemplateManifestsConfigMap prepares a config map with downloaded manifests.
 func TemplateManifestsConfigMap(provider operatorv1.GenericProvider, labels map[string]string, metadata, components []byte, compress bool) (*corev1.ConfigMap, error) {
-	configMapName := fmt.Sprintf("%s-%s-%s", provider.GetType(), provider.GetName(), provider.GetSpec().Version)
+	configMapName := fmt.Sprintf("%s-%s-%s", provider.GetType(), provider.GetName(), providerVersion(provider))
 
 	kinds, _, err := clientgoscheme.Scheme.ObjectKinds(&corev1.ConfigMap{})
 	if err != nil || len(kinds) == 0 {
@@ -194,6 +463,18 @@ func TemplateManifestsConfigMap(provider operatorv1.GenericProvider, labels map[
 		}
 	}
 
+	if isHTTPProvider(provider) {
+		configMap.ObjectMeta.Annotations = map[string]string{
+			configMapSourceAnnotation: provider.GetSpec().FetchConfig.HTTP.URL,
+		}
+	}
+
+	if isGitProvider(provider) {
+		configMap.ObjectMeta.Annotations = map[string]string{
+			configMapSourceAnnotation: provider.GetSpec().FetchConfig.Git.URL,
+		}
+	}
+
 	// Components manifests data can exceed the configmap size limit. In this case we have to compress it.
 	if !compress {
 		configMap.Data[operatorv1.ComponentsConfigMapKey] = string(components)
@@ -208,7 +489,11 @@ func TemplateManifestsConfigMap(provider operatorv1.GenericProvider, labels map[
 		}
 
 		// Setting the annotation to mark these manifests as compressed.
-		configMap.SetAnnotations(map[string]string{operatorv1.CompressedAnnotation: "true"})
+		if configMap.Annotations == nil {
+			configMap.Annotations = map[string]string{}
+		}
+
+		configMap.Annotations[operatorv1.CompressedAnnotation] = "true"
 	}
 
 	gvk := provider.GetObjectKind().GroupVersionKind()
@@ -265,9 +550,9 @@ func decompressData(compressedData []byte) (data []byte, err error) {
 	return decompressedData, nil
 }
 
-// OCIConfigMap templates config from the OCI source.
-func OCIConfigMap(ctx context.Context, provider operatorv1.GenericProvider, auth *auth.Credential) (*corev1.ConfigMap, error) {
-	store, err := FetchOCI(ctx, provider, auth)
+// OCIConfigMap templates config from the OCI source. If a public key is provided, the OCI artifact signature is verified.
+func OCIConfigMap(ctx context.Context, provider operatorv1.GenericProvider, registry OCIRegistryOptions, publicKey []byte) (*corev1.ConfigMap, error) {
+	store, err := FetchOCI(ctx, provider, registry, publicKey)
 	if err != nil {
 		return nil, err
 	}
@@ -282,13 +567,70 @@ func OCIConfigMap(ctx context.Context, provider operatorv1.GenericProvider, auth
 		return nil, err
 	}
 
-	configMap, err := TemplateManifestsConfigMap(provider, ProviderLabels(provider), metadata, components, needToCompress(metadata, components))
+	labels := ProviderLabels(provider)
+	labels[configMapSourceLabel] = ociSource
+
+	configMap, err := TemplateManifestsConfigMap(provider, labels, metadata, components, needToCompress(metadata, components))
 	if err != nil {
 		err = fmt.Errorf("failed to create config map for provider %q: %w", provider.GetName(), err)
 
 		return nil, err
 	}
 
+	// Record the digest the OCI reference resolved to, to detect re-pushed tags later on.
+	annotations := configMap.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	annotations[ociDigestAnnotation] = store.Digest()
+
+	// Record the key the signature was verified with, to verify the manifests again when the key changes.
+	if len(publicKey) != 0 {
+		annotations[ociPublicKeyAnnotation] = publicKeyFingerprint(publicKey)
+	}
+
+	configMap.SetAnnotations(annotations)
+
+	if provider.GetUID() == "" {
+		// Unset owner references due to lack of existing provider owner object
+		configMap.OwnerReferences = nil
+	}
+
+	return configMap, nil
+}
+
+// LocalDirectoryConfigMap templates ConfigMap resource from the provider metadata and components files in a local
+// directory, named as in the provider OCI artifacts.
+func LocalDirectoryConfigMap(provider operatorv1.GenericProvider, dir string) (*corev1.ConfigMap, error) {
+	store := NewMapStore(provider)
+
+	for name := range store.data {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("cannot read %s from directory %s: %w", name, dir, err)
+		}
+
+		store.data[name] = data
+	}
+
+	metadata, err := store.GetMetadata(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	components, err := store.GetComponents(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	configMap, err := TemplateManifestsConfigMap(provider, ProviderLabels(provider), metadata, components, needToCompress(metadata, components))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config map for provider %q: %w", provider.GetName(), err)
+	}
+
 	if provider.GetUID() == "" {
 		// Unset owner references due to lack of existing provider owner object
 		configMap.OwnerReferences = nil
@@ -299,20 +641,25 @@ func OCIConfigMap(ctx context.Context, provider operatorv1.GenericProvider, auth
 
 // RepositoryConfigMap templates ConfigMap resource from the provider repository.
 func RepositoryConfigMap(ctx context.Context, provider operatorv1.GenericProvider, repo repository.Repository) (*corev1.ConfigMap, error) {
-	metadata, err := repo.GetFile(ctx, provider.GetSpec().Version, "metadata.yaml")
+	metadata, err := repo.GetFile(ctx, providerVersion(provider), "metadata.yaml")
 	if err != nil {
 		err = fmt.Errorf("failed to read metadata.yaml from the repository for provider %q: %w", provider.GetName(), err)
 
 		return nil, err
 	}
 
-	components, err := repo.GetFile(ctx, provider.GetSpec().Version, repo.ComponentsPath())
+	components, err := repo.GetFile(ctx, providerVersion(provider), repo.ComponentsPath())
 	if err != nil {
 		err = fmt.Errorf("failed to read %q from the repository for provider %q: %w", repo.ComponentsPath(), provider.GetName(), err)
 
 		return nil, err
 	}
 
+	checksums, err := expectedManifestChecksums(ctx, provider, repo)
+	if err != nil {
+		return nil, err
+	}
+
 	configMap, err := TemplateManifestsConfigMap(provider, ProviderLabels(provider), metadata, components, needToCompress(metadata, components))
 	if err != nil {
 		err = fmt.Errorf("failed to create config map for provider %q: %w", provider.GetName(), err)
@@ -320,6 +667,13 @@ func RepositoryConfigMap(ctx context.Context, provider operatorv1.GenericProvide
 		return nil, err
 	}
 
+	// Verify the manifests before the ConfigMap is created, and record the verified checksums.
+	if checksums != nil {
+		if err := verifyManifests(configMap, checksums, repo.ComponentsPath(), metadata, components); err != nil {
+			return nil, err
+		}
+	}
+
 	if provider.GetUID() == "" {
 		// Unset owner references due to lack of existing provider owner object
 		configMap.OwnerReferences = nil
@@ -340,25 +694,26 @@ func providerLabelSelector(provider operatorv1.GenericProvider) *metav1.LabelSel
 }
 
 // ProviderLabels returns default set of labels that identify a config map with downloaded manifests.
+// The manifests fetched from an OCI artifact are labelled with their source too, which is not part of
+// the identifying labels, as the manifests may be fetched from a fallback source.
 func ProviderLabels(provider operatorv1.GenericProvider) map[string]string {
-	labels := map[string]string{
-		operatorv1.ConfigMapVersionLabelName: provider.GetSpec().Version,
+	return map[string]string{
+		operatorv1.ConfigMapVersionLabelName: providerVersion(provider),
 		operatorv1.ConfigMapTypeLabel:        provider.GetType(),
 		operatorv1.ConfigMapNameLabel:        provider.GetName(),
 		operatorManagedLabel:                 "true",
 	}
-
-	if provider.GetSpec().FetchConfig != nil && provider.GetSpec().FetchConfig.OCI != "" {
-		labels[configMapSourceLabel] = ociSource
-	}
-
-	return labels
 }
 
 // ProviderCacheName generates a cache name for a given provider.
 
 func ProviderCacheName(provider operatorv1.GenericProvider) string {
-	return fmt.Sprintf("%s-%s-%s-cache", provider.GetType(), provider.GetName(), provider.GetSpec().Version)
+	return providerCacheNameForVersion(provider, providerVersion(provider))
+}
+
+// providerCacheNameForVersion generates a cache name for a given provider version.
+func providerCacheNameForVersion(provider operatorv1.GenericProvider, version string) string {
+	return fmt.Sprintf("%s-%s-%s-cache", provider.GetType(), provider.GetName(), version)
 }
 
 // needToCompress checks whether the input data exceeds the maximum configmap
@@ -19,10 +19,12 @@ package controller
 import (
 	"bytes"
 	"context"
+	"errors"
 	"strings"
 	"testing"
 
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
 	configclient "sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
@@ -223,3 +225,182 @@ func TestProviderCacheName(t *testing.T) {
 		})
 	}
 }
+
+func TestFetchManifestsFallbacks(t *testing.T) {
+	server := newHTTPTestServer()
+	defer server.Close()
+
+	reader := configclient.NewMemoryReader()
+	reader.Set(HTTPUsernameKey, "user")
+	reader.Set(HTTPPasswordKey, "secret")
+
+	configClient, err := configclient.New(context.Background(), "", configclient.InjectReader(reader))
+	NewWithT(t).Expect(err).NotTo(HaveOccurred())
+
+	testCases := []struct {
+		name           string
+		fetchConfig    *operatorv1.FetchConfiguration
+		expectedSource string
+		expectedReason string
+	}{
+		{
+			name: "provider source succeeds",
+			fetchConfig: &operatorv1.FetchConfiguration{
+				HTTP:      &operatorv1.HTTPConfiguration{URL: server.URL + "/providers/core"},
+				Fallbacks: []operatorv1.FetchSource{{HTTP: &operatorv1.HTTPConfiguration{URL: server.URL + "/missing"}}},
+			},
+			expectedSource: server.URL + "/providers/core",
+		},
+		{
+			name: "fallback source succeeds",
+			fetchConfig: &operatorv1.FetchConfiguration{
+				HTTP: &operatorv1.HTTPConfiguration{URL: server.URL + "/missing"},
+				Fallbacks: []operatorv1.FetchSource{
+					{HTTP: &operatorv1.HTTPConfiguration{URL: server.URL + "/other"}},
+					{HTTP: &operatorv1.HTTPConfiguration{URL: server.URL + "/providers/core"}},
+				},
+			},
+			expectedSource: server.URL + "/providers/core",
+		},
+		{
+			name: "fallback source of an OCI provider succeeds",
+			fetchConfig: &operatorv1.FetchConfiguration{
+				OCIConfiguration: operatorv1.OCIConfiguration{OCI: "127.0.0.1:1/cluster-api:v1.0.0"},
+				Fallbacks:        []operatorv1.FetchSource{{HTTP: &operatorv1.HTTPConfiguration{URL: server.URL + "/providers/core"}}},
+			},
+			expectedSource: server.URL + "/providers/core",
+		},
+		{
+			name: "all sources fail",
+			fetchConfig: &operatorv1.FetchConfiguration{
+				HTTP:      &operatorv1.HTTPConfiguration{URL: server.URL + "/missing"},
+				Fallbacks: []operatorv1.FetchSource{{HTTP: &operatorv1.HTTPConfiguration{URL: server.URL + "/other"}}},
+			},
+			expectedReason: operatorv1.ComponentsFetchErrorReason,
+		},
+		{
+			name: "verification failure is not retried",
+			fetchConfig: &operatorv1.FetchConfiguration{
+				HTTP:      &operatorv1.HTTPConfiguration{URL: server.URL + "/providers/core"},
+				Checksums: &operatorv1.ManifestChecksums{Components: strings.Repeat("0", 64)},
+				Fallbacks: []operatorv1.FetchSource{{HTTP: &operatorv1.HTTPConfiguration{URL: server.URL + "/providers/core"}}},
+			},
+			expectedReason: operatorv1.ChecksumVerificationFailedReason,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			provider := &operatorv1.CoreProvider{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-api", Namespace: "capi-system"},
+				Spec: operatorv1.CoreProviderSpec{ProviderSpec: operatorv1.ProviderSpec{
+					Version:     "v1.0.0",
+					FetchConfig: tc.fetchConfig,
+				}},
+			}
+
+			p := &PhaseReconciler{
+				provider:           provider,
+				providerConfig:     configclient.NewProvider("cluster-api", fakeURL, clusterctlv1.CoreProviderType),
+				configClient:       configClient,
+				providerTypeMapper: util.ClusterctlProviderType,
+			}
+
+			configMap, err := p.fetchManifests(context.Background())
+			if tc.expectedReason != "" {
+				var phaseErr *PhaseError
+
+				g.Expect(errors.As(err, &phaseErr)).To(BeTrue())
+				g.Expect(phaseErr.Reason).To(Equal(tc.expectedReason))
+
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(configMap.Annotations).To(HaveKeyWithValue(configMapSourceAnnotation, tc.expectedSource))
+			g.Expect(configMap.Labels).To(Equal(ProviderLabels(provider)))
+			g.Expect(configMap.Data).To(HaveKeyWithValue(operatorv1.ComponentsConfigMapKey, httpTestComponents))
+		})
+	}
+}
+
+func TestDownloadManifestsVerifiesExistingOCIManifests(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("a", 64)
+
+	testCases := []struct {
+		name           string
+		ociConfig      operatorv1.OCIConfiguration
+		expectedReason string
+	}{
+		{
+			name:      "manifests verified with the pinned digest",
+			ociConfig: operatorv1.OCIConfiguration{OCI: "127.0.0.1:1/cluster-api", OCIDigest: digest},
+		},
+		{
+			name:           "digest pinned after the download",
+			ociConfig:      operatorv1.OCIConfiguration{OCI: "127.0.0.1:1/cluster-api", OCIDigest: "sha256:" + strings.Repeat("b", 64)},
+			expectedReason: operatorv1.ComponentsFetchErrorReason,
+		},
+		{
+			name: "missing public key secret",
+			ociConfig: operatorv1.OCIConfiguration{
+				OCI:             "127.0.0.1:1/cluster-api",
+				OCIVerification: &operatorv1.OCIVerification{PublicKeySecretRef: operatorv1.SecretReference{Name: "cosign"}},
+			},
+			expectedReason: operatorv1.OCIVerificationFailedReason,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			provider := &operatorv1.CoreProvider{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-api", Namespace: "capi-system"},
+				Spec: operatorv1.CoreProviderSpec{ProviderSpec: operatorv1.ProviderSpec{
+					Version:     "v1.0.0",
+					FetchConfig: &operatorv1.FetchConfiguration{OCIConfiguration: tc.ociConfig},
+				}},
+			}
+
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "core-cluster-api-v1.0.0",
+					Namespace: "capi-system",
+					Labels:    ProviderLabels(provider),
+					Annotations: map[string]string{
+						configMapSourceAnnotation: "127.0.0.1:1/cluster-api",
+						ociDigestAnnotation:       digest,
+					},
+				},
+			}
+
+			configClient, err := configclient.New(context.Background(), "", configclient.InjectReader(configclient.NewMemoryReader()))
+			g.Expect(err).NotTo(HaveOccurred())
+
+			p := &PhaseReconciler{
+				ctrlClient:         fake.NewClientBuilder().WithScheme(cacheTestScheme()).WithObjects(configMap).Build(),
+				provider:           provider,
+				providerConfig:     configclient.NewProvider("cluster-api", fakeURL, clusterctlv1.CoreProviderType),
+				configClient:       configClient,
+				providerTypeMapper: util.ClusterctlProviderType,
+			}
+
+			_, err = p.DownloadManifests(context.Background())
+			if tc.expectedReason != "" {
+				var phaseErr *PhaseError
+
+				g.Expect(errors.As(err, &phaseErr)).To(BeTrue())
+				g.Expect(phaseErr.Reason).To(Equal(tc.expectedReason))
+
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(provider.Status.OCIArtifact).NotTo(BeNil())
+			g.Expect(provider.Status.OCIArtifact.Digest).To(Equal(digest))
+		})
+	}
+}
@@ -0,0 +1,207 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+
+	corev1 "k8s.io/api/core/v1"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"oras.land/oras-go/v2/registry/remote/credentials"
+	"oras.land/oras-go/v2/registry/remote/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
+)
+
+// OCIRegistryOptions defines how to connect to the OCI registry.
+type OCIRegistryOptions struct {
+	// Credential is used for the registry if the credentials store has no credentials for it.
+	Credential *auth.Credential
+
+	// CredentialsStore provides per-registry credentials, for example from a Docker config file.
+	CredentialsStore credentials.Store
+
+	// TLSConfig is used to verify the registry certificate, and to present a client certificate.
+	TLSConfig *tls.Config
+}
+
+// Client returns the client used to connect to the registry.
+func (o OCIRegistryOptions) Client(registry string) remote.Client {
+	httpClient := retry.DefaultClient
+
+	if o.TLSConfig != nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = o.TLSConfig
+
+		httpClient = &http.Client{Transport: retry.NewTransport(transport)}
+	}
+
+	var fallback auth.CredentialFunc
+	if o.Credential != nil {
+		fallback = auth.StaticCredential(registry, *o.Credential)
+	}
+
+	credential := fallback
+
+	if o.CredentialsStore != nil {
+		credential = func(ctx context.Context, hostport string) (auth.Credential, error) {
+			cred, err := credentials.Credential(o.CredentialsStore)(ctx, hostport)
+			if err != nil || cred != auth.EmptyCredential || fallback == nil {
+				return cred, err
+			}
+
+			return fallback(ctx, hostport)
+		}
+	}
+
+	return &auth.Client{
+		Client:     httpClient,
+		Cache:      auth.NewCache(),
+		Credential: credential,
+	}
+}
+
+// OCITLSConfig returns the TLS configuration verifying the registry certificate with the CA bundle, and presenting
+// the client certificate for mutual TLS. It returns nil if neither is set.
+func OCITLSConfig(caBundle, clientCert, clientKey []byte) (*tls.Config, error) {
+	if len(caBundle) == 0 && len(clientCert) == 0 && len(clientKey) == 0 {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if len(caBundle) != 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+
+		if !pool.AppendCertsFromPEM(caBundle) {
+			return nil, errors.New("no valid certificates found in the CA bundle")
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	if len(clientCert) != 0 || len(clientKey) != 0 {
+		certificate, err := tls.X509KeyPair(clientCert, clientKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate: %w", err)
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	return tlsConfig, nil
+}
+
+// OCIRegistryAuthentication returns the options to connect to the OCI registry of the provider. The Secrets referenced
+// in the provider OCI configuration take precedence over the default options.
+func OCIRegistryAuthentication(ctx context.Context, cl client.Client, provider operatorv1.GenericProvider, defaults OCIRegistryOptions) (OCIRegistryOptions, error) {
+	options := defaults
+
+	fetchConfig := provider.GetSpec().FetchConfig
+	if fetchConfig == nil || fetchConfig.OCIAuth == nil {
+		return options, nil
+	}
+
+	ociAuth := fetchConfig.OCIAuth
+
+	if ociAuth.DockerConfigSecretRef != nil {
+		dockerConfig, err := providerSecretData(ctx, cl, provider, *ociAuth.DockerConfigSecretRef, corev1.DockerConfigJsonKey)
+		if err != nil {
+			return options, err
+		}
+
+		options.CredentialsStore, err = credentials.NewMemoryStoreFromDockerConfig(dockerConfig)
+		if err != nil {
+			return options, fmt.Errorf("invalid Docker config in secret %s: %w", ociAuth.DockerConfigSecretRef.Name, err)
+		}
+	}
+
+	if ociAuth.CASecretRef == nil && ociAuth.ClientCertSecretRef == nil {
+		return options, nil
+	}
+
+	tlsConfig, err := secretTLSConfig(ctx, cl, provider, ociAuth.CASecretRef, ociAuth.ClientCertSecretRef)
+	if err != nil {
+		return options, err
+	}
+
+	options.TLSConfig = tlsConfig
+
+	return options, nil
+}
+
+// secretTLSConfig returns the TLS configuration with the CA bundle and the client certificate of the referenced
+// Secrets, as built by OCITLSConfig. It returns nil if neither is referenced.
+func secretTLSConfig(ctx context.Context, cl client.Client, provider operatorv1.GenericProvider, caSecretRef, clientCertSecretRef *operatorv1.SecretReference) (*tls.Config, error) {
+	var caBundle, clientCert, clientKey []byte
+
+	if caSecretRef != nil {
+		var err error
+
+		caBundle, err = providerSecretData(ctx, cl, provider, *caSecretRef, corev1.ServiceAccountRootCAKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if clientCertSecretRef != nil {
+		var err error
+
+		clientCert, err = providerSecretData(ctx, cl, provider, *clientCertSecretRef, corev1.TLSCertKey)
+		if err != nil {
+			return nil, err
+		}
+
+		clientKey, err = providerSecretData(ctx, cl, provider, *clientCertSecretRef, corev1.TLSPrivateKeyKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return OCITLSConfig(caBundle, clientCert, clientKey)
+}
+
+// providerSecretData returns the value of the key in the referenced Secret. If the namespace is not set,
+// the provider namespace is used.
+func providerSecretData(ctx context.Context, cl client.Client, provider operatorv1.GenericProvider, secretRef operatorv1.SecretReference, key string) ([]byte, error) {
+	namespace := secretRef.Namespace
+	if namespace == "" {
+		namespace = provider.GetNamespace()
+	}
+
+	secret := &corev1.Secret{}
+	if err := cl.Get(ctx, client.ObjectKey{Name: secretRef.Name, Namespace: namespace}, secret); err != nil {
+		return nil, fmt.Errorf("unable to get secret %s/%s: %w", namespace, secretRef.Name, err)
+	}
+
+	data, ok := secret.Data[key]
+	if !ok || len(data) == 0 {
+		return nil, fmt.Errorf("secret %s/%s has no %q key", namespace, secretRef.Name, key)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
+)
+
+func TestOCIRegistryAuthentication(t *testing.T) {
+	g := NewWithT(t)
+
+	handler, digest := ociTestRegistryHandler(g)
+
+	// The registry requires basic authentication with the credentials of the Docker config.
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username, password, ok := r.BasicAuth(); !ok || username != "user" || password != "secret" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="test"`)
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		handler(w, r)
+	}))
+	defer server.Close()
+
+	registry := strings.TrimPrefix(server.URL, "https://")
+
+	caBundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	dockerConfig := fmt.Sprintf(`{"auths":{%q:{"auth":%q}}}`, registry, base64.StdEncoding.EncodeToString([]byte("user:secret")))
+
+	secrets := []corev1.Secret{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "registry-auth", Namespace: "capi-system"},
+			Type:       corev1.SecretTypeDockerConfigJson,
+			Data:       map[string][]byte{corev1.DockerConfigJsonKey: []byte(dockerConfig)},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "registry-ca", Namespace: "capi-system"},
+			Data:       map[string][]byte{corev1.ServiceAccountRootCAKey: caBundle},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "invalid-client-cert", Namespace: "capi-system"},
+			Type:       corev1.SecretTypeTLS,
+			Data:       map[string][]byte{corev1.TLSCertKey: []byte("cert"), corev1.TLSPrivateKeyKey: []byte("key")},
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		ociAuth       *operatorv1.OCIAuth
+		credential    *auth.Credential
+		expectedErr   bool
+		expectedFetch bool
+	}{
+		{
+			name: "registry certificate not trusted",
+		},
+		{
+			name:    "credentials missing",
+			ociAuth: &operatorv1.OCIAuth{CASecretRef: &operatorv1.SecretReference{Name: "registry-ca"}},
+		},
+		{
+			name:          "credentials from variables",
+			ociAuth:       &operatorv1.OCIAuth{CASecretRef: &operatorv1.SecretReference{Name: "registry-ca"}},
+			credential:    &auth.Credential{Username: "user", Password: "secret"},
+			expectedFetch: true,
+		},
+		{
+			name: "Docker config takes precedence over variables",
+			ociAuth: &operatorv1.OCIAuth{
+				DockerConfigSecretRef: &operatorv1.SecretReference{Name: "registry-auth"},
+				CASecretRef:           &operatorv1.SecretReference{Name: "registry-ca", Namespace: "capi-system"},
+			},
+			credential:    &auth.Credential{Username: "user", Password: "wrong"},
+			expectedFetch: true,
+		},
+		{
+			name:        "missing secret",
+			ociAuth:     &operatorv1.OCIAuth{DockerConfigSecretRef: &operatorv1.SecretReference{Name: "missing"}},
+			expectedErr: true,
+		},
+		{
+			name:        "missing key",
+			ociAuth:     &operatorv1.OCIAuth{CASecretRef: &operatorv1.SecretReference{Name: "registry-auth"}},
+			expectedErr: true,
+		},
+		{
+			name:        "invalid client certificate",
+			ociAuth:     &operatorv1.OCIAuth{ClientCertSecretRef: &operatorv1.SecretReference{Name: "invalid-client-cert"}},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			provider := &operatorv1.CoreProvider{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-api", Namespace: "capi-system"},
+				Spec: operatorv1.CoreProviderSpec{ProviderSpec: operatorv1.ProviderSpec{
+					Version: "v1.0.0",
+					FetchConfig: &operatorv1.FetchConfiguration{OCIConfiguration: operatorv1.OCIConfiguration{
+						OCI:     registry + "/provider",
+						OCIAuth: tc.ociAuth,
+					}},
+				}},
+			}
+
+			fakeclient := fake.NewClientBuilder().WithScheme(cacheTestScheme()).WithObjects(&secrets[0], &secrets[1], &secrets[2]).Build()
+
+			options, err := OCIRegistryAuthentication(context.Background(), fakeclient, provider, OCIRegistryOptions{Credential: tc.credential})
+			if tc.expectedErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+
+			resolved, err := ResolveOCIDigest(context.Background(), provider.Spec.FetchConfig.OCI, provider.Spec.Version, options)
+			if !tc.expectedFetch {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(resolved).To(Equal(digest))
+		})
+	}
+}
+
+func TestOCITLSConfig(t *testing.T) {
+	g := NewWithT(t)
+
+	tlsConfig, err := OCITLSConfig(nil, nil, nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(tlsConfig).To(BeNil())
+
+	_, err = OCITLSConfig([]byte("not a certificate"), nil, nil)
+	g.Expect(err).To(HaveOccurred())
+
+	_, err = OCITLSConfig(nil, []byte("cert"), nil)
+	g.Expect(err).To(HaveOccurred())
+}
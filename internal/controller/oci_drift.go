@@ -0,0 +1,175 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
+	"sigs.k8s.io/cluster-api-operator/internal/controller/genericprovider"
+)
+
+// ociReference returns the OCI reference the provider manifests are fetched with.
+func ociReference(provider genericprovider.GenericProvider) string {
+	url, reference, _ := parseOCISource(provider.GetSpec().FetchConfig.OCI, providerVersion(provider))
+
+	return fmt.Sprintf("%s:%s", url, reference)
+}
+
+// isOCIProvider returns true if the provider manifests are fetched from an OCI artifact.
+func isOCIProvider(provider genericprovider.GenericProvider) bool {
+	return provider.GetSpec().FetchConfig != nil && provider.GetSpec().FetchConfig.OCI != ""
+}
+
+// ociDriftCheckInterval returns the interval the provider OCI reference is re-checked at, or 0 if the check is disabled.
+func ociDriftCheckInterval(provider genericprovider.GenericProvider) time.Duration {
+	if !isOCIProvider(provider) || provider.GetSpec().FetchConfig.OCIDriftCheckInterval == nil {
+		return 0
+	}
+
+	return provider.GetSpec().FetchConfig.OCIDriftCheckInterval.Duration
+}
+
+// isOCIDriftCheckDue returns true if the provider OCI reference has to be re-checked.
+func isOCIDriftCheckDue(provider genericprovider.GenericProvider) bool {
+	interval := ociDriftCheckInterval(provider)
+	if interval == 0 {
+		return false
+	}
+
+	artifact := provider.GetStatus().OCIArtifact
+
+	return artifact == nil || artifact.LastDriftCheck == nil || time.Since(artifact.LastDriftCheck.Time) >= interval
+}
+
+// ociDriftCheckRequeueAfter returns the time until the provider OCI reference has to be re-checked,
+// or 0 if the check is disabled.
+func ociDriftCheckRequeueAfter(provider genericprovider.GenericProvider) time.Duration {
+	interval := ociDriftCheckInterval(provider)
+
+	artifact := provider.GetStatus().OCIArtifact
+	if interval == 0 || artifact == nil || artifact.LastDriftCheck == nil {
+		return interval
+	}
+
+	if remaining := interval - time.Since(artifact.LastDriftCheck.Time); remaining > 0 {
+		return remaining
+	}
+
+	return interval
+}
+
+// setOCIArtifact records the OCI artifact the ConfigMap manifests were fetched from in the provider status.
+func setOCIArtifact(provider genericprovider.GenericProvider, configMap *corev1.ConfigMap) {
+	status := provider.GetStatus()
+
+	digest, ok := configMap.GetAnnotations()[ociDigestAnnotation]
+
+	// Manifests fetched from a fallback source are not tracked against the provider OCI reference.
+	if source, found := configMap.GetAnnotations()[configMapSourceAnnotation]; ok && found && isOCIProvider(provider) && source != provider.GetSpec().FetchConfig.OCI {
+		ok = false
+	}
+
+	if !isOCIProvider(provider) || !ok {
+		status.OCIArtifact = nil
+
+		conditions.Delete(provider, operatorv1.OCIArtifactUpToDateCondition)
+	} else if status.OCIArtifact == nil || status.OCIArtifact.Digest != digest || status.OCIArtifact.Reference != ociReference(provider) {
+		status.OCIArtifact = &operatorv1.OCIArtifactStatus{
+			Reference: ociReference(provider),
+			Digest:    digest,
+		}
+	}
+
+	provider.SetStatus(status)
+}
+
+// reconcileOCIArtifact records the OCI artifact of the existing ConfigMap in the provider status and, when it is due,
+// checks whether the OCI reference still resolves to that artifact.
+func (p *PhaseReconciler) reconcileOCIArtifact(ctx context.Context) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	configMaps := &corev1.ConfigMapList{}
+	if err := p.ctrlClient.List(ctx, configMaps, client.InNamespace(p.provider.GetNamespace()), client.MatchingLabels(p.prepareConfigMapLabels())); err != nil {
+		return fmt.Errorf("failed to list ConfigMaps: %w", err)
+	}
+
+	if len(configMaps.Items) != 1 {
+		return nil
+	}
+
+	setOCIArtifact(p.provider, &configMaps.Items[0])
+
+	if ociDriftCheckInterval(p.provider) == 0 {
+		conditions.Delete(p.provider, operatorv1.OCIArtifactUpToDateCondition)
+
+		return nil
+	}
+
+	artifact := p.provider.GetStatus().OCIArtifact
+	if artifact == nil || !isOCIDriftCheckDue(p.provider) {
+		return nil
+	}
+
+	registry, err := p.ociRegistryOptions(ctx, p.provider)
+	if err != nil {
+		return err
+	}
+
+	digest, err := ResolveOCIDigest(ctx, p.provider.GetSpec().FetchConfig.OCI, providerVersion(p.provider), registry)
+
+	switch {
+	case err != nil:
+		log.Error(err, "Failed to resolve OCI reference", "reference", artifact.Reference)
+
+		conditions.Set(p.provider, metav1.Condition{
+			Type:    operatorv1.OCIArtifactUpToDateCondition,
+			Status:  metav1.ConditionUnknown,
+			Reason:  operatorv1.OCIDriftCheckFailedReason,
+			Message: err.Error(),
+		})
+	case digest != artifact.Digest:
+		log.Info("OCI reference resolves to a different artifact", "reference", artifact.Reference, "digest", digest, "fetchedDigest", artifact.Digest)
+
+		conditions.Set(p.provider, metav1.Condition{
+			Type:    operatorv1.OCIArtifactUpToDateCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  operatorv1.OCITagDriftedReason,
+			Message: fmt.Sprintf("%s resolves to %s, but the provider manifests were fetched from %s", artifact.Reference, digest, artifact.Digest),
+		})
+	default:
+		conditions.Set(p.provider, metav1.Condition{
+			Type:   operatorv1.OCIArtifactUpToDateCondition,
+			Status: metav1.ConditionTrue,
+			Reason: operatorv1.OCIArtifactUpToDateReason,
+		})
+	}
+
+	status := p.provider.GetStatus()
+	status.OCIArtifact.LastDriftCheck = &metav1.Time{Time: time.Now()}
+	p.provider.SetStatus(status)
+
+	return nil
+}
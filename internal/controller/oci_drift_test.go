@@ -0,0 +1,300 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"oras.land/oras-go/v2/content"
+	configclient "sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
+)
+
+// newOCITestRegistry starts a registry serving a single manifest for the "provider:v1.0.0" reference,
+// and returns its URL and the manifest digest.
+func newOCITestRegistry(g *WithT) (*httptest.Server, string) {
+	handler, digest := ociTestRegistryHandler(g)
+
+	return httptest.NewServer(handler), digest
+}
+
+// ociTestRegistryHandler returns a registry handler serving a single manifest for the "provider:v1.0.0" reference,
+// and the manifest digest.
+func ociTestRegistryHandler(g *WithT) (http.HandlerFunc, string) {
+	manifest, err := json.Marshal(ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    content.NewDescriptorFromBytes(ocispec.MediaTypeEmptyJSON, []byte("{}")),
+		Layers:    []ocispec.Descriptor{},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	desc := content.NewDescriptorFromBytes(ocispec.MediaTypeImageManifest, manifest)
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/provider/manifests/v1.0.0" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", ocispec.MediaTypeImageManifest)
+		w.Header().Set("Docker-Content-Digest", desc.Digest.String())
+		w.Header().Set("Content-Length", strconv.Itoa(len(manifest)))
+
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(manifest)
+		}
+	})
+
+	return handler, desc.Digest.String()
+}
+
+func TestOCIDriftCheckDue(t *testing.T) {
+	testCases := []struct {
+		name         string
+		fetchConfig  *operatorv1.FetchConfiguration
+		artifact     *operatorv1.OCIArtifactStatus
+		expectedDue  bool
+		requeueAfter time.Duration
+	}{
+		{
+			name:        "not an OCI provider",
+			fetchConfig: &operatorv1.FetchConfiguration{URL: "https://github.com/kubernetes-sigs/cluster-api/releases"},
+		},
+		{
+			name:        "drift check disabled",
+			fetchConfig: &operatorv1.FetchConfiguration{OCIConfiguration: operatorv1.OCIConfiguration{OCI: "registry.test/provider"}},
+			artifact:    &operatorv1.OCIArtifactStatus{Digest: "sha256:abc"},
+		},
+		{
+			name: "never checked",
+			fetchConfig: &operatorv1.FetchConfiguration{OCIConfiguration: operatorv1.OCIConfiguration{
+				OCI:                   "registry.test/provider",
+				OCIDriftCheckInterval: &metav1.Duration{Duration: time.Hour},
+			}},
+			artifact:     &operatorv1.OCIArtifactStatus{Digest: "sha256:abc"},
+			expectedDue:  true,
+			requeueAfter: time.Hour,
+		},
+		{
+			name: "checked recently",
+			fetchConfig: &operatorv1.FetchConfiguration{OCIConfiguration: operatorv1.OCIConfiguration{
+				OCI:                   "registry.test/provider",
+				OCIDriftCheckInterval: &metav1.Duration{Duration: time.Hour},
+			}},
+			artifact:     &operatorv1.OCIArtifactStatus{Digest: "sha256:abc", LastDriftCheck: &metav1.Time{Time: time.Now().Add(-30 * time.Minute)}},
+			requeueAfter: 30 * time.Minute,
+		},
+		{
+			name: "check interval elapsed",
+			fetchConfig: &operatorv1.FetchConfiguration{OCIConfiguration: operatorv1.OCIConfiguration{
+				OCI:                   "registry.test/provider",
+				OCIDriftCheckInterval: &metav1.Duration{Duration: time.Hour},
+			}},
+			artifact:     &operatorv1.OCIArtifactStatus{Digest: "sha256:abc", LastDriftCheck: &metav1.Time{Time: time.Now().Add(-2 * time.Hour)}},
+			expectedDue:  true,
+			requeueAfter: time.Hour,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			provider := &operatorv1.CoreProvider{
+				Spec:   operatorv1.CoreProviderSpec{ProviderSpec: operatorv1.ProviderSpec{FetchConfig: tc.fetchConfig}},
+				Status: operatorv1.CoreProviderStatus{ProviderStatus: operatorv1.ProviderStatus{OCIArtifact: tc.artifact}},
+			}
+
+			g.Expect(isOCIDriftCheckDue(provider)).To(Equal(tc.expectedDue))
+			g.Expect(ociDriftCheckRequeueAfter(provider)).To(BeNumerically("~", tc.requeueAfter, time.Minute))
+		})
+	}
+}
+
+func TestReconcileOCIArtifact(t *testing.T) {
+	g := NewWithT(t)
+
+	server, digest := newOCITestRegistry(g)
+	defer server.Close()
+
+	configClient, err := configclient.New(context.Background(), "", configclient.InjectReader(configclient.NewMemoryReader()))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	testCases := []struct {
+		name              string
+		fetchedDigest     string
+		lastDriftCheck    *metav1.Time
+		interval          *metav1.Duration
+		expectedCondition *metav1.Condition
+	}{
+		{
+			name:          "drift check disabled",
+			fetchedDigest: digest,
+		},
+		{
+			name:              "tag resolves to the fetched artifact",
+			fetchedDigest:     digest,
+			interval:          &metav1.Duration{Duration: time.Hour},
+			expectedCondition: &metav1.Condition{Status: metav1.ConditionTrue, Reason: operatorv1.OCIArtifactUpToDateReason},
+		},
+		{
+			name:              "tag was re-pushed",
+			fetchedDigest:     "sha256:" + strings.Repeat("0", 64),
+			interval:          &metav1.Duration{Duration: time.Hour},
+			expectedCondition: &metav1.Condition{Status: metav1.ConditionFalse, Reason: operatorv1.OCITagDriftedReason},
+		},
+		{
+			name:           "drift check not due",
+			fetchedDigest:  "sha256:" + strings.Repeat("0", 64),
+			interval:       &metav1.Duration{Duration: time.Hour},
+			lastDriftCheck: &metav1.Time{Time: time.Now()},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			provider := &operatorv1.CoreProvider{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-api", Namespace: "capi-system"},
+				Spec: operatorv1.CoreProviderSpec{ProviderSpec: operatorv1.ProviderSpec{
+					Version: "v1.0.0",
+					FetchConfig: &operatorv1.FetchConfiguration{OCIConfiguration: operatorv1.OCIConfiguration{
+						OCI:                   server.URL + "/provider",
+						OCIDriftCheckInterval: tc.interval,
+					}},
+				}},
+			}
+
+			if tc.lastDriftCheck != nil {
+				provider.Status.OCIArtifact = &operatorv1.OCIArtifactStatus{
+					Reference:      ociReference(provider),
+					Digest:         tc.fetchedDigest,
+					LastDriftCheck: tc.lastDriftCheck,
+				}
+			}
+
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "core-cluster-api-v1.0.0",
+					Namespace:   "capi-system",
+					Labels:      ProviderLabels(provider),
+					Annotations: map[string]string{ociDigestAnnotation: tc.fetchedDigest},
+				},
+			}
+
+			p := &PhaseReconciler{
+				ctrlClient:   fake.NewClientBuilder().WithScheme(cacheTestScheme()).WithObjects(configMap).Build(),
+				provider:     provider,
+				configClient: configClient,
+			}
+
+			g.Expect(p.reconcileOCIArtifact(context.Background())).To(Succeed())
+
+			g.Expect(provider.Status.OCIArtifact).NotTo(BeNil())
+			g.Expect(provider.Status.OCIArtifact.Digest).To(Equal(tc.fetchedDigest))
+			g.Expect(provider.Status.OCIArtifact.Reference).To(Equal(strings.TrimPrefix(server.URL, "http://") + "/provider:v1.0.0"))
+
+			condition := conditions.Get(provider, operatorv1.OCIArtifactUpToDateCondition)
+			if tc.expectedCondition == nil {
+				g.Expect(condition).To(BeNil())
+				return
+			}
+
+			g.Expect(condition).NotTo(BeNil())
+			g.Expect(condition.Status).To(Equal(tc.expectedCondition.Status))
+			g.Expect(condition.Reason).To(Equal(tc.expectedCondition.Reason))
+			g.Expect(provider.Status.OCIArtifact.LastDriftCheck).NotTo(BeNil())
+		})
+	}
+}
+
+func TestApplyFromCacheChecksOCIDrift(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	server, digest := newOCITestRegistry(g)
+	defer server.Close()
+
+	configClient, err := configclient.New(ctx, "", configclient.InjectReader(configclient.NewMemoryReader()))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	provider := &operatorv1.CoreProvider{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-api", Namespace: "capi-system"},
+		Spec: operatorv1.CoreProviderSpec{ProviderSpec: operatorv1.ProviderSpec{
+			Version: "v1.0.0",
+			FetchConfig: &operatorv1.FetchConfiguration{OCIConfiguration: operatorv1.OCIConfiguration{
+				OCI:                   server.URL + "/provider",
+				OCIDriftCheckInterval: &metav1.Duration{Duration: time.Hour},
+			}},
+		}},
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "core-cluster-api-v1.0.0",
+			Namespace:   "capi-system",
+			Labels:      ProviderLabels(provider),
+			Annotations: map[string]string{ociDigestAnnotation: digest},
+		},
+	}
+
+	cache := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: ProviderCacheName(provider), Namespace: "capi-system"},
+		Data:       map[string][]byte{"cache": []byte("[]")},
+	}
+
+	cl := fake.NewClientBuilder().WithScheme(cacheTestScheme()).WithObjects(configMap, cache).Build()
+
+	cacheHash, err := calculateCacheHash(ctx, cl, provider, cache.Data)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	cache.Annotations = map[string]string{AppliedSpecHashAnnotation: cacheHash}
+	g.Expect(cl.Update(ctx, cache)).To(Succeed())
+
+	provider.Annotations = map[string]string{AppliedSpecHashAnnotation: cacheHash}
+
+	p := &PhaseReconciler{
+		ctrlClient:   cl,
+		provider:     provider,
+		configClient: configClient,
+	}
+
+	result, err := p.ApplyFromCache(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.Completed).To(BeTrue(), "the cached manifests are applied while the OCI artifact is checked for drift")
+
+	g.Expect(conditions.IsTrue(provider, operatorv1.OCIArtifactUpToDateCondition)).To(BeTrue())
+	g.Expect(provider.Status.OCIArtifact).NotTo(BeNil())
+	g.Expect(provider.Status.OCIArtifact.LastDriftCheck).NotTo(BeNil())
+}
@@ -18,18 +18,30 @@ package controller
 
 import (
 	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
 	"fmt"
 	"io"
 	"strings"
 
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	corev1 "k8s.io/api/core/v1"
 	"oras.land/oras-go/v2"
 	"oras.land/oras-go/v2/content"
 	"oras.land/oras-go/v2/registry/remote"
 	"oras.land/oras-go/v2/registry/remote/auth"
-	"oras.land/oras-go/v2/registry/remote/retry"
 	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
 	configclient "sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
@@ -45,12 +57,29 @@ const (
 	componentsFile      = "components.yaml"
 	typedComponentsFile = "%s-components.yaml"
 	fullComponentsFile  = "%s-%s-%s-components.yaml"
+
+	defaultOCIPublicKeySecretKey = "cosign.pub"
+	cosignSignatureTagSuffix     = ".sig"
+	cosignSignatureAnnotation    = "dev.cosignproject.cosign/signature"
 )
 
+// ErrOCIVerification is returned when the OCI artifact doesn't match the pinned digest, or its signature can't be verified.
+var ErrOCIVerification = errors.New("OCI artifact verification failed")
+
+// OCIArtifactVerification defines the checks an OCI artifact has to pass before its content is used.
+type OCIArtifactVerification struct {
+	// Digest is the expected manifest digest of the artifact.
+	Digest string
+
+	// PublicKey is the PEM encoded public key the artifact signature is verified with.
+	PublicKey []byte
+}
+
 // mapStore is a pre-initialized map with expected file names to copy from OCI artifact.
 type mapStore struct {
 	data   map[string][]byte
 	source oras.Target
+	digest string
 }
 
 // NewMapStore initializes mapStore for the provider resource.
@@ -59,16 +88,21 @@ func NewMapStore(p operatorv1.GenericProvider) mapStore {
 		data: map[string][]byte{
 			metadataFile:   nil,
 			componentsFile: nil,
-			fmt.Sprintf(typedComponentsFile, p.GetType()):                                       nil,
-			fmt.Sprintf(fullMetadataFile, p.GetType(), p.ProviderName(), p.GetSpec().Version):   nil,
-			fmt.Sprintf(fullComponentsFile, p.GetType(), p.ProviderName(), p.GetSpec().Version): nil,
+			fmt.Sprintf(typedComponentsFile, p.GetType()):                                      nil,
+			fmt.Sprintf(fullMetadataFile, p.GetType(), p.ProviderName(), providerVersion(p)):   nil,
+			fmt.Sprintf(fullComponentsFile, p.GetType(), p.ProviderName(), providerVersion(p)): nil,
 		},
 	}
 }
 
+// Digest returns the manifest digest of the OCI artifact copied to the store.
+func (m mapStore) Digest() string {
+	return m.digest
+}
+
 // GetMetadata returns metadata file for the provider.
 func (m mapStore) GetMetadata(p operatorv1.GenericProvider) ([]byte, error) {
-	fullMetadataKey := fmt.Sprintf(fullMetadataFile, p.GetType(), p.ProviderName(), p.GetSpec().Version)
+	fullMetadataKey := fmt.Sprintf(fullMetadataFile, p.GetType(), p.ProviderName(), providerVersion(p))
 
 	data := m.data[fullMetadataKey]
 	if len(data) != 0 {
@@ -85,7 +119,7 @@ func (m mapStore) GetMetadata(p operatorv1.GenericProvider) ([]byte, error) {
 
 // GetComponents returns componenents file for the provider.
 func (m mapStore) GetComponents(p operatorv1.GenericProvider) ([]byte, error) {
-	fullComponentsKey := fmt.Sprintf(fullComponentsFile, p.GetType(), p.ProviderName(), p.GetSpec().Version)
+	fullComponentsKey := fmt.Sprintf(fullComponentsFile, p.GetType(), p.ProviderName(), providerVersion(p))
 
 	data := m.data[fullComponentsKey]
 	if len(data) != 0 {
@@ -214,28 +248,25 @@ func parseOCISource(url string, version string) (string, string, bool) {
 }
 
 // CopyOCIStore collects artifacts from the provider OCI url and creates a map of file contents.
-func CopyOCIStore(ctx context.Context, url string, version string, store *mapStore, credential *auth.Credential) error {
-	url, version, plainHTTP := parseOCISource(url, version)
-
-	repo, err := remote.NewRepository(url)
+// The OCI reference is resolved to a digest, and the artifact is verified before its content is copied by digest.
+func CopyOCIStore(ctx context.Context, url string, version string, store *mapStore, registry OCIRegistryOptions, verification OCIArtifactVerification) error {
+	repo, reference, err := newOCIRepository(url, version, registry)
 	if err != nil {
-		return fmt.Errorf("invalid registry URL specified: %w", err)
-	}
-
-	if credential != nil {
-		repo.Client = &auth.Client{
-			Client:     retry.DefaultClient,
-			Cache:      auth.NewCache(),
-			Credential: auth.StaticCredential(repo.Reference.Registry, *credential),
-		}
+		return err
 	}
 
-	repo.PlainHTTP = plainHTTP
-
 	// Set the source repository for restoring duplicated content inside the artifact
 	store.source = repo
 
-	_, err = oras.Copy(ctx, repo, version, store, version, oras.CopyOptions{
+	desc, err := verifyOCIArtifact(ctx, repo, reference, verification)
+	if err != nil {
+		return err
+	}
+
+	// Copy the artifact by digest, so a tag pushed in the meantime is not picked up.
+	store.digest = desc.Digest.String()
+
+	_, err = oras.Copy(ctx, repo, store.digest, store, reference, oras.CopyOptions{
 		CopyGraphOptions: oras.CopyGraphOptions{
 			PreCopy: store.selector,
 		},
@@ -247,6 +278,36 @@ func CopyOCIStore(ctx context.Context, url string, version string, store *mapSto
 	return nil
 }
 
+// ResolveOCIDigest returns the manifest digest the provider OCI reference currently resolves to.
+func ResolveOCIDigest(ctx context.Context, url string, version string, registry OCIRegistryOptions) (string, error) {
+	repo, reference, err := newOCIRepository(url, version, registry)
+	if err != nil {
+		return "", err
+	}
+
+	desc, err := repo.Resolve(ctx, reference)
+	if err != nil {
+		return "", fmt.Errorf("unable to resolve OCI reference %q: %w", reference, err)
+	}
+
+	return desc.Digest.String(), nil
+}
+
+// newOCIRepository returns the remote repository of the OCI url, and the reference of the artifact in it.
+func newOCIRepository(url string, version string, registry OCIRegistryOptions) (*remote.Repository, string, error) {
+	url, reference, plainHTTP := parseOCISource(url, version)
+
+	repo, err := remote.NewRepository(url)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid registry URL specified: %w", err)
+	}
+
+	repo.Client = registry.Client(repo.Reference.Registry)
+	repo.PlainHTTP = plainHTTP
+
+	return repo, reference, nil
+}
+
 // OCIAuthentication returns user supplied credentials from provider variables.
 func OCIAuthentication(c configclient.VariablesClient) *auth.Credential {
 	username, _ := c.Get(OCIUsernameKey)
@@ -266,8 +327,9 @@ func OCIAuthentication(c configclient.VariablesClient) *auth.Credential {
 	return nil
 }
 
-// FetchOCI copies the content of OCI.
-func FetchOCI(ctx context.Context, provider operatorv1.GenericProvider, cred *auth.Credential) (*mapStore, error) {
+// FetchOCI copies the content of OCI. The artifact is verified against the pinned digest, and if a public key
+// is provided, against its signature.
+func FetchOCI(ctx context.Context, provider operatorv1.GenericProvider, registry OCIRegistryOptions, publicKey []byte) (*mapStore, error) {
 	log := log.FromContext(ctx)
 
 	log.V(2).Info("Custom fetch configuration OCI url was provided")
@@ -275,10 +337,191 @@ func FetchOCI(ctx context.Context, provider operatorv1.GenericProvider, cred *au
 	// Prepare components store for the provider type.
 	store := NewMapStore(provider)
 
-	err := CopyOCIStore(ctx, provider.GetSpec().FetchConfig.OCI, provider.GetSpec().Version, &store, cred)
+	fetchConfig := provider.GetSpec().FetchConfig
+	verification := OCIArtifactVerification{
+		Digest:    fetchConfig.OCIDigest,
+		PublicKey: publicKey,
+	}
+
+	err := CopyOCIStore(ctx, fetchConfig.OCI, providerVersion(provider), &store, registry, verification)
 	if err != nil {
 		return nil, fmt.Errorf("unable to copy OCI content: %w", err)
 	}
 
 	return &store, nil
 }
+
+// OCIVerificationPublicKey returns the public key the OCI artifact signature of the provider is verified with,
+// or nil if the signature verification is not configured.
+func OCIVerificationPublicKey(ctx context.Context, cl client.Client, provider operatorv1.GenericProvider) ([]byte, error) {
+	fetchConfig := provider.GetSpec().FetchConfig
+	if fetchConfig == nil || fetchConfig.OCIVerification == nil {
+		return nil, nil
+	}
+
+	secretRef := fetchConfig.OCIVerification.PublicKeySecretRef
+
+	namespace := secretRef.Namespace
+	if namespace == "" {
+		namespace = provider.GetNamespace()
+	}
+
+	key := fetchConfig.OCIVerification.PublicKeySecretKey
+	if key == "" {
+		key = defaultOCIPublicKeySecretKey
+	}
+
+	secret := &corev1.Secret{}
+	if err := cl.Get(ctx, client.ObjectKey{Name: secretRef.Name, Namespace: namespace}, secret); err != nil {
+		return nil, fmt.Errorf("%w: unable to get public key secret %s/%s: %w", ErrOCIVerification, namespace, secretRef.Name, err)
+	}
+
+	publicKey, ok := secret.Data[key]
+	if !ok || len(publicKey) == 0 {
+		return nil, fmt.Errorf("%w: public key secret %s/%s has no %q key", ErrOCIVerification, namespace, secretRef.Name, key)
+	}
+
+	return publicKey, nil
+}
+
+// verifyOCIArtifact resolves the reference and verifies the artifact against the pinned digest and the signature.
+// It returns the descriptor of the verified artifact.
+func verifyOCIArtifact(ctx context.Context, source oras.ReadOnlyTarget, reference string, verification OCIArtifactVerification) (ocispec.Descriptor, error) {
+	desc, err := source.Resolve(ctx, reference)
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("unable to resolve OCI reference %q: %w", reference, err)
+	}
+
+	if verification.Digest != "" && desc.Digest.String() != verification.Digest {
+		return ocispec.Descriptor{}, fmt.Errorf("%w: %q resolved to digest %s, expected %s", ErrOCIVerification, reference, desc.Digest, verification.Digest)
+	}
+
+	if len(verification.PublicKey) != 0 {
+		if err := verifyCosignSignature(ctx, source, desc, verification.PublicKey); err != nil {
+			return ocispec.Descriptor{}, fmt.Errorf("%w: %w", ErrOCIVerification, err)
+		}
+	}
+
+	return desc, nil
+}
+
+// cosignPayload is the part of the cosign simple signing payload identifying the signed artifact.
+type cosignPayload struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+	} `json:"critical"`
+}
+
+// verifyCosignSignature looks up the cosign signatures of the artifact, and checks that at least one of them
+// is signed with the public key and refers to the artifact digest. Only the legacy cosign format, with the
+// signatures stored under the "sha256-<digest>.sig" tag, is supported; signatures attached with the OCI 1.1
+// referrers API and Sigstore bundles are not found.
+func verifyCosignSignature(ctx context.Context, source oras.ReadOnlyTarget, desc ocispec.Descriptor, publicKeyPEM []byte) error {
+	publicKey, err := parsePublicKey(publicKeyPEM)
+	if err != nil {
+		return err
+	}
+
+	signatureTag := strings.Replace(desc.Digest.String(), ":", "-", 1) + cosignSignatureTagSuffix
+
+	signatureDesc, err := source.Resolve(ctx, signatureTag)
+	if err != nil {
+		return fmt.Errorf("unable to find signature %q for digest %s, only legacy cosign signatures are supported, "+
+			"OCI 1.1 referrers and Sigstore bundles are not: %w", signatureTag, desc.Digest, err)
+	}
+
+	manifestData, err := content.FetchAll(ctx, source, signatureDesc)
+	if err != nil {
+		return fmt.Errorf("unable to fetch signature manifest: %w", err)
+	}
+
+	manifest := ocispec.Manifest{}
+	if err := json.Unmarshal(manifestData, &manifest); err != nil {
+		return fmt.Errorf("unable to parse signature manifest: %w", err)
+	}
+
+	for _, layer := range manifest.Layers {
+		encodedSignature, ok := layer.Annotations[cosignSignatureAnnotation]
+		if !ok {
+			continue
+		}
+
+		signature, err := base64.StdEncoding.DecodeString(encodedSignature)
+		if err != nil {
+			continue
+		}
+
+		payload, err := content.FetchAll(ctx, source, layer)
+		if err != nil {
+			return fmt.Errorf("unable to fetch signature payload: %w", err)
+		}
+
+		if !verifySignature(publicKey, payload, signature) {
+			continue
+		}
+
+		signed := cosignPayload{}
+		if err := json.Unmarshal(payload, &signed); err != nil {
+			continue
+		}
+
+		if signed.Critical.Image.DockerManifestDigest == desc.Digest.String() {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("no valid signature found for digest %s in the %q annotations of %q", desc.Digest, cosignSignatureAnnotation, signatureTag)
+}
+
+// publicKeyFingerprint returns the sha256 checksum of the public key, identifying the key the OCI artifact
+// signature was verified with.
+func publicKeyFingerprint(publicKey []byte) string {
+	sum := sha256.Sum256(publicKey)
+
+	return hex.EncodeToString(sum[:])
+}
+
+// ociVerificationOutdated returns true if the manifests of the ConfigMap were not verified against the pinned
+// digest or the signature key of the provider.
+func ociVerificationOutdated(provider operatorv1.GenericProvider, configMap *corev1.ConfigMap, publicKey []byte) bool {
+	annotations := configMap.GetAnnotations()
+
+	if digest := provider.GetSpec().FetchConfig.OCIDigest; digest != "" && annotations[ociDigestAnnotation] != digest {
+		return true
+	}
+
+	return len(publicKey) != 0 && annotations[ociPublicKeyAnnotation] != publicKeyFingerprint(publicKey)
+}
+
+// parsePublicKey parses a PEM encoded PKIX public key.
+func parsePublicKey(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("public key is not PEM encoded")
+	}
+
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse public key: %w", err)
+	}
+
+	return publicKey, nil
+}
+
+// verifySignature verifies the signature of the payload, using SHA-256 for ECDSA and RSA keys.
+func verifySignature(publicKey crypto.PublicKey, payload, signature []byte) bool {
+	digest := sha256.Sum256(payload)
+
+	switch key := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		return ecdsa.VerifyASN1(key, digest[:], signature)
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature) == nil
+	case ed25519.PublicKey:
+		return ed25519.Verify(key, payload, signature)
+	}
+
+	return false
+}
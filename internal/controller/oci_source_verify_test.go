@@ -0,0 +1,323 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/content/memory"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
+)
+
+// pushOCITestManifest pushes a manifest with the given layers to the store, and tags it.
+func pushOCITestManifest(g *WithT, store *memory.Store, tag string, layers ...ocispec.Descriptor) ocispec.Descriptor {
+	ctx := context.Background()
+
+	config := content.NewDescriptorFromBytes(ocispec.MediaTypeEmptyJSON, []byte("{}"))
+	if exists, _ := store.Exists(ctx, config); !exists {
+		g.Expect(store.Push(ctx, config, bytes.NewReader([]byte("{}")))).To(Succeed())
+	}
+
+	manifest, err := json.Marshal(ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    config,
+		Layers:    layers,
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	desc := content.NewDescriptorFromBytes(ocispec.MediaTypeImageManifest, manifest)
+	g.Expect(store.Push(ctx, desc, bytes.NewReader(manifest))).To(Succeed())
+	g.Expect(store.Tag(ctx, desc, tag)).To(Succeed())
+
+	return desc
+}
+
+// pushOCITestBlob pushes a blob to the store.
+func pushOCITestBlob(g *WithT, store *memory.Store, mediaType string, data []byte, annotations map[string]string) ocispec.Descriptor {
+	desc := content.NewDescriptorFromBytes(mediaType, data)
+	g.Expect(store.Push(context.Background(), desc, bytes.NewReader(data))).To(Succeed())
+
+	desc.Annotations = annotations
+
+	return desc
+}
+
+// signOCITestArtifact pushes a cosign signature of the artifact digest, signed with the key.
+func signOCITestArtifact(g *WithT, store *memory.Store, artifact ocispec.Descriptor, key *ecdsa.PrivateKey) {
+	payload := []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":"registry.test/provider"},"image":{"docker-manifest-digest":%q},"type":"cosign container image signature"},"optional":null}`, artifact.Digest))
+
+	hash := sha256.Sum256(payload)
+	signature, err := ecdsa.SignASN1(rand.Reader, key, hash[:])
+	g.Expect(err).NotTo(HaveOccurred())
+
+	layer := pushOCITestBlob(g, store, "application/vnd.dev.cosign.simplesigning.v1+json", payload, map[string]string{
+		cosignSignatureAnnotation: base64.StdEncoding.EncodeToString(signature),
+	})
+
+	pushOCITestManifest(g, store, "sha256-"+artifact.Digest.Encoded()+".sig", layer)
+}
+
+func ociTestPublicKey(g *WithT, key *ecdsa.PrivateKey) []byte {
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+func TestVerifyOCIArtifact(t *testing.T) {
+	g := NewWithT(t)
+
+	signingKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	store := memory.New()
+
+	components := pushOCITestBlob(g, store, "application/vnd.test.file", []byte("kind: Namespace"), map[string]string{
+		ocispec.AnnotationTitle: componentsFile,
+	})
+	signed := pushOCITestManifest(g, store, "v1.0.0", components)
+	signOCITestArtifact(g, store, signed, signingKey)
+
+	// An unsigned artifact with a different digest.
+	pushOCITestManifest(g, store, "v1.1.0", components, components)
+
+	testCases := []struct {
+		name           string
+		reference      string
+		verification   OCIArtifactVerification
+		expectedDigest string
+		expectedErr    bool
+	}{
+		{
+			name:           "matching digest",
+			reference:      "v1.0.0",
+			verification:   OCIArtifactVerification{Digest: signed.Digest.String()},
+			expectedDigest: signed.Digest.String(),
+		},
+		{
+			name:         "digest mismatch",
+			reference:    "v1.1.0",
+			verification: OCIArtifactVerification{Digest: signed.Digest.String()},
+			expectedErr:  true,
+		},
+		{
+			name:           "valid signature",
+			reference:      "v1.0.0",
+			verification:   OCIArtifactVerification{PublicKey: ociTestPublicKey(g, signingKey)},
+			expectedDigest: signed.Digest.String(),
+		},
+		{
+			name:         "signed with another key",
+			reference:    "v1.0.0",
+			verification: OCIArtifactVerification{PublicKey: ociTestPublicKey(g, otherKey)},
+			expectedErr:  true,
+		},
+		{
+			name:         "missing signature",
+			reference:    "v1.1.0",
+			verification: OCIArtifactVerification{PublicKey: ociTestPublicKey(g, signingKey)},
+			expectedErr:  true,
+		},
+		{
+			name:         "invalid public key",
+			reference:    "v1.0.0",
+			verification: OCIArtifactVerification{PublicKey: []byte("not a key")},
+			expectedErr:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			desc, err := verifyOCIArtifact(context.Background(), store, tc.reference, tc.verification)
+			if tc.expectedErr {
+				g.Expect(err).To(MatchError(ErrOCIVerification))
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(desc.Digest.String()).To(Equal(tc.expectedDigest))
+		})
+	}
+}
+
+func TestOCIVerificationPublicKey(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "cosign", Namespace: "capi-system"},
+		Data: map[string][]byte{
+			defaultOCIPublicKeySecretKey: []byte("public key"),
+			"custom.pub":                 []byte("custom public key"),
+		},
+	}
+
+	testCases := []struct {
+		name         string
+		verification *operatorv1.OCIVerification
+		expectedKey  []byte
+		expectedErr  bool
+	}{
+		{
+			name: "verification not configured",
+		},
+		{
+			name:         "default key",
+			verification: &operatorv1.OCIVerification{PublicKeySecretRef: operatorv1.SecretReference{Name: "cosign"}},
+			expectedKey:  []byte("public key"),
+		},
+		{
+			name: "custom key",
+			verification: &operatorv1.OCIVerification{
+				PublicKeySecretRef: operatorv1.SecretReference{Name: "cosign", Namespace: "capi-system"},
+				PublicKeySecretKey: "custom.pub",
+			},
+			expectedKey: []byte("custom public key"),
+		},
+		{
+			name:         "missing key",
+			verification: &operatorv1.OCIVerification{PublicKeySecretRef: operatorv1.SecretReference{Name: "cosign"}, PublicKeySecretKey: "missing.pub"},
+			expectedErr:  true,
+		},
+		{
+			name:         "missing secret",
+			verification: &operatorv1.OCIVerification{PublicKeySecretRef: operatorv1.SecretReference{Name: "missing"}},
+			expectedErr:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			provider := &operatorv1.CoreProvider{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-api", Namespace: "capi-system"},
+				Spec: operatorv1.CoreProviderSpec{ProviderSpec: operatorv1.ProviderSpec{
+					FetchConfig: &operatorv1.FetchConfiguration{OCIConfiguration: operatorv1.OCIConfiguration{
+						OCI:             "registry.test/provider",
+						OCIVerification: tc.verification,
+					}},
+				}},
+			}
+
+			fakeclient := fake.NewClientBuilder().WithScheme(cacheTestScheme()).WithObjects(secret).Build()
+
+			key, err := OCIVerificationPublicKey(context.Background(), fakeclient, provider)
+			if tc.expectedErr {
+				g.Expect(err).To(MatchError(ErrOCIVerification))
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(key).To(Equal(tc.expectedKey))
+		})
+	}
+}
+
+func TestOCIVerificationOutdated(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("a", 64)
+	publicKey := []byte("public key")
+
+	testCases := []struct {
+		name        string
+		ociDigest   string
+		publicKey   []byte
+		annotations map[string]string
+		expected    bool
+	}{
+		{
+			name:        "verification not configured",
+			annotations: map[string]string{ociDigestAnnotation: digest},
+		},
+		{
+			name:        "verified with the pinned digest",
+			ociDigest:   digest,
+			annotations: map[string]string{ociDigestAnnotation: digest},
+		},
+		{
+			name:        "digest pinned after the download",
+			ociDigest:   "sha256:" + strings.Repeat("b", 64),
+			annotations: map[string]string{ociDigestAnnotation: digest},
+			expected:    true,
+		},
+		{
+			name:      "verified with the public key",
+			publicKey: publicKey,
+			annotations: map[string]string{
+				ociDigestAnnotation:    digest,
+				ociPublicKeyAnnotation: publicKeyFingerprint(publicKey),
+			},
+		},
+		{
+			name:        "signature verification configured after the download",
+			publicKey:   publicKey,
+			annotations: map[string]string{ociDigestAnnotation: digest},
+			expected:    true,
+		},
+		{
+			name:      "public key changed after the download",
+			publicKey: []byte("other public key"),
+			annotations: map[string]string{
+				ociDigestAnnotation:    digest,
+				ociPublicKeyAnnotation: publicKeyFingerprint(publicKey),
+			},
+			expected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			provider := &operatorv1.CoreProvider{
+				Spec: operatorv1.CoreProviderSpec{ProviderSpec: operatorv1.ProviderSpec{
+					FetchConfig: &operatorv1.FetchConfiguration{OCIConfiguration: operatorv1.OCIConfiguration{
+						OCI:       "registry.test/provider",
+						OCIDigest: tc.ociDigest,
+					}},
+				}},
+			}
+
+			configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+
+			g.Expect(ociVerificationOutdated(provider, configMap, tc.publicKey)).To(Equal(tc.expected))
+		})
+	}
+}
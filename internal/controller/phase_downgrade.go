@@ -0,0 +1,107 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/version"
+	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
+	"sigs.k8s.io/cluster-api-operator/util"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const incompatibleDowngradeContractMessage = "Downgrade of provider %s to %s is not possible: target contract %s isn't compatible with the core provider contract %s"
+
+// downgrade deletes the installed provider components and installs the target version, as the clusterctl
+// upgrade plan doesn't support downgrades.
+func (p *PhaseReconciler) downgrade(ctx context.Context) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	log.Info("Deleting provider components before the downgrade", "installedVersion", *p.provider.GetStatus().InstalledVersion)
+
+	if _, err := p.Delete(ctx); err != nil {
+		return err
+	}
+
+	log.Info("Installing provider components for the downgrade", "version", providerVersion(p.provider))
+
+	return p.newClusterClient().ProviderComponents().Create(ctx, p.components.Objs())
+}
+
+// validateDowngradeContract checks that the target version contract, read from the provider metadata,
+// is compatible with the contract of the installed core provider.
+func (p *PhaseReconciler) validateDowngradeContract(ctx context.Context) error {
+	var (
+		coreContract string
+		compatible   bool
+	)
+
+	if p.providerTypeMapper(p.provider) == clusterctlv1.CoreProviderType {
+		// The core provider must stay compatible with the contract the other providers are abiding by.
+		if contract := p.provider.GetStatus().Contract; contract != nil {
+			coreContract = *contract
+		}
+
+		compatible = util.CompatibleContracts(p.contract).Has(coreContract)
+	} else {
+		if err := p.providerLister(ctx, &clusterctlv1.ProviderList{}, coreProviderContract(&coreContract, p.providerTypeMapper)); err != nil {
+			return fmt.Errorf("failed to get core provider contract: %w", err)
+		}
+
+		compatible = util.CompatibleContracts(coreContract).Has(p.contract)
+	}
+
+	if coreContract != "" && !compatible {
+		return fmt.Errorf(incompatibleDowngradeContractMessage, p.provider.GetName(), providerVersion(p.provider), p.contract, coreContract)
+	}
+
+	return nil
+}
+
+// coreProviderContract returns the contract of the installed core provider.
+func coreProviderContract(contract *string, mapper ProviderTypeMapper) ProviderOperation {
+	return func(provider operatorv1.GenericProvider) error {
+		if mapper(provider) == clusterctlv1.CoreProviderType && provider.GetStatus().Contract != nil {
+			*contract = *provider.GetStatus().Contract
+		}
+
+		return nil
+	}
+}
+
+// isDowngradeAllowed returns true if the provider is annotated to allow downgrades.
+func isDowngradeAllowed(provider operatorv1.GenericProvider) bool {
+	return provider.GetAnnotations()[operatorv1.AllowDowngradeAnnotation] == operatorv1.TrueValue
+}
+
+// isDowngrade returns true if the target version is older than the installed one.
+func isDowngrade(installedVersion, targetVersion string) bool {
+	installed, err := version.ParseSemantic(installedVersion)
+	if err != nil {
+		return false
+	}
+
+	target, err := version.ParseSemantic(targetVersion)
+	if err != nil {
+		return false
+	}
+
+	return target.LessThan(installed)
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
+	"sigs.k8s.io/cluster-api-operator/util"
+)
+
+func TestIsDowngrade(t *testing.T) {
+	testCases := []struct {
+		name             string
+		installedVersion string
+		targetVersion    string
+		expected         bool
+	}{
+		{
+			name:             "major downgrade",
+			installedVersion: "v2.0.0",
+			targetVersion:    "v1.9.0",
+			expected:         true,
+		},
+		{
+			name:             "minor downgrade",
+			installedVersion: "v1.10.0",
+			targetVersion:    "v1.9.3",
+			expected:         true,
+		},
+		{
+			name:             "patch downgrade",
+			installedVersion: "v1.10.1",
+			targetVersion:    "v1.10.0",
+			expected:         true,
+		},
+		{
+			name:             "upgrade",
+			installedVersion: "v1.9.0",
+			targetVersion:    "v1.10.0",
+		},
+		{
+			name:             "invalid version",
+			installedVersion: "v1.9.0",
+			targetVersion:    "latest",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(isDowngrade(tc.installedVersion, tc.targetVersion)).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestValidateDowngradeContract(t *testing.T) {
+	testCases := []struct {
+		name           string
+		provider       operatorv1.GenericProvider
+		coreContract   string
+		targetContract string
+		expectedErr    bool
+	}{
+		{
+			name:           "core provider keeps the contract",
+			provider:       &operatorv1.CoreProvider{ObjectMeta: metav1.ObjectMeta{Name: "cluster-api", Namespace: "capi-system"}},
+			coreContract:   "v1beta2",
+			targetContract: "v1beta2",
+		},
+		{
+			name:           "core provider changes the contract",
+			provider:       &operatorv1.CoreProvider{ObjectMeta: metav1.ObjectMeta{Name: "cluster-api", Namespace: "capi-system"}},
+			coreContract:   "v1beta2",
+			targetContract: "v1beta1",
+			expectedErr:    true,
+		},
+		{
+			name:           "infrastructure provider matches the core provider contract",
+			provider:       &operatorv1.InfrastructureProvider{ObjectMeta: metav1.ObjectMeta{Name: "docker", Namespace: "capd-system"}},
+			coreContract:   "v1beta2",
+			targetContract: "v1beta2",
+		},
+		{
+			name:           "infrastructure provider with a contract compatible with the core provider contract",
+			provider:       &operatorv1.InfrastructureProvider{ObjectMeta: metav1.ObjectMeta{Name: "docker", Namespace: "capd-system"}},
+			coreContract:   "v1beta2",
+			targetContract: "v1beta1",
+		},
+		{
+			name:           "infrastructure provider with a contract incompatible with the core provider contract",
+			provider:       &operatorv1.InfrastructureProvider{ObjectMeta: metav1.ObjectMeta{Name: "docker", Namespace: "capd-system"}},
+			coreContract:   "v1beta1",
+			targetContract: "v1beta2",
+			expectedErr:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			core := &operatorv1.CoreProvider{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-api", Namespace: "capi-system"},
+				Status: operatorv1.CoreProviderStatus{
+					ProviderStatus: operatorv1.ProviderStatus{Contract: ptr.To(tc.coreContract)},
+				},
+			}
+
+			if _, ok := tc.provider.(*operatorv1.CoreProvider); ok {
+				tc.provider = core
+			}
+
+			fakeClient := fake.NewClientBuilder().WithScheme(cacheTestScheme()).WithStatusSubresource(core).WithObjects(core).Build()
+			r := &GenericProviderReconciler{Client: fakeClient}
+
+			p := &PhaseReconciler{
+				ctrlClient:         fakeClient,
+				provider:           tc.provider,
+				providerTypeMapper: util.ClusterctlProviderType,
+				providerLister:     r.listProviders,
+				contract:           tc.targetContract,
+			}
+
+			err := p.validateDowngradeContract(context.Background())
+			if tc.expectedErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -27,8 +27,8 @@ import (
 	apijson "k8s.io/apimachinery/pkg/util/json"
 	"k8s.io/client-go/kubernetes/scheme"
 	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
+	"sigs.k8s.io/cluster-api-operator/internal/processor"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/repository"
-	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/yamlprocessor"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -51,13 +51,18 @@ func (p *PhaseReconciler) Fetch(ctx context.Context) (*Result, error) {
 
 	log.V(2).Info("Fetched components file", "size", len(componentsFile), "needsCompression", p.needsCompression)
 
+	templateProcessor, err := processor.New(p.provider.GetSpec().TemplateProcessor)
+	if err != nil {
+		return &Result{}, wrapPhaseError(err, operatorv1.ComponentsFetchErrorReason, operatorv1.ProviderInstalledCondition)
+	}
+
 	// Generate a set of new objects using the clusterctl library. NewComponents() will do the yaml processing,
 	// like ensure all the provider components are in proper namespace, replace variables, etc. See the clusterctl
 	// documentation for more details.
 	p.components, err = repository.NewComponents(repository.ComponentsInput{
 		Provider:     p.providerConfig,
 		ConfigClient: p.configClient,
-		Processor:    yamlprocessor.NewSimpleProcessor(),
+		Processor:    templateProcessor,
 		RawYaml:      componentsFile,
 		Options:      p.options,
 	})
@@ -76,6 +81,11 @@ func (p *PhaseReconciler) Fetch(ctx context.Context) (*Result, error) {
 		return &Result{}, wrapPhaseError(err, operatorv1.ComponentsPatchErrorReason, operatorv1.ProviderInstalledCondition)
 	}
 
+	// Run the kustomization over the provider components if specified.
+	if err := repository.AlterComponents(p.components, applyKustomization(ctx, p.ctrlClient, p.provider)); err != nil {
+		return &Result{}, wrapPhaseError(err, operatorv1.ComponentsKustomizationErrorReason, operatorv1.ProviderInstalledCondition)
+	}
+
 	// Apply image overrides to the provider manifests.
 	if err := repository.AlterComponents(p.components, imageOverrides(p.components.ManifestLabel(), p.overridesClient)); err != nil {
 		return &Result{}, wrapPhaseError(err, operatorv1.ComponentsImageOverrideErrorReason, operatorv1.ProviderInstalledCondition)
@@ -185,11 +195,28 @@ func (p *PhaseReconciler) ReportStatus(ctx context.Context) (*Result, error) {
 	log := ctrl.LoggerFrom(ctx)
 
 	status := p.provider.GetStatus()
+
+	outcome := operatorv1.ProviderHistoryInstalled
+
+	if status.InstalledVersion != nil && *status.InstalledVersion != p.components.Version() {
+		outcome = operatorv1.ProviderHistoryUpgraded
+		if isDowngrade(*status.InstalledVersion, p.components.Version()) {
+			outcome = operatorv1.ProviderHistoryDowngraded
+		}
+	}
+
 	status.Contract = &p.contract
 	installedVersion := p.components.Version()
 	status.InstalledVersion = &installedVersion
 	p.provider.SetStatus(status)
 
+	cacheHash, err := p.appliedCacheHash(ctx)
+	if err != nil {
+		return &Result{}, err
+	}
+
+	p.recordHistory(ctx, installedVersion, outcome, cacheHash, "")
+
 	log.V(2).Info("Reported provider status", "contract", p.contract, "installedVersion", installedVersion)
 
 	return &Result{}, nil
@@ -166,7 +166,7 @@ func (p *PhaseReconciler) secretReader(ctx context.Context, providers ...configc
 			return mr.AddProvider(p.provider.ProviderName(), p.providerTypeMapper(p.provider), fakeURL)
 		}
 
-		if isCustom && p.provider.GetSpec().FetchConfig.OCI != "" {
+		if isCustom && (p.provider.GetSpec().FetchConfig.OCI != "" || p.provider.GetSpec().FetchConfig.HTTP != nil || p.provider.GetSpec().FetchConfig.Git != nil) {
 			return mr.AddProvider(p.provider.ProviderName(), p.providerTypeMapper(p.provider), fakeURL)
 		}
 	}
@@ -46,27 +46,61 @@ func (p *PhaseReconciler) Upgrade(ctx context.Context) (*Result, error) {
 	}
 
 	// Provider needs to be re-installed
-	if *p.provider.GetStatus().InstalledVersion == p.provider.GetSpec().Version {
-		log.V(2).Info("Skipping upgrade, versions match", "version", p.provider.GetSpec().Version)
+	if *p.provider.GetStatus().InstalledVersion == providerVersion(p.provider) {
+		log.V(2).Info("Skipping upgrade, versions match", "version", providerVersion(p.provider))
 
 		return &Result{}, nil
 	}
 
-	log.Info("Version changes detected, updating existing components", "installedVersion", *p.provider.GetStatus().InstalledVersion, "targetVersion", p.provider.GetSpec().Version)
+	// The version resolved by the version policy may have changed since the maintenance window was checked.
+	if result, err := p.WaitForMaintenanceWindow(ctx); !result.IsZero() || err != nil {
+		return result, err
+	}
 
-	provider := p.providerConverter(p.provider)
-	if provider.Version == "" {
-		provider.Version = p.options.Version
+	previousVersion := *p.provider.GetStatus().InstalledVersion
+	downgrade := isDowngradeAllowed(p.provider) && isDowngrade(previousVersion, providerVersion(p.provider))
+
+	if downgrade {
+		if err := p.validateDowngradeContract(ctx); err != nil {
+			return &Result{}, wrapPhaseError(err, operatorv1.IncompatibleDowngradeContractReason, operatorv1.ProviderUpgradedCondition)
+		}
 	}
 
-	if err := p.newClusterClient().ProviderUpgrader().ApplyCustomPlan(ctx, cluster.UpgradeOptions{}, cluster.UpgradeItem{
-		NextVersion: p.provider.GetSpec().Version,
-		Provider:    provider,
-	}); err != nil {
+	log.Info("Version changes detected, updating existing components", "installedVersion", previousVersion, "targetVersion", providerVersion(p.provider))
+
+	if err := p.applyVersionChange(ctx, downgrade); err != nil {
+		p.recordHistory(ctx, providerVersion(p.provider), operatorv1.ProviderHistoryUpgradeFailed, "", err.Error())
+
+		if isUpgradeRollbackEnabled(p.provider) {
+			return p.rollback(ctx, previousVersion, err)
+		}
+
 		return &Result{}, wrapPhaseError(err, operatorv1.ComponentsUpgradeErrorReason, operatorv1.ProviderUpgradedCondition)
 	}
 
-	log.Info("Provider successfully upgraded", "version", p.provider.GetSpec().Version)
+	if isUpgradeRollbackEnabled(p.provider) {
+		status := p.provider.GetStatus()
+		status.PendingUpgrade = &operatorv1.PendingUpgradeStatus{
+			PreviousVersion: previousVersion,
+			Version:         providerVersion(p.provider),
+			StartTime:       metav1.Now(),
+		}
+		p.provider.SetStatus(status)
+	}
+
+	if downgrade {
+		log.Info("Provider successfully downgraded", "version", providerVersion(p.provider))
+		conditions.Set(p.provider, metav1.Condition{
+			Type:    operatorv1.ProviderUpgradedCondition,
+			Status:  metav1.ConditionTrue,
+			Reason:  "ProviderDowngraded",
+			Message: "Provider downgraded successfully",
+		})
+
+		return &Result{}, nil
+	}
+
+	log.Info("Provider successfully upgraded", "version", providerVersion(p.provider))
 	conditions.Set(p.provider, metav1.Condition{
 		Type:    operatorv1.ProviderUpgradedCondition,
 		Status:  metav1.ConditionTrue,
@@ -77,12 +111,29 @@ func (p *PhaseReconciler) Upgrade(ctx context.Context) (*Result, error) {
 	return &Result{}, nil
 }
 
+// applyVersionChange upgrades the provider components using clusterctl library, or re-installs them for a downgrade.
+func (p *PhaseReconciler) applyVersionChange(ctx context.Context, downgrade bool) error {
+	if downgrade {
+		return p.downgrade(ctx)
+	}
+
+	provider := p.providerConverter(p.provider)
+	if provider.Version == "" {
+		provider.Version = p.options.Version
+	}
+
+	return p.newClusterClient().ProviderUpgrader().ApplyCustomPlan(ctx, cluster.UpgradeOptions{}, cluster.UpgradeItem{
+		NextVersion: providerVersion(p.provider),
+		Provider:    provider,
+	})
+}
+
 // Install installs the provider components using clusterctl library.
 func (p *PhaseReconciler) Install(ctx context.Context) (*Result, error) {
 	log := ctrl.LoggerFrom(ctx)
 
 	// Provider was upgraded, nothing to do
-	if p.provider.GetStatus().InstalledVersion != nil && *p.provider.GetStatus().InstalledVersion != p.provider.GetSpec().Version {
+	if p.provider.GetStatus().InstalledVersion != nil && *p.provider.GetStatus().InstalledVersion != providerVersion(p.provider) {
 		log.V(2).Info("Skipping install, provider was upgraded in this reconciliation")
 
 		return &Result{}, nil
@@ -90,7 +141,7 @@ func (p *PhaseReconciler) Install(ctx context.Context) (*Result, error) {
 
 	clusterClient := p.newClusterClient()
 
-	log.Info("Installing provider", "version", p.provider.GetSpec().Version)
+	log.Info("Installing provider", "version", providerVersion(p.provider))
 
 	if err := clusterClient.ProviderComponents().Create(ctx, p.components.Objs()); err != nil {
 		reason := "InstallFailed"
@@ -101,7 +152,7 @@ func (p *PhaseReconciler) Install(ctx context.Context) (*Result, error) {
 		return &Result{}, wrapPhaseError(err, reason, operatorv1.ProviderInstalledCondition)
 	}
 
-	log.Info("Provider successfully installed", "version", p.provider.GetSpec().Version)
+	log.Info("Provider successfully installed", "version", providerVersion(p.provider))
 	conditions.Set(p.provider, metav1.Condition{
 		Type:    operatorv1.ProviderInstalledCondition,
 		Status:  metav1.ConditionTrue,
@@ -129,7 +180,7 @@ func convertProvider(provider operatorv1.GenericProvider) clusterctlv1.Provider
 // Delete deletes the provider components using clusterctl library.
 func (p *PhaseReconciler) Delete(ctx context.Context) (*Result, error) {
 	log := ctrl.LoggerFrom(ctx)
-	log.Info("Deleting provider", "version", p.provider.GetSpec().Version)
+	log.Info("Deleting provider", "version", providerVersion(p.provider))
 
 	clusterClient := p.newClusterClient()
 
@@ -144,7 +195,7 @@ func (p *PhaseReconciler) Delete(ctx context.Context) (*Result, error) {
 		IncludeCRDs:      false,
 	})
 	if err == nil {
-		log.Info("Provider successfully deleted", "version", p.provider.GetSpec().Version)
+		log.Info("Provider successfully deleted", "version", providerVersion(p.provider))
 	}
 
 	return &Result{}, wrapPhaseError(err, operatorv1.OldComponentsDeletionErrorReason, operatorv1.ProviderInstalledCondition)
@@ -65,7 +65,7 @@ func (p *PhaseReconciler) Load(ctx context.Context) (*Result, error) {
 		return &Result{}, wrapPhaseError(err, operatorv1.ComponentsFetchErrorReason, operatorv1.ProviderInstalledCondition)
 	}
 
-	if spec.Version == "" {
+	if providerVersion(p.provider) == "" {
 		// User didn't set the version, so we need to find the latest one from the matching config maps.
 		repoVersions, err := p.repo.GetVersions(ctx)
 		if err != nil {
@@ -87,13 +87,18 @@ func (p *PhaseReconciler) Load(ctx context.Context) (*Result, error) {
 	p.options = repository.ComponentsOptions{
 		TargetNamespace:     p.provider.GetNamespace(),
 		SkipTemplateProcess: false,
-		Version:             spec.Version,
+		Version:             providerVersion(p.provider),
 	}
 
 	if err := p.validateRepoCAPIVersion(ctx); err != nil {
 		return &Result{}, wrapPhaseError(err, operatorv1.CAPIVersionIncompatibilityReason, operatorv1.ProviderInstalledCondition)
 	}
 
+	// Reject provider versions which are incompatible with the installed core provider before installing them.
+	if err := checkProviderContract(ctx, p.provider, p.contract, p.providerTypeMapper, p.providerLister); err != nil {
+		return &Result{}, err
+	}
+
 	return &Result{}, nil
 }
 
@@ -162,7 +167,7 @@ func (p *PhaseReconciler) configmapRepository(ctx context.Context, labelSelector
 			continue
 		}
 
-		components, err := getComponentsData(cm)
+		components, err := GetComponentsData(cm)
 		if err != nil {
 			return nil, err
 		}
@@ -191,8 +196,8 @@ func fetchAdditionalManifests(ctx context.Context, cl client.Client, provider ge
 	return cm.Data[operatorv1.AdditionalManifestsConfigMapKey], nil
 }
 
-// getComponentsData returns components data based on if it's compressed or not.
-func getComponentsData(cm corev1.ConfigMap) (string, error) {
+// GetComponentsData returns the components data of the ConfigMap, based on if it's compressed or not.
+func GetComponentsData(cm corev1.ConfigMap) (string, error) {
 	// Data is not compressed, return it immediately.
 	if cm.GetAnnotations()[operatorv1.CompressedAnnotation] != "true" {
 		components, ok := cm.Data[operatorv1.ComponentsConfigMapKey]
@@ -0,0 +1,224 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	utilyaml "sigs.k8s.io/cluster-api/util/yaml"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
+)
+
+const (
+	// previewManifestsKey is the key of the rendered manifests in the preview Secret.
+	previewManifestsKey = "manifests"
+
+	// previewDiffKey is the key of the diff against the live components in the preview Secret.
+	previewDiffKey = "diff"
+)
+
+// Preview holds the installation or the upgrade of the provider until the changes preview is approved, if enabled.
+// The rendered manifests and their server-side dry-run diff against the live components are stored in the preview
+// Secret, as they hold the substituted variables and the data of the live Secrets, and the reconciliation stops until the approved preview annotation is set to the preview hash.
+func (p *PhaseReconciler) Preview(ctx context.Context) (*Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	if !p.provider.GetSpec().PreviewChanges {
+		return &Result{}, p.clearPreview(ctx)
+	}
+
+	objs := addNamespaceIfMissing(p.components.Objs(), p.provider.GetNamespace())
+
+	manifests, err := utilyaml.FromUnstructured(objs)
+	if err != nil {
+		return &Result{}, wrapPhaseError(err, operatorv1.PreviewErrorReason, operatorv1.PreviewApprovedCondition)
+	}
+
+	hash := fmt.Sprintf("%x", sha256.Sum256(manifests))
+
+	if p.provider.GetAnnotations()[operatorv1.ApprovedPreviewAnnotation] == hash {
+		log.Info("Changes preview is approved", "hash", hash)
+		conditions.Set(p.provider, metav1.Condition{
+			Type:    operatorv1.PreviewApprovedCondition,
+			Status:  metav1.ConditionTrue,
+			Reason:  operatorv1.PreviewApprovedReason,
+			Message: fmt.Sprintf("Preview %s is approved", hash),
+		})
+
+		return &Result{}, nil
+	}
+
+	diffs, err := ComponentsDiff(ctx, p.ctrlClient, p.provider, objs)
+	if err != nil {
+		return &Result{}, wrapPhaseError(err, operatorv1.PreviewErrorReason, operatorv1.PreviewApprovedCondition)
+	}
+
+	if len(diffs) == 0 {
+		log.V(2).Info("Rendered manifests don't change the installed components, skipping preview", "hash", hash)
+		conditions.Set(p.provider, metav1.Condition{
+			Type:    operatorv1.PreviewApprovedCondition,
+			Status:  metav1.ConditionTrue,
+			Reason:  operatorv1.NoChangesToPreviewReason,
+			Message: "The rendered manifests don't change the installed components",
+		})
+
+		return &Result{}, nil
+	}
+
+	if err := p.storePreview(ctx, manifests, diffs); err != nil {
+		return &Result{}, wrapPhaseError(err, operatorv1.PreviewErrorReason, operatorv1.PreviewApprovedCondition)
+	}
+
+	preview := &operatorv1.PreviewStatus{
+		Hash:       hash,
+		Version:    providerVersion(p.provider),
+		SecretName: previewSecretName(p.provider),
+	}
+
+	for _, diff := range diffs {
+		switch {
+		case diff.Created:
+			preview.CreatedComponents++
+		case diff.Deleted:
+			preview.DeletedComponents++
+		default:
+			preview.ChangedComponents++
+		}
+	}
+
+	status := p.provider.GetStatus()
+	status.Preview = preview
+	p.provider.SetStatus(status)
+
+	log.Info("Holding changes until the preview is approved", "hash", hash, "secret", preview.SecretName)
+	conditions.Set(p.provider, metav1.Condition{
+		Type:   operatorv1.PreviewApprovedCondition,
+		Status: metav1.ConditionFalse,
+		Reason: operatorv1.WaitingForPreviewApprovalReason,
+		Message: fmt.Sprintf("Changes to version %s create %d, change %d and delete %d components, see the %s Secret. "+
+			"Set the %s annotation to %s to approve them",
+			preview.Version, preview.CreatedComponents, preview.ChangedComponents, preview.DeletedComponents, preview.SecretName,
+			operatorv1.ApprovedPreviewAnnotation, hash),
+	})
+
+	return &Result{Completed: true}, nil
+}
+
+// storePreview stores the rendered manifests and the diff in the preview Secret, compressed if they exceed
+// the Secret size limit.
+func (p *PhaseReconciler) storePreview(ctx context.Context, manifests []byte, diffs []ComponentDiff) error {
+	kinds, _, err := scheme.Scheme.ObjectKinds(&corev1.Secret{})
+	if err != nil || len(kinds) == 0 {
+		return fmt.Errorf("cannot fetch kind of the Secret resource: %w", err)
+	}
+
+	secret := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       kinds[0].Kind,
+			APIVersion: kinds[0].GroupVersion().String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      previewSecretName(p.provider),
+			Namespace: p.provider.GetNamespace(),
+		},
+		Type: corev1.SecretTypeOpaque,
+	}
+
+	gvk := p.provider.GetObjectKind().GroupVersionKind()
+
+	secret.SetOwnerReferences([]metav1.OwnerReference{
+		{
+			APIVersion: gvk.GroupVersion().String(),
+			Kind:       gvk.Kind,
+			Name:       p.provider.GetName(),
+			UID:        p.provider.GetUID(),
+		},
+	})
+
+	var diff strings.Builder
+	for _, d := range diffs {
+		diff.WriteString(d.Diff)
+	}
+
+	if !needToCompress(manifests, []byte(diff.String())) {
+		secret.Data = map[string][]byte{
+			previewManifestsKey: manifests,
+			previewDiffKey:      []byte(diff.String()),
+		}
+	} else {
+		secret.Data = map[string][]byte{}
+
+		for key, data := range map[string][]byte{previewManifestsKey: manifests, previewDiffKey: []byte(diff.String())} {
+			var buf bytes.Buffer
+			if err := compressData(&buf, data); err != nil {
+				return err
+			}
+
+			secret.Data[key] = buf.Bytes()
+		}
+
+		secret.Annotations = map[string]string{operatorv1.CompressedAnnotation: operatorv1.TrueValue}
+	}
+
+	if err := p.ctrlClient.Patch(ctx, secret, client.Apply, client.ForceOwnership, client.FieldOwner(cacheOwner)); err != nil {
+		return fmt.Errorf("failed to store the preview: %w", err)
+	}
+
+	return nil
+}
+
+// clearPreview removes the preview status and Secret of the provider, once the changes preview is disabled.
+func (p *PhaseReconciler) clearPreview(ctx context.Context) error {
+	conditions.Delete(p.provider, operatorv1.PreviewApprovedCondition)
+
+	status := p.provider.GetStatus()
+	if status.Preview == nil {
+		return nil
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      status.Preview.SecretName,
+			Namespace: p.provider.GetNamespace(),
+		},
+	}
+
+	if err := client.IgnoreNotFound(p.ctrlClient.Delete(ctx, secret)); err != nil {
+		return wrapPhaseError(fmt.Errorf("failed to delete the preview: %w", err), operatorv1.PreviewErrorReason, operatorv1.PreviewApprovedCondition)
+	}
+
+	status.Preview = nil
+	p.provider.SetStatus(status)
+
+	return nil
+}
+
+// previewSecretName returns the name of the Secret storing the changes preview of the provider.
+func previewSecretName(provider operatorv1.GenericProvider) string {
+	return fmt.Sprintf("%s-%s-preview", provider.GetType(), provider.GetName())
+}
@@ -0,0 +1,230 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	configclient "sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/repository"
+	yaml "sigs.k8s.io/cluster-api/cmd/clusterctl/client/yamlprocessor"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
+)
+
+func TestPreview(t *testing.T) {
+	components := `apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: capi-manager
+  namespace: capi-system
+`
+
+	testCases := []struct {
+		name              string
+		previewChanges    bool
+		installed         bool
+		outdated          bool
+		removed           bool
+		approved          bool
+		expectedCompleted bool
+		expectedCondition *metav1.Condition
+		expectedCreated   int32
+		expectedChanged   int32
+		expectedDeleted   int32
+		expectedDiff      string
+	}{
+		{
+			name: "preview disabled",
+		},
+		{
+			name:              "new components wait for approval",
+			previewChanges:    true,
+			expectedCompleted: true,
+			expectedCondition: &metav1.Condition{
+				Status: metav1.ConditionFalse,
+				Reason: operatorv1.WaitingForPreviewApprovalReason,
+			},
+			expectedCreated: 2,
+			expectedDiff:    "+++ ServiceAccount capi-system/capi-manager (rendered)",
+		},
+		{
+			name:              "changed components wait for approval",
+			previewChanges:    true,
+			installed:         true,
+			outdated:          true,
+			expectedCompleted: true,
+			expectedCondition: &metav1.Condition{
+				Status: metav1.ConditionFalse,
+				Reason: operatorv1.WaitingForPreviewApprovalReason,
+			},
+			expectedChanged: 1,
+			expectedDiff:    "+++ ServiceAccount capi-system/capi-manager (rendered)",
+		},
+		{
+			name:              "removed components wait for approval",
+			previewChanges:    true,
+			installed:         true,
+			removed:           true,
+			expectedCompleted: true,
+			expectedCondition: &metav1.Condition{
+				Status: metav1.ConditionFalse,
+				Reason: operatorv1.WaitingForPreviewApprovalReason,
+			},
+			expectedDeleted: 1,
+			expectedDiff:    "--- Role capi-system/capi-leader-election (live)",
+		},
+		{
+			name:           "approved preview",
+			previewChanges: true,
+			approved:       true,
+			expectedCondition: &metav1.Condition{
+				Status: metav1.ConditionTrue,
+				Reason: operatorv1.PreviewApprovedReason,
+			},
+		},
+		{
+			name:           "no changes",
+			previewChanges: true,
+			installed:      true,
+			expectedCondition: &metav1.Condition{
+				Status: metav1.ConditionTrue,
+				Reason: operatorv1.NoChangesToPreviewReason,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+
+			provider := &operatorv1.CoreProvider{
+				TypeMeta:   metav1.TypeMeta{Kind: "CoreProvider", APIVersion: operatorv1.GroupVersion.String()},
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-api", Namespace: "capi-system"},
+				Spec: operatorv1.CoreProviderSpec{ProviderSpec: operatorv1.ProviderSpec{
+					Version:        "v1.9.3",
+					PreviewChanges: tc.previewChanges,
+				}},
+			}
+
+			configClient, err := configclient.New(ctx, "", configclient.InjectReader(configclient.NewMemoryReader()))
+			g.Expect(err).NotTo(HaveOccurred())
+
+			providerConfig := configclient.NewProvider("cluster-api", "", clusterctlv1.CoreProviderType)
+
+			c, err := repository.NewComponents(repository.ComponentsInput{
+				Provider:     providerConfig,
+				ConfigClient: configClient,
+				Processor:    yaml.NewSimpleProcessor(),
+				RawYaml:      []byte(components),
+				Options:      repository.ComponentsOptions{Version: "v1.9.3", TargetNamespace: "capi-system"},
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			cl := fake.NewClientBuilder().WithScheme(setupScheme()).Build()
+
+			if tc.installed {
+				installed := []unstructured.Unstructured{}
+				for _, obj := range addNamespaceIfMissing(c.Objs(), "capi-system") {
+					obj := obj.DeepCopy()
+					if tc.outdated && obj.GetKind() == "ServiceAccount" {
+						obj.SetLabels(map[string]string{"version": "v1.9.2"})
+					}
+
+					installed = append(installed, *obj)
+				}
+
+				if tc.removed {
+					role := unstructured.Unstructured{}
+					role.SetAPIVersion("rbac.authorization.k8s.io/v1")
+					role.SetKind("Role")
+					role.SetName("capi-leader-election")
+					role.SetNamespace("capi-system")
+					role.SetLabels(installed[0].GetLabels())
+
+					installed = append(installed, role)
+				}
+
+				g.Expect(applyManifests(ctx, cl, installed)).To(Succeed())
+			}
+
+			p := &PhaseReconciler{
+				ctrlClient: cl,
+				provider:   provider,
+				components: c,
+			}
+
+			if tc.approved {
+				_, err := p.Preview(ctx)
+				g.Expect(err).NotTo(HaveOccurred())
+
+				provider.SetAnnotations(map[string]string{operatorv1.ApprovedPreviewAnnotation: provider.Status.Preview.Hash})
+			}
+
+			res, err := p.Preview(ctx)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(res.Completed).To(Equal(tc.expectedCompleted))
+
+			condition := conditions.Get(provider, operatorv1.PreviewApprovedCondition)
+			if tc.expectedCondition == nil {
+				g.Expect(condition).To(BeNil())
+				g.Expect(provider.Status.Preview).To(BeNil())
+
+				return
+			}
+
+			g.Expect(condition).NotTo(BeNil())
+			g.Expect(condition.Status).To(Equal(tc.expectedCondition.Status))
+			g.Expect(condition.Reason).To(Equal(tc.expectedCondition.Reason))
+
+			if !tc.expectedCompleted {
+				return
+			}
+
+			g.Expect(provider.Status.Preview).NotTo(BeNil())
+			g.Expect(provider.Status.Preview.CreatedComponents).To(Equal(tc.expectedCreated))
+			g.Expect(provider.Status.Preview.ChangedComponents).To(Equal(tc.expectedChanged))
+			g.Expect(provider.Status.Preview.DeletedComponents).To(Equal(tc.expectedDeleted))
+			g.Expect(condition.Message).To(ContainSubstring(provider.Status.Preview.Hash))
+
+			secret := &corev1.Secret{}
+			g.Expect(cl.Get(ctx, client.ObjectKey{Name: provider.Status.Preview.SecretName, Namespace: "capi-system"}, secret)).To(Succeed())
+			g.Expect(string(secret.Data[previewManifestsKey])).To(ContainSubstring("name: capi-manager"))
+			g.Expect(string(secret.Data[previewDiffKey])).To(ContainSubstring(tc.expectedDiff))
+
+			// Disabling the preview removes it.
+			provider.Spec.PreviewChanges = false
+
+			_, err = p.Preview(ctx)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(provider.Status.Preview).To(BeNil())
+			g.Expect(conditions.Get(provider, operatorv1.PreviewApprovedCondition)).To(BeNil())
+			g.Expect(apierrors.IsNotFound(cl.Get(ctx, client.ObjectKeyFromObject(secret), secret))).To(BeTrue())
+		})
+	}
+}
@@ -0,0 +1,249 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// defaultUpgradeRollbackTimeout is the default time the provider deployments have to become Available after an upgrade.
+	defaultUpgradeRollbackTimeout = 10 * time.Minute
+
+	// upgradeVerificationInterval is the interval between checks of the upgraded provider deployments.
+	upgradeVerificationInterval = 30 * time.Second
+)
+
+// VerifyUpgrade checks that the provider deployments become Available after an upgrade, and rolls the provider back
+// to the previously installed version if they don't within the configured timeout.
+func (p *PhaseReconciler) VerifyUpgrade(ctx context.Context) (*Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	// The version which was rolled back is not downloaded, cached or installed again until the provider spec changes.
+	if isUpgradeRollbackEnabled(p.provider) && isUpgradeRolledBack(p.provider) {
+		log.V(2).Info("Skipping reconciliation, upgrade was rolled back", "version", providerVersion(p.provider))
+
+		return &Result{Completed: true}, nil
+	}
+
+	status := p.provider.GetStatus()
+	pendingUpgrade := status.PendingUpgrade
+
+	if pendingUpgrade == nil {
+		return &Result{}, nil
+	}
+
+	// Stop tracking the upgrade if the rollback was disabled or a different version was requested.
+	if !isUpgradeRollbackEnabled(p.provider) || pendingUpgrade.Version != providerVersion(p.provider) {
+		log.V(2).Info("Skipping upgrade verification, provider spec has changed", "version", pendingUpgrade.Version)
+
+		status.PendingUpgrade = nil
+		p.provider.SetStatus(status)
+
+		return &Result{}, nil
+	}
+
+	available, err := p.deploymentsAvailable(ctx)
+	if err != nil {
+		return &Result{}, wrapPhaseError(err, operatorv1.ComponentsUpgradeErrorReason, operatorv1.ProviderUpgradedCondition)
+	}
+
+	if available {
+		log.Info("Upgraded provider deployments are available", "version", pendingUpgrade.Version)
+
+		status.PendingUpgrade = nil
+		p.provider.SetStatus(status)
+
+		return &Result{}, nil
+	}
+
+	timeout := upgradeRollbackTimeout(p.provider)
+
+	if elapsed := time.Since(pendingUpgrade.StartTime.Time); elapsed < timeout {
+		log.Info("Waiting for upgraded provider deployments to become available", "version", pendingUpgrade.Version, "elapsed", elapsed)
+
+		return &Result{RequeueAfter: min(timeout-elapsed, upgradeVerificationInterval)}, nil
+	}
+
+	return p.rollback(ctx, pendingUpgrade.PreviousVersion, fmt.Errorf("provider deployments did not become available within %s", timeout))
+}
+
+// rollback re-applies the cached manifests of the previously installed version, deletes the cache of the failed
+// version, and records the rollback in status. Objects which are only part of the failed version manifests are
+// not deleted. The reconciliation is completed, and the failed version is not reconciled again until the provider
+// spec changes.
+func (p *PhaseReconciler) rollback(ctx context.Context, previousVersion string, upgradeErr error) (*Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	version := providerVersion(p.provider)
+
+	log.Info("Rolling back provider upgrade", "version", version, "previousVersion", previousVersion, "reason", upgradeErr.Error())
+
+	secret := &corev1.Secret{}
+	key := client.ObjectKey{Name: providerCacheNameForVersion(p.provider, previousVersion), Namespace: p.provider.GetNamespace()}
+
+	if err := p.ctrlClient.Get(ctx, key, secret); err != nil {
+		err = fmt.Errorf("upgrade to %s failed: %w, cached manifests for %s are not available: %w", version, upgradeErr, previousVersion, err)
+
+		return &Result{}, wrapPhaseError(err, operatorv1.ComponentsRollbackErrorReason, operatorv1.ProviderUpgradedCondition)
+	}
+
+	compressed := secret.GetAnnotations()[operatorv1.CompressedAnnotation] == operatorv1.TrueValue
+
+	if err := p.applyManifestsFromData(ctx, secret.Data, compressed); err != nil {
+		err = fmt.Errorf("upgrade to %s failed: %w, rollback to %s failed: %w", version, upgradeErr, previousVersion, err)
+
+		return &Result{}, wrapPhaseError(err, operatorv1.ComponentsRollbackErrorReason, operatorv1.ProviderUpgradedCondition)
+	}
+
+	failedCache := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: ProviderCacheName(p.provider), Namespace: p.provider.GetNamespace()},
+	}
+
+	if err := p.ctrlClient.Delete(ctx, failedCache); client.IgnoreNotFound(err) != nil {
+		err = fmt.Errorf("upgrade to %s was rolled back to %s, but its cache can't be deleted: %w", version, previousVersion, err)
+
+		return &Result{}, wrapPhaseError(err, operatorv1.ComponentsRollbackErrorReason, operatorv1.ProviderUpgradedCondition)
+	}
+
+	status := p.provider.GetStatus()
+	status.InstalledVersion = &previousVersion
+	status.PendingUpgrade = nil
+	status.LastRollback = &operatorv1.RollbackStatus{
+		FromVersion:        version,
+		ToVersion:          previousVersion,
+		Message:            upgradeErr.Error(),
+		ObservedGeneration: p.provider.GetGeneration(),
+		Time:               metav1.Now(),
+	}
+	p.provider.SetStatus(status)
+
+	p.recordHistory(ctx, previousVersion, operatorv1.ProviderHistoryRolledBack, "", upgradeErr.Error())
+
+	// Remove the applied hash, so the cached manifests of the failed version are not applied on the next reconcile.
+	annotations := p.provider.GetAnnotations()
+	delete(annotations, AppliedSpecHashAnnotation)
+	p.provider.SetAnnotations(annotations)
+
+	log.Info("Provider upgrade rolled back", "version", version, "previousVersion", previousVersion)
+	conditions.Set(p.provider, metav1.Condition{
+		Type:    operatorv1.ProviderUpgradedCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  operatorv1.ComponentsUpgradeRolledBackReason,
+		Message: fmt.Sprintf("Upgrade to %s was rolled back to %s: %s", version, previousVersion, upgradeErr.Error()),
+	})
+
+	return &Result{Completed: true}, nil
+}
+
+// isUpgradeRolledBack returns true if the upgrade to the current provider spec was already rolled back.
+func isUpgradeRolledBack(provider operatorv1.GenericProvider) bool {
+	lastRollback := provider.GetStatus().LastRollback
+
+	return lastRollback != nil &&
+		lastRollback.FromVersion == providerVersion(provider) &&
+		lastRollback.ObservedGeneration == provider.GetGeneration()
+}
+
+// deploymentsAvailable checks that all provider deployments are rolled out and Available.
+func (p *PhaseReconciler) deploymentsAvailable(ctx context.Context) (bool, error) {
+	deploymentList := &appsv1.DeploymentList{}
+	if err := p.ctrlClient.List(ctx, deploymentList, client.InNamespace(p.provider.GetNamespace()), client.HasLabels{clusterv1.ProviderNameLabel}); err != nil {
+		return false, fmt.Errorf("failed to list provider deployments: %w", err)
+	}
+
+	found := false
+
+	for i := range deploymentList.Items {
+		deployment := &deploymentList.Items[i]
+
+		if !isOwnedBy(deployment, p.provider) {
+			continue
+		}
+
+		found = true
+
+		if !isDeploymentAvailable(deployment) {
+			return false, nil
+		}
+	}
+
+	return found, nil
+}
+
+// isOwnedBy returns true if the object has an owner reference to the provider.
+func isOwnedBy(obj client.Object, provider operatorv1.GenericProvider) bool {
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.UID == provider.GetUID() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isDeploymentAvailable returns true if the latest deployment generation is fully rolled out and Available.
+func isDeploymentAvailable(deployment *appsv1.Deployment) bool {
+	if deployment.Status.ObservedGeneration < deployment.Generation {
+		return false
+	}
+
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+
+	if deployment.Status.UpdatedReplicas < replicas {
+		return false
+	}
+
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Type == appsv1.DeploymentAvailable {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+
+	return false
+}
+
+// isUpgradeRollbackEnabled returns true if failed upgrades of the provider have to be rolled back.
+func isUpgradeRollbackEnabled(provider operatorv1.GenericProvider) bool {
+	rollback := provider.GetSpec().UpgradeRollback
+
+	return rollback != nil && rollback.Enabled
+}
+
+// upgradeRollbackTimeout returns the time the provider deployments have to become Available after an upgrade.
+func upgradeRollbackTimeout(provider operatorv1.GenericProvider) time.Duration {
+	rollback := provider.GetSpec().UpgradeRollback
+	if rollback == nil || rollback.Timeout == nil {
+		return defaultUpgradeRollbackTimeout
+	}
+
+	return rollback.Timeout.Duration
+}
@@ -0,0 +1,353 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
+)
+
+func rollbackTestScheme() *runtime.Scheme {
+	s := cacheTestScheme()
+	utilruntime.Must(appsv1.AddToScheme(s))
+
+	return s
+}
+
+func rollbackTestProvider(rollback *operatorv1.UpgradeRollbackSpec, pendingUpgrade *operatorv1.PendingUpgradeStatus) *operatorv1.CoreProvider {
+	return &operatorv1.CoreProvider{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "cluster-api",
+			Namespace:  "capi-system",
+			UID:        "provider-uid",
+			Generation: 2,
+			Annotations: map[string]string{
+				AppliedSpecHashAnnotation: "hash",
+			},
+		},
+		Spec: operatorv1.CoreProviderSpec{
+			ProviderSpec: operatorv1.ProviderSpec{
+				Version:         "v1.12.0",
+				UpgradeRollback: rollback,
+			},
+		},
+		Status: operatorv1.CoreProviderStatus{
+			ProviderStatus: operatorv1.ProviderStatus{
+				InstalledVersion: ptr.To("v1.12.0"),
+				PendingUpgrade:   pendingUpgrade,
+			},
+		},
+	}
+}
+
+func rollbackTestDeployment(available bool) *appsv1.Deployment {
+	status := corev1.ConditionFalse
+	if available {
+		status = corev1.ConditionTrue
+	}
+
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "capi-controller-manager",
+			Namespace:  "capi-system",
+			Generation: 1,
+			Labels: map[string]string{
+				clusterv1.ProviderNameLabel: "cluster-api",
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				{Kind: "CoreProvider", Name: "cluster-api", UID: "provider-uid"},
+			},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To[int32](1),
+		},
+		Status: appsv1.DeploymentStatus{
+			ObservedGeneration: 1,
+			UpdatedReplicas:    1,
+			Conditions: []appsv1.DeploymentCondition{
+				{Type: appsv1.DeploymentAvailable, Status: status},
+			},
+		},
+	}
+}
+
+func rollbackTestCache() *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "core-cluster-api-v1.11.0-cache",
+			Namespace: "capi-system",
+		},
+		Data: map[string][]byte{
+			"cache": []byte(`[{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"previous","namespace":"capi-system"},"data":{"version":"v1.11.0"}}]`),
+		},
+	}
+}
+
+func rollbackTestFailedCache() *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "core-cluster-api-v1.12.0-cache",
+			Namespace: "capi-system",
+		},
+	}
+}
+
+func TestVerifyUpgrade(t *testing.T) {
+	enabled := &operatorv1.UpgradeRollbackSpec{Enabled: true, Timeout: &metav1.Duration{Duration: time.Minute}}
+
+	testCases := []struct {
+		name                   string
+		rollback               *operatorv1.UpgradeRollbackSpec
+		pendingUpgrade         *operatorv1.PendingUpgradeStatus
+		lastRollback           *operatorv1.RollbackStatus
+		objects                []client.Object
+		expectedResult         *Result
+		expectedErr            bool
+		expectPendingUpgrade   bool
+		expectedInstalled      string
+		expectRollback         bool
+		expectRolledBackObject bool
+	}{
+		{
+			name:              "no pending upgrade",
+			rollback:          enabled,
+			expectedResult:    &Result{},
+			expectedInstalled: "v1.12.0",
+		},
+		{
+			name:     "rollback disabled",
+			rollback: &operatorv1.UpgradeRollbackSpec{Enabled: false},
+			pendingUpgrade: &operatorv1.PendingUpgradeStatus{
+				PreviousVersion: "v1.11.0", Version: "v1.12.0", StartTime: metav1.NewTime(time.Now().Add(-time.Hour)),
+			},
+			expectedResult:    &Result{},
+			expectedInstalled: "v1.12.0",
+		},
+		{
+			name:     "different version requested",
+			rollback: enabled,
+			pendingUpgrade: &operatorv1.PendingUpgradeStatus{
+				PreviousVersion: "v1.10.0", Version: "v1.11.0", StartTime: metav1.NewTime(time.Now().Add(-time.Hour)),
+			},
+			expectedResult:    &Result{},
+			expectedInstalled: "v1.12.0",
+		},
+		{
+			name:     "deployments available",
+			rollback: enabled,
+			pendingUpgrade: &operatorv1.PendingUpgradeStatus{
+				PreviousVersion: "v1.11.0", Version: "v1.12.0", StartTime: metav1.NewTime(time.Now().Add(-time.Hour)),
+			},
+			objects:           []client.Object{rollbackTestDeployment(true)},
+			expectedResult:    &Result{},
+			expectedInstalled: "v1.12.0",
+		},
+		{
+			name:     "waiting for deployments",
+			rollback: enabled,
+			pendingUpgrade: &operatorv1.PendingUpgradeStatus{
+				PreviousVersion: "v1.11.0", Version: "v1.12.0", StartTime: metav1.Now(),
+			},
+			objects:              []client.Object{rollbackTestDeployment(false)},
+			expectedResult:       &Result{RequeueAfter: upgradeVerificationInterval},
+			expectPendingUpgrade: true,
+			expectedInstalled:    "v1.12.0",
+		},
+		{
+			name:     "timeout elapsed",
+			rollback: enabled,
+			pendingUpgrade: &operatorv1.PendingUpgradeStatus{
+				PreviousVersion: "v1.11.0", Version: "v1.12.0", StartTime: metav1.NewTime(time.Now().Add(-time.Hour)),
+			},
+			objects:                []client.Object{rollbackTestDeployment(false), rollbackTestCache(), rollbackTestFailedCache()},
+			expectedResult:         &Result{Completed: true},
+			expectedInstalled:      "v1.11.0",
+			expectRollback:         true,
+			expectRolledBackObject: true,
+		},
+		{
+			name:     "timeout elapsed without previous cache",
+			rollback: enabled,
+			pendingUpgrade: &operatorv1.PendingUpgradeStatus{
+				PreviousVersion: "v1.11.0", Version: "v1.12.0", StartTime: metav1.NewTime(time.Now().Add(-time.Hour)),
+			},
+			objects:              []client.Object{rollbackTestDeployment(false)},
+			expectedResult:       &Result{},
+			expectedErr:          true,
+			expectPendingUpgrade: true,
+			expectedInstalled:    "v1.12.0",
+		},
+		{
+			name:     "upgrade rolled back",
+			rollback: enabled,
+			lastRollback: &operatorv1.RollbackStatus{
+				FromVersion: "v1.12.0", ToVersion: "v1.11.0", ObservedGeneration: 2,
+			},
+			expectedResult:    &Result{Completed: true},
+			expectedInstalled: "v1.12.0",
+		},
+		{
+			name:     "spec changed after the rollback",
+			rollback: enabled,
+			lastRollback: &operatorv1.RollbackStatus{
+				FromVersion: "v1.12.0", ToVersion: "v1.11.0", ObservedGeneration: 1,
+			},
+			expectedResult:    &Result{},
+			expectedInstalled: "v1.12.0",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			provider := rollbackTestProvider(tc.rollback, tc.pendingUpgrade)
+			provider.Status.LastRollback = tc.lastRollback
+			fakeclient := fake.NewClientBuilder().WithScheme(rollbackTestScheme()).WithObjects(tc.objects...).Build()
+
+			p := &PhaseReconciler{
+				ctrlClient: fakeclient,
+				provider:   provider,
+			}
+
+			result, err := p.VerifyUpgrade(context.Background())
+			if tc.expectedErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+
+			g.Expect(result).To(Equal(tc.expectedResult))
+			g.Expect(provider.Status.PendingUpgrade != nil).To(Equal(tc.expectPendingUpgrade))
+			g.Expect(provider.Status.InstalledVersion).To(HaveValue(Equal(tc.expectedInstalled)))
+
+			if !tc.expectRollback {
+				g.Expect(provider.Status.LastRollback).To(Equal(tc.lastRollback))
+				return
+			}
+
+			g.Expect(provider.Status.LastRollback).NotTo(BeNil())
+			g.Expect(provider.Status.LastRollback.FromVersion).To(Equal("v1.12.0"))
+			g.Expect(provider.Status.LastRollback.ToVersion).To(Equal("v1.11.0"))
+			g.Expect(provider.Status.LastRollback.ObservedGeneration).To(Equal(int64(2)))
+			g.Expect(provider.GetAnnotations()).NotTo(HaveKey(AppliedSpecHashAnnotation))
+			g.Expect(isUpgradeRolledBack(provider)).To(BeTrue())
+
+			// The cache of the failed version is deleted, so it is never applied.
+			err = fakeclient.Get(context.Background(), client.ObjectKeyFromObject(rollbackTestFailedCache()), &corev1.Secret{})
+			g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+			condition := conditions.Get(provider, operatorv1.ProviderUpgradedCondition)
+			g.Expect(condition).NotTo(BeNil())
+			g.Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			g.Expect(condition.Reason).To(Equal(operatorv1.ComponentsUpgradeRolledBackReason))
+
+			if tc.expectRolledBackObject {
+				cm := &corev1.ConfigMap{}
+				g.Expect(fakeclient.Get(context.Background(), client.ObjectKey{Name: "previous", Namespace: "capi-system"}, cm)).To(Succeed())
+				g.Expect(cm.Data).To(HaveKeyWithValue("version", "v1.11.0"))
+			}
+		})
+	}
+}
+
+func TestRollbackRecordsUpgradeError(t *testing.T) {
+	g := NewWithT(t)
+
+	provider := rollbackTestProvider(&operatorv1.UpgradeRollbackSpec{Enabled: true}, nil)
+	fakeclient := fake.NewClientBuilder().WithScheme(rollbackTestScheme()).WithObjects(rollbackTestCache()).Build()
+
+	p := &PhaseReconciler{
+		ctrlClient: fakeclient,
+		provider:   provider,
+	}
+
+	result, err := p.rollback(context.Background(), "v1.11.0", errors.New("upgrade failed"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.Completed).To(BeTrue())
+	g.Expect(provider.Status.InstalledVersion).To(HaveValue(Equal("v1.11.0")))
+	g.Expect(provider.Status.LastRollback.Message).To(Equal("upgrade failed"))
+	g.Expect(provider.Status.History).To(HaveLen(1))
+	g.Expect(provider.Status.History[0].Version).To(Equal("v1.11.0"))
+	g.Expect(provider.Status.History[0].Outcome).To(Equal(operatorv1.ProviderHistoryRolledBack))
+}
+
+func TestIsDeploymentAvailable(t *testing.T) {
+	testCases := []struct {
+		name     string
+		mutate   func(*appsv1.Deployment)
+		expected bool
+	}{
+		{
+			name:     "available",
+			mutate:   func(*appsv1.Deployment) {},
+			expected: true,
+		},
+		{
+			name: "not available",
+			mutate: func(d *appsv1.Deployment) {
+				d.Status.Conditions[0].Status = corev1.ConditionFalse
+			},
+		},
+		{
+			name: "generation not observed",
+			mutate: func(d *appsv1.Deployment) {
+				d.Generation = 2
+			},
+		},
+		{
+			name: "replicas not updated",
+			mutate: func(d *appsv1.Deployment) {
+				d.Spec.Replicas = ptr.To[int32](2)
+			},
+		},
+		{
+			name: "missing available condition",
+			mutate: func(d *appsv1.Deployment) {
+				d.Status.Conditions = nil
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			deployment := rollbackTestDeployment(true)
+			tc.mutate(deployment)
+
+			g.Expect(isDeploymentAvailable(deployment)).To(Equal(tc.expected))
+		})
+	}
+}
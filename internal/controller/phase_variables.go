@@ -0,0 +1,71 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
+	"sigs.k8s.io/cluster-api-operator/internal/processor"
+)
+
+// VerifyVariables checks that all the variables required by the provider components are set in the provider
+// configuration secret, before the components are processed. The missing variables are listed by name in the
+// VariablesResolved condition.
+func (p *PhaseReconciler) VerifyVariables(ctx context.Context) (*Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	componentsFile, err := p.repo.GetFile(ctx, p.options.Version, p.repo.ComponentsPath())
+	if err != nil {
+		err = fmt.Errorf("failed to read %q from provider's repository %q: %w", p.repo.ComponentsPath(), p.providerConfig.ManifestLabel(), err)
+
+		return &Result{}, wrapPhaseError(err, operatorv1.ComponentsFetchErrorReason, operatorv1.ProviderInstalledCondition)
+	}
+
+	templateProcessor, err := processor.New(p.provider.GetSpec().TemplateProcessor)
+	if err != nil {
+		return &Result{}, wrapPhaseError(err, operatorv1.ComponentsFetchErrorReason, operatorv1.ProviderInstalledCondition)
+	}
+
+	missing, err := processor.MissingVariables(templateProcessor, componentsFile, p.configClient.Variables().Get)
+	if err != nil {
+		err = fmt.Errorf("failed to parse the variables of the components: %w", err)
+
+		return &Result{}, wrapPhaseError(err, operatorv1.ComponentsFetchErrorReason, operatorv1.ProviderInstalledCondition)
+	}
+
+	if len(missing) > 0 {
+		log.Info("Provider components require variables which are not set", "variables", missing)
+
+		return &Result{}, wrapPhaseError(&processor.MissingVariablesError{Missing: missing},
+			operatorv1.MissingVariablesReason, operatorv1.VariablesResolvedCondition)
+	}
+
+	conditions.Set(p.provider, metav1.Condition{
+		Type:    operatorv1.VariablesResolvedCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  operatorv1.VariablesResolvedReason,
+		Message: "All the variables required by the provider components are set",
+	})
+
+	return &Result{}, nil
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	configclient "sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/repository"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
+)
+
+func TestVerifyVariables(t *testing.T) {
+	components := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: controller-manager
+spec:
+  template:
+    spec:
+      containers:
+      - args:
+        - --feature-gates=MachinePool=${EXP_MACHINE_POOL:=false}
+        - --region=${AWS_REGION}
+        - --credentials=${AWS_B64ENCODED_CREDENTIALS}
+`
+
+	testCases := []struct {
+		name              string
+		variables         map[string]string
+		templateProcessor operatorv1.TemplateProcessor
+		expectedMissing   []string
+	}{
+		{
+			name:      "all variables set",
+			variables: map[string]string{"AWS_REGION": "eu-west-1", "AWS_B64ENCODED_CREDENTIALS": "Y3JlZHM="},
+		},
+		{
+			name:            "missing variables listed by name",
+			variables:       map[string]string{"EXP_MACHINE_POOL": "true"},
+			expectedMissing: []string{"AWS_B64ENCODED_CREDENTIALS", "AWS_REGION"},
+		},
+		{
+			name:              "missing variables of the selected processor",
+			variables:         map[string]string{"AWS_REGION": "eu-west-1"},
+			templateProcessor: operatorv1.EnvsubstTemplateProcessor,
+			expectedMissing:   []string{"AWS_B64ENCODED_CREDENTIALS"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			reader := configclient.NewMemoryReader()
+			for key, value := range tc.variables {
+				reader.Set(key, value)
+			}
+
+			configClient, err := configclient.New(context.Background(), "", configclient.InjectReader(reader))
+			g.Expect(err).NotTo(HaveOccurred())
+
+			repo := repository.NewMemoryRepository().
+				WithPaths("", "components.yaml").
+				WithFile("v2.3.0", "components.yaml", []byte(components))
+
+			provider := &operatorv1.InfrastructureProvider{
+				ObjectMeta: metav1.ObjectMeta{Name: "aws", Namespace: "capa-system"},
+				Spec: operatorv1.InfrastructureProviderSpec{ProviderSpec: operatorv1.ProviderSpec{
+					Version:           "v2.3.0",
+					TemplateProcessor: tc.templateProcessor,
+				}},
+			}
+
+			p := &PhaseReconciler{
+				provider:     provider,
+				repo:         repo,
+				configClient: configClient,
+				options:      repository.ComponentsOptions{Version: "v2.3.0"},
+			}
+
+			_, err = p.VerifyVariables(context.Background())
+			if tc.expectedMissing != nil {
+				pe := &PhaseError{}
+				g.Expect(errors.As(err, &pe)).To(BeTrue())
+				g.Expect(pe.Type).To(Equal(operatorv1.VariablesResolvedCondition))
+				g.Expect(pe.Reason).To(Equal(operatorv1.MissingVariablesReason))
+
+				for _, name := range tc.expectedMissing {
+					g.Expect(pe.Error()).To(ContainSubstring(name))
+				}
+
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(conditions.IsTrue(provider, operatorv1.VariablesResolvedCondition)).To(BeTrue())
+		})
+	}
+}
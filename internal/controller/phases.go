@@ -71,6 +71,7 @@ type PhaseReconciler struct {
 	clusterctlProvider         *clusterctlv1.Provider
 	needsCompression           bool
 	customAlterComponentsFuncs []repository.ComponentsAlterFn
+	ociRegistryDefaults        OCIRegistryOptions
 }
 
 // PhaseReconcilerOption is a function that configures the reconciler.
@@ -111,6 +112,14 @@ func WithCustomAlterComponentsFuncs(fns []repository.ComponentsAlterFn) PhaseRec
 	}
 }
 
+// WithOCIRegistryOptions configures the reconciler to connect to OCI registries with the given default options.
+// The credentials are read from the provider variables if the options have none.
+func WithOCIRegistryOptions(options OCIRegistryOptions) PhaseReconcilerOption {
+	return func(r *PhaseReconciler) {
+		r.ociRegistryDefaults = options
+	}
+}
+
 // PhaseFn is a function that represent a phase of the reconciliation.
 type PhaseFn func(context.Context) (*Result, error)
 
@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/google/go-github/v82/github"
 	"golang.org/x/oauth2"
@@ -30,6 +31,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/version"
 	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
 	"sigs.k8s.io/cluster-api-operator/internal/controller/genericprovider"
+	"sigs.k8s.io/cluster-api-operator/util"
 	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
 	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
 	configclient "sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
@@ -70,9 +72,9 @@ func preflightChecks(ctx context.Context, c client.Client, provider genericprovi
 
 	spec := provider.GetSpec()
 
-	if spec.Version != "" {
+	if version := providerVersion(provider); version != "" {
 		// Check that the provider version is supported.
-		if err := checkProviderVersion(ctx, spec.Version, provider); err != nil {
+		if err := checkProviderVersion(ctx, version, provider); err != nil {
 			return err
 		}
 	}
@@ -92,9 +94,9 @@ func preflightChecks(ctx context.Context, c client.Client, provider genericprovi
 	}
 
 	if !isPredefinedProvider {
-		if spec.FetchConfig == nil || spec.FetchConfig.Selector == nil && spec.FetchConfig.URL == "" && spec.FetchConfig.OCI == "" {
+		if spec.FetchConfig == nil || spec.FetchConfig.Selector == nil && spec.FetchConfig.URL == "" && spec.FetchConfig.OCI == "" && spec.FetchConfig.HTTP == nil && spec.FetchConfig.Git == nil {
 			return setPreflightFailed(provider, operatorv1.FetchConfigValidationErrorReason,
-				"Either Selector, OCI URL or provider URL must be provided for a not predefined provider")
+				"Either Selector, OCI URL, HTTP source, git repository or provider URL must be provided for a not predefined provider")
 		}
 	}
 
@@ -104,6 +106,30 @@ func preflightChecks(ctx context.Context, c client.Client, provider genericprovi
 			"Only one of Selector and URL must be provided, not both")
 	}
 
+	if spec.FetchConfig != nil && spec.FetchConfig.Git != nil && spec.Version == "" {
+		return setPreflightFailed(provider, operatorv1.FetchConfigValidationErrorReason,
+			"Version must be set for a provider built from a git repository")
+	}
+
+	if spec.VersionPolicy != nil {
+		if spec.FetchConfig != nil && (spec.FetchConfig.OCI != "" || spec.FetchConfig.Selector != nil || spec.FetchConfig.Git != nil) {
+			return setPreflightFailed(provider, operatorv1.FetchConfigValidationErrorReason,
+				"Version policy is not supported with OCI, Selector or git fetch configuration")
+		}
+
+		if spec.VersionPolicy.Constraint != "" {
+			if _, err := parseVersionConstraint(spec.VersionPolicy.Constraint); err != nil {
+				return setPreflightFailed(provider, operatorv1.IncorrectVersionFormatReason, err.Error())
+			}
+		}
+	}
+
+	if spec.MaintenanceWindow != nil {
+		if _, _, err := maintenanceWindowState(spec.MaintenanceWindow, time.Now()); err != nil {
+			return setPreflightFailed(provider, operatorv1.InvalidMaintenanceWindowReason, err.Error())
+		}
+	}
+
 	// Validate that provided GitHub token works and has repository access.
 	if spec.ConfigSecret != nil {
 		secret := &corev1.Secret{}
@@ -201,6 +227,12 @@ func checkProviderVersion(ctx context.Context, providerVersion string, provider
 		if targetVersion.Major() < installedVersion.Major() || targetVersion.Major() == installedVersion.Major() && targetVersion.Minor() < installedVersion.Minor() {
 			log.V(2).Info("Provider downgrade detected", "installedVersion", installedVersion.String(), "targetVersion", targetVersion.String())
 
+			if isDowngradeAllowed(provider) {
+				log.Info("Provider downgrade is allowed", "installedVersion", installedVersion.String(), "targetVersion", targetVersion.String())
+
+				return nil
+			}
+
 			return setPreflightFailed(provider, operatorv1.UnsupportedProviderDowngradeReason,
 				unsupportedProviderDowngradeMessage)
 		}
@@ -209,6 +241,31 @@ func checkProviderVersion(ctx context.Context, providerVersion string, provider
 	return nil
 }
 
+// checkProviderContract verifies that the contract of the provider version, read from the provider metadata,
+// is compatible with the contract of the installed core provider.
+func checkProviderContract(ctx context.Context, provider genericprovider.GenericProvider, contract string, mapper ProviderTypeMapper, lister ProviderLister) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	if mapper(provider) == clusterctlv1.CoreProviderType {
+		return nil
+	}
+
+	coreContract := ""
+	if err := lister(ctx, &clusterctlv1.ProviderList{}, coreProviderContract(&coreContract, mapper)); err != nil {
+		return fmt.Errorf("failed to get core provider contract: %w", err)
+	}
+
+	// The core provider contract is not known until it's installed.
+	if coreContract == "" || util.CompatibleContracts(coreContract).Has(contract) {
+		return nil
+	}
+
+	log.Info("Provider contract isn't compatible with the core provider contract", "contract", contract, "coreProviderContract", coreContract)
+
+	return setPreflightFailed(provider, operatorv1.CAPIVersionIncompatibilityReason,
+		fmt.Sprintf(capiVersionIncompatibilityMessage, coreContract, contract, provider.GetName()))
+}
+
 // coreProviderIsReady returns true if the core provider is ready.
 func coreProviderIsReady(ready *bool, mapper ProviderTypeMapper) ProviderOperation {
 	return func(provider operatorv1.GenericProvider) error {
@@ -23,8 +23,10 @@ import (
 
 	. "github.com/onsi/gomega"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
 	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
@@ -737,7 +739,7 @@ func TestPreflightChecks(t *testing.T) {
 			expectedCondition: metav1.Condition{
 				Type:    operatorv1.PreflightCheckCondition,
 				Reason:  operatorv1.FetchConfigValidationErrorReason,
-				Message: "Either Selector, OCI URL or provider URL must be provided for a not predefined provider",
+				Message: "Either Selector, OCI URL, HTTP source, git repository or provider URL must be provided for a not predefined provider",
 				Status:  metav1.ConditionFalse,
 			},
 			providerList: &operatorv1.CoreProviderList{},
@@ -769,7 +771,37 @@ func TestPreflightChecks(t *testing.T) {
 			expectedCondition: metav1.Condition{
 				Type:    operatorv1.PreflightCheckCondition,
 				Reason:  operatorv1.FetchConfigValidationErrorReason,
-				Message: "Either Selector, OCI URL or provider URL must be provided for a not predefined provider",
+				Message: "Either Selector, OCI URL, HTTP source, git repository or provider URL must be provided for a not predefined provider",
+				Status:  metav1.ConditionFalse,
+			},
+			providerList: &operatorv1.CoreProviderList{},
+		},
+		{
+			name:          "custom Infrastructure Provider built from git without version, preflight check failed",
+			expectedError: true,
+			providers: []operatorv1.GenericProvider{
+				&operatorv1.InfrastructureProvider{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "my-custom-aws",
+						Namespace: namespaceName1,
+					},
+					TypeMeta: metav1.TypeMeta{
+						Kind:       "InfrastructureProvider",
+						APIVersion: "operator.cluster.x-k8s.io/v1alpha1",
+					},
+					Spec: operatorv1.InfrastructureProviderSpec{
+						ProviderSpec: operatorv1.ProviderSpec{
+							FetchConfig: &operatorv1.FetchConfiguration{
+								Git: &operatorv1.GitConfiguration{URL: "https://github.com/my-org/cluster-api-provider-aws.git", Ref: "main"},
+							},
+						},
+					},
+				},
+			},
+			expectedCondition: metav1.Condition{
+				Type:    operatorv1.PreflightCheckCondition,
+				Reason:  operatorv1.FetchConfigValidationErrorReason,
+				Message: "Version must be set for a provider built from a git repository",
 				Status:  metav1.ConditionFalse,
 			},
 			providerList: &operatorv1.CoreProviderList{},
@@ -816,6 +848,7 @@ func TestPreflightChecksUpgradesDowngrades(t *testing.T) {
 		name                    string
 		installedVersion        string
 		targetVersion           string
+		allowDowngrade          bool
 		expectedConditionStatus metav1.ConditionStatus
 		expectedError           bool
 	}{
@@ -851,6 +884,13 @@ func TestPreflightChecksUpgradesDowngrades(t *testing.T) {
 			installedVersion:        "v1.10.1",
 			targetVersion:           "v1.10.0",
 		},
+		{
+			name:                    "downgrade core provider minor version with downgrades allowed",
+			expectedConditionStatus: metav1.ConditionTrue,
+			installedVersion:        "v1.10.0",
+			targetVersion:           "v1.9.0",
+			allowDowngrade:          true,
+		},
 		{
 			name:                    "same version",
 			expectedConditionStatus: metav1.ConditionTrue,
@@ -887,6 +927,10 @@ func TestPreflightChecksUpgradesDowngrades(t *testing.T) {
 				},
 			}
 
+			if tc.allowDowngrade {
+				provider.SetAnnotations(map[string]string{operatorv1.AllowDowngradeAnnotation: operatorv1.TrueValue})
+			}
+
 			fakeClient := fake.NewClientBuilder().WithObjects().Build()
 
 			gs.Expect(fakeClient.Create(ctx, provider)).To(Succeed())
@@ -913,3 +957,88 @@ func TestPreflightChecksUpgradesDowngrades(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckProviderContract(t *testing.T) {
+	testCases := []struct {
+		name                    string
+		provider                operatorv1.GenericProvider
+		coreContract            string
+		contract                string
+		expectedErr             bool
+		expectedConditionReason string
+	}{
+		{
+			name:     "matching contract",
+			provider: &operatorv1.InfrastructureProvider{ObjectMeta: metav1.ObjectMeta{Name: "docker", Namespace: "capd-system"}},
+			contract: "v1beta2",
+		},
+		{
+			name:     "v1beta1 contract is compatible with the v1beta2 core provider",
+			provider: &operatorv1.InfrastructureProvider{ObjectMeta: metav1.ObjectMeta{Name: "docker", Namespace: "capd-system"}},
+			contract: "v1beta1",
+		},
+		{
+			name:                    "contract isn't compatible with the core provider",
+			provider:                &operatorv1.InfrastructureProvider{ObjectMeta: metav1.ObjectMeta{Name: "docker", Namespace: "capd-system"}},
+			contract:                "v1alpha4",
+			expectedErr:             true,
+			expectedConditionReason: operatorv1.CAPIVersionIncompatibilityReason,
+		},
+		{
+			name:                    "v1beta2 contract isn't compatible with the v1beta1 core provider",
+			provider:                &operatorv1.InfrastructureProvider{ObjectMeta: metav1.ObjectMeta{Name: "docker", Namespace: "capd-system"}},
+			coreContract:            "v1beta1",
+			contract:                "v1beta2",
+			expectedErr:             true,
+			expectedConditionReason: operatorv1.CAPIVersionIncompatibilityReason,
+		},
+		{
+			name:         "core provider contract is not known yet",
+			provider:     &operatorv1.InfrastructureProvider{ObjectMeta: metav1.ObjectMeta{Name: "docker", Namespace: "capd-system"}},
+			coreContract: "-",
+			contract:     "v1beta1",
+		},
+		{
+			name:     "core provider is not checked",
+			provider: &operatorv1.CoreProvider{ObjectMeta: metav1.ObjectMeta{Name: "cluster-api", Namespace: "capi-system"}},
+			contract: "v1beta1",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			core := &operatorv1.CoreProvider{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-api", Namespace: "capi-system"},
+				Status: operatorv1.CoreProviderStatus{
+					ProviderStatus: operatorv1.ProviderStatus{Contract: ptr.To("v1beta2")},
+				},
+			}
+
+			switch {
+			case tc.coreContract == "-":
+				core.Status.Contract = nil
+			case tc.coreContract != "":
+				core.Status.Contract = ptr.To(tc.coreContract)
+			}
+
+			fakeClient := fake.NewClientBuilder().WithScheme(cacheTestScheme()).WithStatusSubresource(core).WithObjects(core).Build()
+			r := GenericProviderReconciler{Client: fakeClient}
+
+			err := checkProviderContract(context.Background(), tc.provider, tc.contract, util.ClusterctlProviderType, r.listProviders)
+			if !tc.expectedErr {
+				g.Expect(err).NotTo(HaveOccurred())
+				return
+			}
+
+			g.Expect(err).To(HaveOccurred())
+			g.Expect(err.Error()).To(ContainSubstring("detected %s for provider %s", tc.contract, tc.provider.GetName()))
+
+			condition := conditions.Get(tc.provider, operatorv1.PreflightCheckCondition)
+			g.Expect(condition).NotTo(BeNil())
+			g.Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			g.Expect(condition.Reason).To(Equal(tc.expectedConditionReason))
+		})
+	}
+}
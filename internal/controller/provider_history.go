@@ -0,0 +1,140 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
+)
+
+// maxProviderHistoryEntries is the maximum number of entries kept in the provider status history.
+const maxProviderHistoryEntries = 10
+
+// recordHistory appends an entry for the given provider version to the provider status history.
+func (p *PhaseReconciler) recordHistory(ctx context.Context, version string, outcome operatorv1.ProviderHistoryOutcome, appliedSpecHash, message string) {
+	status := p.provider.GetStatus()
+
+	contract := p.contract
+	if contract == "" && status.Contract != nil {
+		contract = *status.Contract
+	}
+
+	status.History = appendProviderHistory(status.History, operatorv1.ProviderHistoryEntry{
+		Version:         version,
+		Contract:        contract,
+		Source:          p.manifestsSource(ctx, version),
+		AppliedSpecHash: appliedSpecHash,
+		Time:            metav1.Now(),
+		Outcome:         outcome,
+		Message:         message,
+	})
+	p.provider.SetStatus(status)
+}
+
+// appliedCacheHash returns the hash of the provider spec and the cached manifests of the provider version.
+// An empty hash is returned if the manifests are not cached.
+func (p *PhaseReconciler) appliedCacheHash(ctx context.Context) (string, error) {
+	secret := &corev1.Secret{}
+	if err := p.ctrlClient.Get(ctx, client.ObjectKey{Name: ProviderCacheName(p.provider), Namespace: p.provider.GetNamespace()}, secret); apierrors.IsNotFound(err) {
+		return "", nil
+	} else if err != nil {
+		return "", fmt.Errorf("failed to get provider cache: %w", err)
+	}
+
+	return calculateCacheHash(ctx, p.ctrlClient, p.provider, secret.Data)
+}
+
+// manifestsSource returns the location the manifests of the provider version are fetched from. The source
+// recorded on the ConfigMap with the downloaded manifests is preferred, as the manifests may be fetched from
+// a fallback source. OCI artifacts are identified by their reference and digest, as tags can be re-pushed.
+func (p *PhaseReconciler) manifestsSource(ctx context.Context, version string) string {
+	fetchConfig := p.provider.GetSpec().FetchConfig
+
+	if fetchConfig != nil && fetchConfig.Selector != nil {
+		return fmt.Sprintf("configmap:%s", metav1.FormatLabelSelector(fetchConfig.Selector))
+	}
+
+	labels := ProviderLabels(p.provider)
+	labels[operatorv1.ConfigMapVersionLabelName] = version
+
+	configMaps := &corev1.ConfigMapList{}
+	if err := p.ctrlClient.List(ctx, configMaps, client.InNamespace(p.provider.GetNamespace()), client.MatchingLabels(labels)); err != nil {
+		ctrl.LoggerFrom(ctx).V(5).Error(err, "Failed to list the manifests ConfigMap of the provider version", "version", version)
+	} else if len(configMaps.Items) == 1 && configMaps.Items[0].Annotations[configMapSourceAnnotation] != "" {
+		annotations := configMaps.Items[0].Annotations
+		if digest := annotations[ociDigestAnnotation]; digest != "" {
+			return ociDigestReference(annotations[configMapSourceAnnotation], version, digest)
+		}
+
+		return annotations[configMapSourceAnnotation]
+	}
+
+	switch {
+	case fetchConfig != nil && fetchConfig.OCI != "":
+		if artifact := p.provider.GetStatus().OCIArtifact; artifact != nil && artifact.Digest != "" &&
+			artifact.Reference == ociDigestReference(fetchConfig.OCI, version, "") {
+			return ociDigestReference(fetchConfig.OCI, version, artifact.Digest)
+		}
+
+		return fetchConfig.OCI
+	case fetchConfig != nil && fetchConfig.URL != "":
+		return fetchConfig.URL
+	case p.providerConfig != nil:
+		return p.providerConfig.URL()
+	default:
+		return ""
+	}
+}
+
+// ociDigestReference returns the reference of the OCI artifact of the provider version, with the digest if set.
+func ociDigestReference(source, version, digest string) string {
+	url, tag, _ := parseOCISource(source, version)
+
+	reference := fmt.Sprintf("%s:%s", url, tag)
+	if digest != "" {
+		reference += "@" + digest
+	}
+
+	return reference
+}
+
+// appendProviderHistory appends the entry to the history, dropping the oldest entries over the limit.
+// An entry repeating the outcome of the last one replaces it, so retried operations don't flood the history.
+func appendProviderHistory(history []operatorv1.ProviderHistoryEntry, entry operatorv1.ProviderHistoryEntry) []operatorv1.ProviderHistoryEntry {
+	if n := len(history); n > 0 {
+		last := history[n-1]
+		if last.Version == entry.Version && last.Outcome == entry.Outcome && last.AppliedSpecHash == entry.AppliedSpecHash {
+			history = history[:n-1]
+		}
+	}
+
+	history = append(history, entry)
+
+	if len(history) > maxProviderHistoryEntries {
+		history = history[len(history)-maxProviderHistoryEntries:]
+	}
+
+	return history
+}
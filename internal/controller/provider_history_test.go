@@ -0,0 +1,228 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
+)
+
+func TestAppendProviderHistory(t *testing.T) {
+	testCases := []struct {
+		name             string
+		history          []operatorv1.ProviderHistoryEntry
+		entry            operatorv1.ProviderHistoryEntry
+		expectedVersions []string
+		expectedOutcomes []operatorv1.ProviderHistoryOutcome
+	}{
+		{
+			name:             "empty history",
+			entry:            operatorv1.ProviderHistoryEntry{Version: "v1.11.0", Outcome: operatorv1.ProviderHistoryInstalled},
+			expectedVersions: []string{"v1.11.0"},
+			expectedOutcomes: []operatorv1.ProviderHistoryOutcome{operatorv1.ProviderHistoryInstalled},
+		},
+		{
+			name: "new version",
+			history: []operatorv1.ProviderHistoryEntry{
+				{Version: "v1.11.0", Outcome: operatorv1.ProviderHistoryInstalled},
+			},
+			entry:            operatorv1.ProviderHistoryEntry{Version: "v1.12.0", Outcome: operatorv1.ProviderHistoryUpgraded},
+			expectedVersions: []string{"v1.11.0", "v1.12.0"},
+			expectedOutcomes: []operatorv1.ProviderHistoryOutcome{operatorv1.ProviderHistoryInstalled, operatorv1.ProviderHistoryUpgraded},
+		},
+		{
+			name: "repeated failure replaces the last entry",
+			history: []operatorv1.ProviderHistoryEntry{
+				{Version: "v1.11.0", Outcome: operatorv1.ProviderHistoryInstalled},
+				{Version: "v1.12.0", Outcome: operatorv1.ProviderHistoryUpgradeFailed, Message: "first"},
+			},
+			entry:            operatorv1.ProviderHistoryEntry{Version: "v1.12.0", Outcome: operatorv1.ProviderHistoryUpgradeFailed, Message: "second"},
+			expectedVersions: []string{"v1.11.0", "v1.12.0"},
+			expectedOutcomes: []operatorv1.ProviderHistoryOutcome{operatorv1.ProviderHistoryInstalled, operatorv1.ProviderHistoryUpgradeFailed},
+		},
+		{
+			name: "re-installation with a different spec",
+			history: []operatorv1.ProviderHistoryEntry{
+				{Version: "v1.11.0", Outcome: operatorv1.ProviderHistoryInstalled, AppliedSpecHash: "a"},
+			},
+			entry:            operatorv1.ProviderHistoryEntry{Version: "v1.11.0", Outcome: operatorv1.ProviderHistoryInstalled, AppliedSpecHash: "b"},
+			expectedVersions: []string{"v1.11.0", "v1.11.0"},
+			expectedOutcomes: []operatorv1.ProviderHistoryOutcome{operatorv1.ProviderHistoryInstalled, operatorv1.ProviderHistoryInstalled},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			history := appendProviderHistory(tc.history, tc.entry)
+
+			versions := []string{}
+			outcomes := []operatorv1.ProviderHistoryOutcome{}
+
+			for _, entry := range history {
+				versions = append(versions, entry.Version)
+				outcomes = append(outcomes, entry.Outcome)
+			}
+
+			g.Expect(versions).To(Equal(tc.expectedVersions))
+			g.Expect(outcomes).To(Equal(tc.expectedOutcomes))
+			g.Expect(history[len(history)-1]).To(Equal(tc.entry))
+		})
+	}
+}
+
+func TestAppendProviderHistoryLimit(t *testing.T) {
+	g := NewWithT(t)
+
+	var history []operatorv1.ProviderHistoryEntry
+
+	for i := range maxProviderHistoryEntries + 5 {
+		history = appendProviderHistory(history, operatorv1.ProviderHistoryEntry{
+			Version: fmt.Sprintf("v1.%d.0", i),
+			Outcome: operatorv1.ProviderHistoryUpgraded,
+		})
+	}
+
+	g.Expect(history).To(HaveLen(maxProviderHistoryEntries))
+	g.Expect(history[0].Version).To(Equal("v1.5.0"))
+	g.Expect(history[maxProviderHistoryEntries-1].Version).To(Equal(fmt.Sprintf("v1.%d.0", maxProviderHistoryEntries+4)))
+}
+
+func TestManifestsSource(t *testing.T) {
+	testCases := []struct {
+		name        string
+		fetchConfig *operatorv1.FetchConfiguration
+		configMaps  []client.Object
+		ociArtifact *operatorv1.OCIArtifactStatus
+		version     string
+		expected    string
+	}{
+		{
+			name:     "no fetch config",
+			expected: "",
+		},
+		{
+			name:        "url",
+			fetchConfig: &operatorv1.FetchConfiguration{URL: "https://github.com/kubernetes-sigs/cluster-api/releases"},
+			expected:    "https://github.com/kubernetes-sigs/cluster-api/releases",
+		},
+		{
+			name:        "oci",
+			fetchConfig: &operatorv1.FetchConfiguration{OCIConfiguration: operatorv1.OCIConfiguration{OCI: "registry.example.com/capi:v1.12.0"}},
+			expected:    "registry.example.com/capi:v1.12.0",
+		},
+		{
+			name: "selector",
+			fetchConfig: &operatorv1.FetchConfiguration{Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"provider": "cluster-api"},
+			}},
+			expected: "configmap:provider=cluster-api",
+		},
+		{
+			name: "fallback source recorded on the ConfigMap",
+			fetchConfig: &operatorv1.FetchConfiguration{
+				OCIConfiguration: operatorv1.OCIConfiguration{OCI: "registry.example.com/capi:v1.12.0"},
+				Fallbacks:        []operatorv1.FetchSource{{URL: "https://github.com/kubernetes-sigs/cluster-api/releases"}},
+			},
+			configMaps: []client.Object{
+				manifestsSourceConfigMap("v1.12.0", "https://github.com/kubernetes-sigs/cluster-api/releases", ""),
+				manifestsSourceConfigMap("v1.11.0", "registry.example.com/capi:v1.11.0", ""),
+			},
+			expected: "https://github.com/kubernetes-sigs/cluster-api/releases",
+		},
+		{
+			name:        "source of another version",
+			fetchConfig: &operatorv1.FetchConfiguration{OCIConfiguration: operatorv1.OCIConfiguration{OCI: "registry.example.com/capi:v1.12.0"}},
+			configMaps: []client.Object{
+				manifestsSourceConfigMap("v1.12.0", "registry.example.com/capi:v1.12.0", ""),
+				manifestsSourceConfigMap("v1.11.0", "https://github.com/kubernetes-sigs/cluster-api/releases", ""),
+			},
+			version:  "v1.11.0",
+			expected: "https://github.com/kubernetes-sigs/cluster-api/releases",
+		},
+		{
+			name:        "oci artifact digest recorded on the ConfigMap",
+			fetchConfig: &operatorv1.FetchConfiguration{OCIConfiguration: operatorv1.OCIConfiguration{OCI: "registry.example.com/capi"}},
+			configMaps: []client.Object{
+				manifestsSourceConfigMap("v1.12.0", "registry.example.com/capi", "sha256:1234"),
+			},
+			expected: "registry.example.com/capi:v1.12.0@sha256:1234",
+		},
+		{
+			name:        "oci artifact digest in the status",
+			fetchConfig: &operatorv1.FetchConfiguration{OCIConfiguration: operatorv1.OCIConfiguration{OCI: "registry.example.com/capi:v1.12.0"}},
+			ociArtifact: &operatorv1.OCIArtifactStatus{Reference: "registry.example.com/capi:v1.12.0", Digest: "sha256:1234"},
+			expected:    "registry.example.com/capi:v1.12.0@sha256:1234",
+		},
+		{
+			name:        "oci artifact digest of another version in the status",
+			fetchConfig: &operatorv1.FetchConfiguration{OCIConfiguration: operatorv1.OCIConfiguration{OCI: "registry.example.com/capi"}},
+			ociArtifact: &operatorv1.OCIArtifactStatus{Reference: "registry.example.com/capi:v1.11.0", Digest: "sha256:1234"},
+			expected:    "registry.example.com/capi",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			provider := rollbackTestProvider(nil, nil)
+			provider.Spec.FetchConfig = tc.fetchConfig
+			provider.Status.OCIArtifact = tc.ociArtifact
+
+			p := &PhaseReconciler{
+				provider:   provider,
+				ctrlClient: fake.NewClientBuilder().WithObjects(tc.configMaps...).Build(),
+			}
+
+			g.Expect(p.manifestsSource(context.Background(), cmp.Or(tc.version, providerVersion(provider)))).To(Equal(tc.expected))
+		})
+	}
+}
+
+func manifestsSourceConfigMap(version, source, digest string) *corev1.ConfigMap {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "core-cluster-api-" + version,
+			Namespace: "capi-system",
+			Labels: map[string]string{
+				operatorv1.ConfigMapVersionLabelName: version,
+				operatorv1.ConfigMapTypeLabel:        "core",
+				operatorv1.ConfigMapNameLabel:        "cluster-api",
+				operatorManagedLabel:                 "true",
+			},
+			Annotations: map[string]string{configMapSourceAnnotation: source},
+		},
+	}
+
+	if digest != "" {
+		configMap.Annotations[ociDigestAnnotation] = digest
+	}
+
+	return configMap
+}
@@ -0,0 +1,468 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	versionutil "k8s.io/apimachinery/pkg/util/version"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
+	"sigs.k8s.io/cluster-api-operator/util"
+)
+
+// upgradePlanStepInterval is the interval the progress of a provider upgrade is checked at.
+const upgradePlanStepInterval = 15 * time.Second
+
+// ProviderUpgradePlanReconciler upgrades the providers of a ProviderUpgradePlan one at a time,
+// starting with the core provider.
+type ProviderUpgradePlanReconciler struct {
+	Client client.Client
+
+	// latestVersion returns the latest release of the provider implementing the contract, or an empty string
+	// if it can't be determined. Defaults to the release series of the metadata in the provider repository.
+	latestVersion func(ctx context.Context, provider operatorv1.GenericProvider, contract string) (string, error)
+
+	// versionContract returns the contract of the provider version, read from the provider metadata,
+	// or an empty string if it can't be determined. Defaults to the metadata in the provider repository.
+	versionContract func(ctx context.Context, provider operatorv1.GenericProvider, version string) (string, error)
+}
+
+func (r *ProviderUpgradePlanReconciler) SetupWithManager(mgr ctrl.Manager, options controller.Options) error {
+	builder := ctrl.NewControllerManagedBy(mgr).
+		For(&operatorv1.ProviderUpgradePlan{})
+
+	// Provider changes are watched to track the progress of the upgrade steps.
+	for _, provider := range operatorv1.Providers {
+		builder = builder.Watches(provider, handler.EnqueueRequestsFromMapFunc(r.providerToUpgradePlans))
+	}
+
+	return builder.WithOptions(options).Complete(r)
+}
+
+func (r *ProviderUpgradePlanReconciler) Reconcile(ctx context.Context, req reconcile.Request) (_ reconcile.Result, reterr error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	plan := &operatorv1.ProviderUpgradePlan{}
+	if err := r.Client.Get(ctx, req.NamespacedName, plan); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("ProviderUpgradePlan not found, skipping reconciliation")
+
+			return ctrl.Result{}, nil
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	patchHelper, err := patch.NewHelper(plan, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	defer func() {
+		if err := patchHelper.Patch(ctx, plan, patch.WithOwnedConditions{Conditions: []string{operatorv1.UpgradeCompletedCondition}}); err != nil {
+			reterr = kerrors.NewAggregate([]error{reterr, err})
+		}
+	}()
+
+	// The steps are planned once per generation of the plan.
+	if plan.Status.ObservedGeneration != plan.Generation {
+		log.Info("Planning provider upgrades")
+
+		steps, contract, err := r.planSteps(ctx, plan)
+		if err != nil {
+			conditions.Set(plan, metav1.Condition{
+				Type:    operatorv1.UpgradeCompletedCondition,
+				Status:  metav1.ConditionFalse,
+				Reason:  operatorv1.UpgradePlanInvalidReason,
+				Message: err.Error(),
+			})
+
+			return ctrl.Result{}, err
+		}
+
+		plan.Status.Steps = steps
+		plan.Status.Contract = contract
+		plan.Status.ObservedGeneration = plan.Generation
+	}
+
+	return r.reconcileSteps(ctx, plan)
+}
+
+// planSteps resolves and validates the target version of each provider, and returns the upgrade steps
+// in the order they have to be applied.
+func (r *ProviderUpgradePlanReconciler) planSteps(ctx context.Context, plan *operatorv1.ProviderUpgradePlan) ([]operatorv1.ProviderUpgradeStep, string, error) {
+	genericProviders, contract, err := util.GetInstalledProviders(ctx, r.Client)
+	if err != nil {
+		return nil, "", err
+	}
+
+	type upgradeTarget struct {
+		provider operatorv1.GenericProvider
+		version  string
+	}
+
+	targets := []upgradeTarget{}
+
+	if plan.Spec.Contract != "" {
+		if err := util.ValidateUpgradeContract(plan.Spec.Contract); err != nil {
+			return nil, "", err
+		}
+
+		contract = plan.Spec.Contract
+
+		for _, provider := range genericProviders {
+			version, err := r.getLatestVersion(ctx, provider, contract)
+			if err != nil {
+				return nil, "", err
+			}
+
+			// Providers without a known release for the contract, e.g. fetched from ConfigMaps, are not upgraded.
+			if version == "" {
+				continue
+			}
+
+			targets = append(targets, upgradeTarget{provider: provider, version: version})
+		}
+	} else {
+		for _, target := range plan.Spec.Providers {
+			provider, err := findUpgradePlanProvider(genericProviders, target)
+			if err != nil {
+				return nil, "", err
+			}
+
+			for _, t := range targets {
+				if t.provider == provider {
+					return nil, "", fmt.Errorf("%s provider %s/%s is listed more than once", provider.GetType(), provider.GetNamespace(), provider.ProviderName())
+				}
+			}
+
+			targets = append(targets, upgradeTarget{provider: provider, version: target.Version})
+		}
+	}
+
+	steps := []operatorv1.ProviderUpgradeStep{}
+	orders := map[string]int{}
+
+	for _, target := range targets {
+		provider := target.provider
+		currentVersion := provider.GetSpec().Version
+
+		if currentVersion == target.version {
+			continue
+		}
+
+		if _, err := versionutil.ParseSemantic(target.version); err != nil {
+			return nil, "", fmt.Errorf("invalid version %q for %s provider %s/%s: %w", target.version, provider.GetType(), provider.GetNamespace(), provider.ProviderName(), err)
+		}
+
+		if isDowngrade(currentVersion, target.version) && !isDowngradeAllowed(provider) {
+			return nil, "", fmt.Errorf("%s provider %s/%s can't be downgraded from %s to %s without the %s annotation",
+				provider.GetType(), provider.GetNamespace(), provider.ProviderName(), currentVersion, target.version, operatorv1.AllowDowngradeAnnotation)
+		}
+
+		steps = append(steps, operatorv1.ProviderUpgradeStep{
+			Type:        provider.GetType(),
+			Name:        provider.ProviderName(),
+			Namespace:   provider.GetNamespace(),
+			FromVersion: currentVersion,
+			Version:     target.version,
+			Phase:       operatorv1.ProviderUpgradeStepPending,
+		})
+		orders[provider.GetType()] = util.ClusterctlProviderType(provider).Order()
+	}
+
+	// Providers are upgraded in dependency order, starting with the core provider.
+	sort.SliceStable(steps, func(i, j int) bool {
+		return orders[steps[i].Type] < orders[steps[j].Type]
+	})
+
+	// The target versions are checked against the contract of the core provider, like the operator does
+	// before installing a provider version.
+	contract, err = r.validateStepContracts(ctx, genericProviders, steps, contract)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return steps, contract, nil
+}
+
+// validateStepContracts checks that the target version of each provider implements a contract compatible with
+// the contract of the core provider, and returns the contract of the core provider once the steps are applied.
+func (r *ProviderUpgradePlanReconciler) validateStepContracts(ctx context.Context, genericProviders []operatorv1.GenericProvider,
+	steps []operatorv1.ProviderUpgradeStep, coreContract string,
+) (string, error) {
+	contracts := make([]string, len(steps))
+	core := -1
+
+	for i, step := range steps {
+		provider, err := findUpgradePlanProvider(genericProviders, operatorv1.ProviderUpgradeTarget{Type: step.Type, Name: step.Name, Namespace: step.Namespace})
+		if err != nil {
+			return "", err
+		}
+
+		contracts[i], err = r.getVersionContract(ctx, provider, step.Version)
+		if err != nil {
+			return "", fmt.Errorf("cannot get the contract of %s provider %s/%s version %s: %w", step.Type, step.Namespace, step.Name, step.Version, err)
+		}
+
+		if util.ClusterctlProviderType(provider) == clusterctlv1.CoreProviderType {
+			core = i
+			coreContract = cmp.Or(contracts[i], coreContract)
+		}
+	}
+
+	for i, step := range steps {
+		if i == core || contracts[i] == "" || util.CompatibleContracts(coreContract).Has(contracts[i]) {
+			continue
+		}
+
+		return "", fmt.Errorf("%s provider %s/%s version %s implements the %s contract, which is not compatible with the %s contract of the core provider",
+			step.Type, step.Namespace, step.Name, step.Version, contracts[i], coreContract)
+	}
+
+	return coreContract, nil
+}
+
+// reconcileSteps drives the upgrade steps in order. A step is started only after the previous one completed.
+func (r *ProviderUpgradePlanReconciler) reconcileSteps(ctx context.Context, plan *operatorv1.ProviderUpgradePlan) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	for i := range plan.Status.Steps {
+		step := &plan.Status.Steps[i]
+
+		switch step.Phase {
+		case operatorv1.ProviderUpgradeStepCompleted:
+			continue
+		case operatorv1.ProviderUpgradeStepFailed:
+			// Failed plans are not retried until the plan spec is changed.
+			return ctrl.Result{}, nil
+		}
+
+		genericProviders, _, err := util.GetInstalledProviders(ctx, r.Client)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		provider, err := findUpgradePlanProvider(genericProviders, operatorv1.ProviderUpgradeTarget{
+			Type:      step.Type,
+			Name:      step.Name,
+			Namespace: step.Namespace,
+		})
+		if err != nil {
+			failUpgradeStep(plan, step, operatorv1.ProviderUpgradeFailedReason, err.Error())
+
+			return ctrl.Result{}, nil
+		}
+
+		if step.Phase == operatorv1.ProviderUpgradeStepPending {
+			log.Info("Upgrading provider", "type", step.Type, "name", step.Name, "namespace", step.Namespace, "version", step.Version)
+
+			if err := setProviderVersion(ctx, r.Client, provider, step.Version); err != nil {
+				return ctrl.Result{}, err
+			}
+
+			step.Phase = operatorv1.ProviderUpgradeStepInProgress
+			step.StartTime = &metav1.Time{Time: time.Now()}
+
+			conditions.Set(plan, metav1.Condition{
+				Type:    operatorv1.UpgradeCompletedCondition,
+				Status:  metav1.ConditionFalse,
+				Reason:  operatorv1.UpgradeInProgressReason,
+				Message: fmt.Sprintf("Upgrading %s provider %s/%s to %s", step.Type, step.Namespace, step.Name, step.Version),
+			})
+
+			return ctrl.Result{RequeueAfter: upgradePlanStepInterval}, nil
+		}
+
+		// A provider failing the preflight checks for the new version, e.g. because of an incompatible contract,
+		// is never upgraded.
+		preflight := conditions.Get(provider, operatorv1.PreflightCheckCondition)
+		if preflight != nil && preflight.Status == metav1.ConditionFalse && preflight.ObservedGeneration >= provider.GetGeneration() {
+			failUpgradeStep(plan, step, operatorv1.ProviderUpgradeFailedReason, preflight.Message)
+
+			return ctrl.Result{}, nil
+		}
+
+		upgraded, err := util.IsProviderUpgraded(provider, step.Version)
+		if err != nil {
+			failUpgradeStep(plan, step, operatorv1.ProviderUpgradeFailedReason, err.Error())
+
+			return ctrl.Result{}, nil
+		}
+
+		if !upgraded {
+			timeout := plan.Spec.StepTimeout
+			if timeout != nil && step.StartTime != nil && time.Since(step.StartTime.Time) > timeout.Duration {
+				failUpgradeStep(plan, step, operatorv1.ProviderUpgradeTimeoutReason,
+					fmt.Sprintf("provider was not upgraded within %s", timeout.Duration))
+
+				return ctrl.Result{}, nil
+			}
+
+			return ctrl.Result{RequeueAfter: upgradePlanStepInterval}, nil
+		}
+
+		log.Info("Provider is upgraded", "type", step.Type, "name", step.Name, "namespace", step.Namespace, "version", step.Version)
+
+		step.Phase = operatorv1.ProviderUpgradeStepCompleted
+		step.CompletionTime = &metav1.Time{Time: time.Now()}
+		step.Message = ""
+	}
+
+	conditions.Set(plan, metav1.Condition{
+		Type:   operatorv1.UpgradeCompletedCondition,
+		Status: metav1.ConditionTrue,
+		Reason: operatorv1.UpgradeCompletedReason,
+	})
+
+	return ctrl.Result{}, nil
+}
+
+// getLatestVersion returns the latest release of the provider implementing the contract.
+func (r *ProviderUpgradePlanReconciler) getLatestVersion(ctx context.Context, provider operatorv1.GenericProvider, contract string) (string, error) {
+	if r.latestVersion != nil {
+		return r.latestVersion(ctx, provider, contract)
+	}
+
+	fetchURL, sourceType, err := util.GetProviderFetchURL(ctx, provider)
+	if err != nil {
+		return "", fmt.Errorf("cannot get provider fetch URL: %w", err)
+	}
+
+	if sourceType == util.ProviderSourceTypeConfigMap {
+		return "", nil
+	}
+
+	return util.GetLatestProviderVersionForContract(ctx, provider.ProviderName(), fetchURL, contract)
+}
+
+// getVersionContract returns the contract of the provider version, read from the metadata in the provider repository.
+func (r *ProviderUpgradePlanReconciler) getVersionContract(ctx context.Context, provider operatorv1.GenericProvider, version string) (string, error) {
+	if r.versionContract != nil {
+		return r.versionContract(ctx, provider, version)
+	}
+
+	fetchURL, sourceType, err := util.GetProviderFetchURL(ctx, provider)
+	if err != nil {
+		return "", fmt.Errorf("cannot get provider fetch URL: %w", err)
+	}
+
+	if sourceType == util.ProviderSourceTypeConfigMap {
+		return "", nil
+	}
+
+	return util.GetProviderVersionContract(ctx, provider.ProviderName(), fetchURL, version)
+}
+
+// providerToUpgradePlans maps a provider to all the upgrade plans with a step for it which is not completed yet.
+func (r *ProviderUpgradePlanReconciler) providerToUpgradePlans(ctx context.Context, obj client.Object) []reconcile.Request {
+	provider, ok := obj.(operatorv1.GenericProvider)
+	if !ok {
+		return nil
+	}
+
+	plans := &operatorv1.ProviderUpgradePlanList{}
+	if err := r.Client.List(ctx, plans); err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "failed to list provider upgrade plans")
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+
+	for _, plan := range plans.Items {
+		for _, step := range plan.Status.Steps {
+			if step.Phase == operatorv1.ProviderUpgradeStepInProgress &&
+				step.Type == provider.GetType() && step.Name == provider.ProviderName() && step.Namespace == provider.GetNamespace() {
+				requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&plan)})
+
+				break
+			}
+		}
+	}
+
+	return requests
+}
+
+// findUpgradePlanProvider returns the installed provider matching the upgrade target.
+func findUpgradePlanProvider(genericProviders []operatorv1.GenericProvider, target operatorv1.ProviderUpgradeTarget) (operatorv1.GenericProvider, error) {
+	var found operatorv1.GenericProvider
+
+	for _, provider := range genericProviders {
+		if provider.GetType() != target.Type || provider.ProviderName() != target.Name ||
+			(target.Namespace != "" && provider.GetNamespace() != target.Namespace) {
+			continue
+		}
+
+		if found != nil {
+			return nil, fmt.Errorf("multiple %s providers with name %s found, please set the provider namespace", target.Type, target.Name)
+		}
+
+		found = provider
+	}
+
+	if found == nil {
+		return nil, fmt.Errorf("%s provider %s is not installed in the management cluster", target.Type, target.Name)
+	}
+
+	return found, nil
+}
+
+// setProviderVersion patches the provider version, which triggers the upgrade of the provider.
+func setProviderVersion(ctx context.Context, cl client.Client, provider operatorv1.GenericProvider, version string) error {
+	//nolint:forcetypeassert
+	patchBase := client.MergeFrom(provider.DeepCopyObject().(client.Object))
+
+	spec := provider.GetSpec()
+	spec.Version = version
+	provider.SetSpec(spec)
+
+	if err := cl.Patch(ctx, provider, patchBase); err != nil {
+		return fmt.Errorf("cannot upgrade %s provider %s/%s: %w", provider.GetType(), provider.GetNamespace(), provider.GetName(), err)
+	}
+
+	return nil
+}
+
+// failUpgradeStep marks the upgrade step and the plan as failed.
+func failUpgradeStep(plan *operatorv1.ProviderUpgradePlan, step *operatorv1.ProviderUpgradeStep, reason, message string) {
+	step.Phase = operatorv1.ProviderUpgradeStepFailed
+	step.CompletionTime = &metav1.Time{Time: time.Now()}
+	step.Message = message
+
+	conditions.Set(plan, metav1.Condition{
+		Type:    operatorv1.UpgradeCompletedCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  reason,
+		Message: fmt.Sprintf("Failed to upgrade %s provider %s/%s to %s: %s", step.Type, step.Namespace, step.Name, step.Version, message),
+	})
+}
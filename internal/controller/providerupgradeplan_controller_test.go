@@ -0,0 +1,393 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
+)
+
+func upgradePlanTestProviders() []client.Object {
+	return []client.Object{
+		&operatorv1.InfrastructureProvider{
+			ObjectMeta: metav1.ObjectMeta{Name: "aws", Namespace: "capa-system", Generation: 1},
+			Spec:       operatorv1.InfrastructureProviderSpec{ProviderSpec: operatorv1.ProviderSpec{Version: "v2.7.0"}},
+		},
+		&operatorv1.BootstrapProvider{
+			ObjectMeta: metav1.ObjectMeta{Name: "kubeadm", Namespace: "capi-kubeadm-bootstrap-system", Generation: 1},
+			Spec:       operatorv1.BootstrapProviderSpec{ProviderSpec: operatorv1.ProviderSpec{Version: "v1.11.0"}},
+		},
+		&operatorv1.CoreProvider{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-api", Namespace: "capi-system", Generation: 1},
+			Spec:       operatorv1.CoreProviderSpec{ProviderSpec: operatorv1.ProviderSpec{Version: "v1.11.0"}},
+		},
+	}
+}
+
+func TestPlanUpgradeSteps(t *testing.T) {
+	latestVersions := map[string]string{
+		"cluster-api:v1beta2": "v1.12.0",
+		"kubeadm:v1beta2":     "v1.12.0",
+		"aws:v1beta2":         "v2.7.0",
+	}
+
+	contracts := map[string]string{
+		"cluster-api:v1.12.0": "v1beta2",
+		"cluster-api:v1.13.0": "v1beta3",
+		"kubeadm:v1.12.0":     "v1beta2",
+		"kubeadm:v1.13.0":     "v1beta3",
+		"aws:v2.8.0":          "v1beta1",
+	}
+
+	testCases := []struct {
+		name             string
+		spec             operatorv1.ProviderUpgradePlanSpec
+		latestVersions   map[string]string
+		expectedSteps    []string
+		expectedContract string
+		expectedErr      bool
+	}{
+		{
+			name:             "contract",
+			spec:             operatorv1.ProviderUpgradePlanSpec{Contract: "v1beta2"},
+			expectedSteps:    []string{"core/cluster-api:v1.12.0", "bootstrap/kubeadm:v1.12.0"},
+			expectedContract: "v1beta2",
+		},
+		{
+			name: "contract with a provider without release for it",
+			spec: operatorv1.ProviderUpgradePlanSpec{Contract: "v1beta2"},
+			latestVersions: map[string]string{
+				"cluster-api:v1beta2": "v1.12.0",
+			},
+			expectedSteps:    []string{"core/cluster-api:v1.12.0"},
+			expectedContract: "v1beta2",
+		},
+		{
+			name: "contract with a provider release incompatible with the core provider",
+			spec: operatorv1.ProviderUpgradePlanSpec{Contract: "v1beta2"},
+			latestVersions: map[string]string{
+				"cluster-api:v1beta2": "v1.12.0",
+				"kubeadm:v1beta2":     "v1.13.0",
+			},
+			expectedErr: true,
+		},
+		{
+			name:        "unsupported contract",
+			spec:        operatorv1.ProviderUpgradePlanSpec{Contract: "v1beta1"},
+			expectedErr: true,
+		},
+		{
+			name: "providers are ordered",
+			spec: operatorv1.ProviderUpgradePlanSpec{Providers: []operatorv1.ProviderUpgradeTarget{
+				{Type: "infrastructure", Name: "aws", Version: "v2.8.0"},
+				{Type: "core", Name: "cluster-api", Namespace: "capi-system", Version: "v1.12.0"},
+			}},
+			expectedSteps:    []string{"core/cluster-api:v1.12.0", "infrastructure/aws:v2.8.0"},
+			expectedContract: "v1beta2",
+		},
+		{
+			name: "providers upgraded to the contract of the core provider",
+			spec: operatorv1.ProviderUpgradePlanSpec{Providers: []operatorv1.ProviderUpgradeTarget{
+				{Type: "bootstrap", Name: "kubeadm", Version: "v1.13.0"},
+				{Type: "core", Name: "cluster-api", Version: "v1.13.0"},
+			}},
+			expectedSteps:    []string{"core/cluster-api:v1.13.0", "bootstrap/kubeadm:v1.13.0"},
+			expectedContract: "v1beta3",
+		},
+		{
+			name: "provider contract incompatible with the installed core provider",
+			spec: operatorv1.ProviderUpgradePlanSpec{Providers: []operatorv1.ProviderUpgradeTarget{
+				{Type: "bootstrap", Name: "kubeadm", Version: "v1.13.0"},
+			}},
+			expectedErr: true,
+		},
+		{
+			name: "provider contract incompatible with the upgraded core provider",
+			spec: operatorv1.ProviderUpgradePlanSpec{Providers: []operatorv1.ProviderUpgradeTarget{
+				{Type: "core", Name: "cluster-api", Version: "v1.13.0"},
+				{Type: "infrastructure", Name: "aws", Version: "v2.8.0"},
+			}},
+			expectedErr: true,
+		},
+		{
+			name: "provider version without known contract",
+			spec: operatorv1.ProviderUpgradePlanSpec{Providers: []operatorv1.ProviderUpgradeTarget{
+				{Type: "bootstrap", Name: "kubeadm", Version: "v1.12.1"},
+			}},
+			expectedSteps:    []string{"bootstrap/kubeadm:v1.12.1"},
+			expectedContract: "v1beta2",
+		},
+		{
+			name: "provider not installed",
+			spec: operatorv1.ProviderUpgradePlanSpec{Providers: []operatorv1.ProviderUpgradeTarget{
+				{Type: "infrastructure", Name: "azure", Version: "v1.20.0"},
+			}},
+			expectedErr: true,
+		},
+		{
+			name: "provider listed twice",
+			spec: operatorv1.ProviderUpgradePlanSpec{Providers: []operatorv1.ProviderUpgradeTarget{
+				{Type: "core", Name: "cluster-api", Version: "v1.12.0"},
+				{Type: "core", Name: "cluster-api", Version: "v1.12.1"},
+			}},
+			expectedErr: true,
+		},
+		{
+			name: "invalid version",
+			spec: operatorv1.ProviderUpgradePlanSpec{Providers: []operatorv1.ProviderUpgradeTarget{
+				{Type: "core", Name: "cluster-api", Version: "latest"},
+			}},
+			expectedErr: true,
+		},
+		{
+			name: "downgrade",
+			spec: operatorv1.ProviderUpgradePlanSpec{Providers: []operatorv1.ProviderUpgradeTarget{
+				{Type: "core", Name: "cluster-api", Version: "v1.10.0"},
+			}},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			r := &ProviderUpgradePlanReconciler{
+				Client: fake.NewClientBuilder().WithScheme(cacheTestScheme()).WithObjects(upgradePlanTestProviders()...).Build(),
+				latestVersion: func(_ context.Context, provider operatorv1.GenericProvider, contract string) (string, error) {
+					if tc.latestVersions != nil {
+						return tc.latestVersions[provider.ProviderName()+":"+contract], nil
+					}
+
+					return latestVersions[provider.ProviderName()+":"+contract], nil
+				},
+				versionContract: func(_ context.Context, provider operatorv1.GenericProvider, version string) (string, error) {
+					return contracts[provider.ProviderName()+":"+version], nil
+				},
+			}
+
+			steps, contract, err := r.planSteps(context.Background(), &operatorv1.ProviderUpgradePlan{Spec: tc.spec})
+			if tc.expectedErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+
+			actualSteps := []string{}
+			for _, step := range steps {
+				g.Expect(step.Phase).To(Equal(operatorv1.ProviderUpgradeStepPending))
+				actualSteps = append(actualSteps, step.Type+"/"+step.Name+":"+step.Version)
+			}
+
+			g.Expect(actualSteps).To(Equal(tc.expectedSteps))
+
+			if tc.expectedContract != "" {
+				g.Expect(contract).To(Equal(tc.expectedContract))
+			}
+		})
+	}
+}
+
+func TestReconcileProviderUpgradePlan(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	plan := &operatorv1.ProviderUpgradePlan{
+		ObjectMeta: metav1.ObjectMeta{Name: "upgrade", Namespace: "default", Generation: 1},
+		Spec: operatorv1.ProviderUpgradePlanSpec{
+			Providers: []operatorv1.ProviderUpgradeTarget{
+				{Type: "bootstrap", Name: "kubeadm", Version: "v1.12.0"},
+				{Type: "core", Name: "cluster-api", Version: "v1.12.0"},
+			},
+			StepTimeout: &metav1.Duration{Duration: time.Hour},
+		},
+	}
+
+	fakeclient := fake.NewClientBuilder().
+		WithScheme(cacheTestScheme()).
+		WithObjects(append(upgradePlanTestProviders(), plan)...).
+		WithStatusSubresource(&operatorv1.ProviderUpgradePlan{}).
+		Build()
+
+	r := &ProviderUpgradePlanReconciler{
+		Client: fakeclient,
+		versionContract: func(context.Context, operatorv1.GenericProvider, string) (string, error) {
+			return "v1beta2", nil
+		},
+	}
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(plan)}
+
+	// markUpgraded reports the provider as upgraded, like the provider controller does.
+	markUpgraded := func(provider operatorv1.GenericProvider) {
+		g.Expect(fakeclient.Get(ctx, client.ObjectKeyFromObject(provider), provider)).To(Succeed())
+
+		status := provider.GetStatus()
+		status.InstalledVersion = ptr.To(provider.GetSpec().Version)
+		provider.SetStatus(status)
+		conditions.Set(provider, metav1.Condition{Type: operatorv1.ProviderUpgradedCondition, Status: metav1.ConditionTrue, Reason: "Upgraded"})
+		conditions.Set(provider, metav1.Condition{Type: clusterv1.ReadyCondition, Status: metav1.ConditionTrue, Reason: "Ready"})
+
+		g.Expect(fakeclient.Update(ctx, provider)).To(Succeed())
+	}
+
+	// The core provider is upgraded first.
+	result, err := r.Reconcile(ctx, req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.RequeueAfter).To(Equal(upgradePlanStepInterval))
+
+	g.Expect(fakeclient.Get(ctx, req.NamespacedName, plan)).To(Succeed())
+	g.Expect(plan.Status.Steps).To(HaveLen(2))
+	g.Expect(plan.Status.Steps[0].Type).To(Equal("core"))
+	g.Expect(plan.Status.Steps[0].Phase).To(Equal(operatorv1.ProviderUpgradeStepInProgress))
+	g.Expect(plan.Status.Steps[0].FromVersion).To(Equal("v1.11.0"))
+	g.Expect(plan.Status.Steps[1].Phase).To(Equal(operatorv1.ProviderUpgradeStepPending))
+	g.Expect(conditions.GetReason(plan, operatorv1.UpgradeCompletedCondition)).To(Equal(operatorv1.UpgradeInProgressReason))
+
+	core := &operatorv1.CoreProvider{}
+	g.Expect(fakeclient.Get(ctx, client.ObjectKey{Name: "cluster-api", Namespace: "capi-system"}, core)).To(Succeed())
+	g.Expect(core.Spec.Version).To(Equal("v1.12.0"))
+
+	bootstrap := &operatorv1.BootstrapProvider{}
+	g.Expect(fakeclient.Get(ctx, client.ObjectKey{Name: "kubeadm", Namespace: "capi-kubeadm-bootstrap-system"}, bootstrap)).To(Succeed())
+	g.Expect(bootstrap.Spec.Version).To(Equal("v1.11.0"))
+
+	// The plan waits for the core provider to be upgraded.
+	_, err = r.Reconcile(ctx, req)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(fakeclient.Get(ctx, client.ObjectKeyFromObject(bootstrap), bootstrap)).To(Succeed())
+	g.Expect(bootstrap.Spec.Version).To(Equal("v1.11.0"))
+
+	// Once the core provider is upgraded, the bootstrap provider upgrade starts.
+	markUpgraded(core)
+
+	_, err = r.Reconcile(ctx, req)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(fakeclient.Get(ctx, req.NamespacedName, plan)).To(Succeed())
+	g.Expect(plan.Status.Steps[0].Phase).To(Equal(operatorv1.ProviderUpgradeStepCompleted))
+	g.Expect(plan.Status.Steps[1].Phase).To(Equal(operatorv1.ProviderUpgradeStepInProgress))
+
+	g.Expect(fakeclient.Get(ctx, client.ObjectKeyFromObject(bootstrap), bootstrap)).To(Succeed())
+	g.Expect(bootstrap.Spec.Version).To(Equal("v1.12.0"))
+
+	markUpgraded(bootstrap)
+
+	result, err = r.Reconcile(ctx, req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.RequeueAfter).To(BeZero())
+
+	g.Expect(fakeclient.Get(ctx, req.NamespacedName, plan)).To(Succeed())
+	g.Expect(plan.Status.Steps[1].Phase).To(Equal(operatorv1.ProviderUpgradeStepCompleted))
+	g.Expect(conditions.IsTrue(plan, operatorv1.UpgradeCompletedCondition)).To(BeTrue())
+}
+
+func TestReconcileProviderUpgradePlanFailure(t *testing.T) {
+	testCases := []struct {
+		name           string
+		conditions     []metav1.Condition
+		startTime      time.Time
+		expectedReason string
+	}{
+		{
+			name: "upgrade failed",
+			conditions: []metav1.Condition{
+				{Type: operatorv1.ProviderUpgradedCondition, Status: metav1.ConditionFalse, Reason: operatorv1.ComponentsUpgradeErrorReason, Message: "boom", ObservedGeneration: 1},
+			},
+			startTime:      time.Now(),
+			expectedReason: operatorv1.ProviderUpgradeFailedReason,
+		},
+		{
+			name: "preflight checks failed",
+			conditions: []metav1.Condition{
+				{Type: operatorv1.PreflightCheckCondition, Status: metav1.ConditionFalse, Reason: operatorv1.CAPIVersionIncompatibilityReason, ObservedGeneration: 1},
+			},
+			startTime:      time.Now(),
+			expectedReason: operatorv1.ProviderUpgradeFailedReason,
+		},
+		{
+			name:           "step timeout",
+			startTime:      time.Now().Add(-2 * time.Hour),
+			expectedReason: operatorv1.ProviderUpgradeTimeoutReason,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+
+			core := &operatorv1.CoreProvider{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-api", Namespace: "capi-system", Generation: 1},
+				Spec:       operatorv1.CoreProviderSpec{ProviderSpec: operatorv1.ProviderSpec{Version: "v1.12.0"}},
+				Status: operatorv1.CoreProviderStatus{ProviderStatus: operatorv1.ProviderStatus{
+					InstalledVersion: ptr.To("v1.11.0"),
+					Conditions:       tc.conditions,
+				}},
+			}
+
+			plan := &operatorv1.ProviderUpgradePlan{
+				ObjectMeta: metav1.ObjectMeta{Name: "upgrade", Namespace: "default", Generation: 1},
+				Spec: operatorv1.ProviderUpgradePlanSpec{
+					Contract:    "v1beta2",
+					StepTimeout: &metav1.Duration{Duration: time.Hour},
+				},
+				Status: operatorv1.ProviderUpgradePlanStatus{
+					ObservedGeneration: 1,
+					Steps: []operatorv1.ProviderUpgradeStep{{
+						Type:      "core",
+						Name:      "cluster-api",
+						Namespace: "capi-system",
+						Version:   "v1.12.0",
+						Phase:     operatorv1.ProviderUpgradeStepInProgress,
+						StartTime: &metav1.Time{Time: tc.startTime},
+					}},
+				},
+			}
+
+			fakeclient := fake.NewClientBuilder().
+				WithScheme(cacheTestScheme()).
+				WithObjects(core, plan).
+				WithStatusSubresource(&operatorv1.ProviderUpgradePlan{}).
+				Build()
+
+			r := &ProviderUpgradePlanReconciler{Client: fakeclient}
+			req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(plan)}
+
+			result, err := r.Reconcile(ctx, req)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(result.RequeueAfter).To(BeZero())
+
+			g.Expect(fakeclient.Get(ctx, req.NamespacedName, plan)).To(Succeed())
+			g.Expect(plan.Status.Steps[0].Phase).To(Equal(operatorv1.ProviderUpgradeStepFailed))
+			g.Expect(conditions.GetReason(plan, operatorv1.UpgradeCompletedCondition)).To(Equal(tc.expectedReason))
+		})
+	}
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"sigs.k8s.io/cluster-api-operator/internal/controller/genericprovider"
+)
+
+// RenderComponents returns the components of the provider as the controller installs them, without installing
+// them: the manifests are downloaded from the provider source, then altered with the provider customizations,
+// patches, kustomization and image overrides. The client holds the objects the provider refers to, like its
+// configuration secret, and receives the ConfigMap with the downloaded manifests.
+func RenderComponents(ctx context.Context, cl client.Client, provider genericprovider.GenericProvider, options ...PhaseReconcilerOption) ([]unstructured.Unstructured, error) {
+	p := NewPhaseReconciler(GenericProviderReconciler{Client: cl}, provider, nil, options...)
+
+	phases := []PhaseFn{
+		p.InitializePhaseReconciler,
+		p.DownloadManifests,
+		p.Load,
+		p.VerifyVariables,
+		p.Fetch,
+	}
+
+	for _, phase := range phases {
+		if _, err := phase(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	return addNamespaceIfMissing(p.components.Objs(), provider.GetNamespace()), nil
+}
@@ -0,0 +1,221 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	versionutil "k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
+	"sigs.k8s.io/cluster-api-operator/internal/controller/genericprovider"
+)
+
+// defaultVersionPolicyInterval is the default interval the provider version policy is re-evaluated at.
+const defaultVersionPolicyInterval = time.Hour
+
+// resolveVersionPolicy resolves the provider version policy against the repository releases, and records the
+// resolved release in the provider status. The provider spec is left unchanged, to not conflict with the tools
+// managing it. If the policy can't be resolved, the provider falls back to its installed version until the next
+// check.
+func (p *PhaseReconciler) resolveVersionPolicy(ctx context.Context) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	resolvedVersion, err := p.resolvePolicyRelease(ctx)
+
+	// The check is recorded even if it failed, so that it's not retried on every reconciliation.
+	status := p.provider.GetStatus()
+	status.LastVersionPolicyCheck = &metav1.Time{Time: time.Now()}
+
+	if err != nil {
+		if status.InstalledVersion != nil {
+			log.Error(err, "Failed to resolve the version policy, keeping the installed version", "version", *status.InstalledVersion)
+
+			status.ResolvedVersion = ptr.To(*status.InstalledVersion)
+		}
+
+		p.provider.SetStatus(status)
+
+		return err
+	}
+
+	if version := providerVersion(p.provider); resolvedVersion != version {
+		log.Info("Updating provider version according to the version policy", "version", version, "resolvedVersion", resolvedVersion)
+	} else {
+		log.V(2).Info("Provider version satisfies the version policy", "version", version)
+	}
+
+	status.ResolvedVersion = &resolvedVersion
+	p.provider.SetStatus(status)
+
+	return nil
+}
+
+// resolvePolicyRelease returns the release of the provider repository selected by the provider version policy.
+func (p *PhaseReconciler) resolvePolicyRelease(ctx context.Context) (string, error) {
+	spec := p.provider.GetSpec()
+
+	repo, err := p.fetchRepository(ctx, p.provider, p.providerConfig)
+	if err != nil {
+		return "", fmt.Errorf("failed to create repo from provider url for provider %q: %w", p.provider.GetName(), err)
+	}
+
+	releases, err := repo.GetVersions(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get a list of available versions for provider %q: %w", p.provider.GetName(), err)
+	}
+
+	return resolvePolicyVersion(spec.VersionPolicy, spec.Version, releases)
+}
+
+// providerVersion returns the version the provider is installed at: the release resolved by the version policy
+// if the provider has one, or the version set in the spec.
+func providerVersion(provider operatorv1.GenericProvider) string {
+	if resolved := provider.GetStatus().ResolvedVersion; provider.GetSpec().VersionPolicy != nil && resolved != nil && *resolved != "" {
+		return *resolved
+	}
+
+	return provider.GetSpec().Version
+}
+
+// resolvePolicyVersion returns the latest release selected by the policy. If the current version satisfies the
+// policy, only patch releases of its minor version are selected, so minor and major updates require an explicit edit.
+func resolvePolicyVersion(policy *operatorv1.VersionPolicy, currentVersion string, releases []string) (string, error) {
+	var (
+		current    *versionutil.Version
+		constraint *semver.Constraints
+		err        error
+	)
+
+	if currentVersion != "" {
+		current, err = versionutil.ParseSemantic(currentVersion)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse provider version %q: %w", currentVersion, err)
+		}
+	}
+
+	switch {
+	case policy.Constraint != "":
+		constraint, err = parseVersionConstraint(policy.Constraint)
+		if err != nil {
+			return "", err
+		}
+	case policy.LatestPatch:
+		if current == nil {
+			return "", fmt.Errorf("version must be set to select the latest patch release")
+		}
+	}
+
+	samePatchSeries := current != nil && (constraint == nil || satisfiesConstraint(constraint, current))
+
+	var (
+		latest        *versionutil.Version
+		latestRelease string
+	)
+
+	for _, release := range releases {
+		v, err := versionutil.ParseSemantic(release)
+		if err != nil || v.PreRelease() != "" {
+			continue
+		}
+
+		if constraint != nil && !satisfiesConstraint(constraint, v) {
+			continue
+		}
+
+		if samePatchSeries && (v.Major() != current.Major() || v.Minor() != current.Minor()) {
+			continue
+		}
+
+		if latest == nil || latest.LessThan(v) {
+			latest = v
+			latestRelease = release
+		}
+	}
+
+	if current != nil && (latest == nil || latest.LessThan(current)) && samePatchSeries {
+		// Never downgrade the provider, the current version is still valid.
+		return currentVersion, nil
+	}
+
+	if latest == nil {
+		return "", fmt.Errorf("no release satisfies the version policy %q", policy.Constraint)
+	}
+
+	return latestRelease, nil
+}
+
+// isVersionPolicyCheckDue returns true if the provider version policy has to be re-evaluated, either periodically
+// or because the provider spec changed since the last reconciliation.
+func isVersionPolicyCheckDue(provider genericprovider.GenericProvider) bool {
+	if provider.GetSpec().VersionPolicy == nil {
+		return false
+	}
+
+	status := provider.GetStatus()
+	lastCheck := status.LastVersionPolicyCheck
+
+	return lastCheck == nil || status.ObservedGeneration != provider.GetGeneration() ||
+		time.Since(lastCheck.Time) >= versionPolicyInterval(provider)
+}
+
+// versionPolicyRequeueAfter returns the time until the provider version policy has to be re-evaluated.
+func versionPolicyRequeueAfter(provider genericprovider.GenericProvider) time.Duration {
+	interval := versionPolicyInterval(provider)
+
+	lastCheck := provider.GetStatus().LastVersionPolicyCheck
+	if lastCheck == nil {
+		return interval
+	}
+
+	if remaining := interval - time.Since(lastCheck.Time); remaining > 0 {
+		return remaining
+	}
+
+	return interval
+}
+
+// versionPolicyInterval returns the interval the provider version policy is re-evaluated at.
+func versionPolicyInterval(provider genericprovider.GenericProvider) time.Duration {
+	policy := provider.GetSpec().VersionPolicy
+	if policy == nil || policy.Interval == nil {
+		return defaultVersionPolicyInterval
+	}
+
+	return policy.Interval.Duration
+}
+
+// parseVersionConstraint parses a version range like "~v2.7", "^v1.9.0", ">=v1.9 <v1.10" or "v1.9.x || v1.10.x".
+func parseVersionConstraint(s string) (*semver.Constraints, error) {
+	constraint, err := semver.NewConstraint(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version constraint %q: %w", s, err)
+	}
+
+	return constraint, nil
+}
+
+// satisfiesConstraint returns true if the release version satisfies the constraint.
+func satisfiesConstraint(constraint *semver.Constraints, v *versionutil.Version) bool {
+	return constraint.Check(semver.New(uint64(v.Major()), uint64(v.Minor()), uint64(v.Patch()), "", ""))
+}
@@ -0,0 +1,391 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	versionutil "k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/utils/ptr"
+	configclient "sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+
+	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
+)
+
+func TestVersionConstraint(t *testing.T) {
+	testCases := []struct {
+		constraint  string
+		matching    []string
+		notMatching []string
+		expectedErr bool
+	}{
+		{
+			constraint:  "~v2.7",
+			matching:    []string{"v2.7.0", "v2.7.5"},
+			notMatching: []string{"v2.6.9", "v2.8.0", "v3.0.0"},
+		},
+		{
+			constraint:  "~v2.7.2",
+			matching:    []string{"v2.7.2", "v2.7.5"},
+			notMatching: []string{"v2.7.1", "v2.8.0"},
+		},
+		{
+			constraint:  "^v1.9.0",
+			matching:    []string{"v1.9.0", "v1.12.3"},
+			notMatching: []string{"v1.8.9", "v2.0.0"},
+		},
+		{
+			constraint:  "^v0.3.1",
+			matching:    []string{"v0.3.1", "v0.3.9"},
+			notMatching: []string{"v0.4.0"},
+		},
+		{
+			constraint:  ">=v1.9 <v1.10",
+			matching:    []string{"v1.9.0", "v1.9.7"},
+			notMatching: []string{"v1.8.0", "v1.10.0"},
+		},
+		{
+			constraint:  ">= v1.9, < v1.10",
+			matching:    []string{"v1.9.3"},
+			notMatching: []string{"v1.10.0"},
+		},
+		{
+			constraint:  ">v1.9 <=v1.11",
+			matching:    []string{"v1.10.0", "v1.11.4"},
+			notMatching: []string{"v1.9.5", "v1.12.0"},
+		},
+		{
+			constraint:  "v1.9.x || v1.11.x",
+			matching:    []string{"v1.9.2", "v1.11.0"},
+			notMatching: []string{"v1.10.0"},
+		},
+		{
+			constraint:  "v1.9.3",
+			matching:    []string{"v1.9.3"},
+			notMatching: []string{"v1.9.4"},
+		},
+		{
+			constraint:  ">=v1.a",
+			expectedErr: true,
+		},
+		{
+			constraint:  "v1.9 ||",
+			expectedErr: true,
+		},
+		{
+			constraint:  "!v1.9",
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.constraint, func(t *testing.T) {
+			g := NewWithT(t)
+
+			constraint, err := parseVersionConstraint(tc.constraint)
+			if tc.expectedErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+
+			for _, v := range tc.matching {
+				g.Expect(satisfiesConstraint(constraint, versionutil.MustParseSemantic(v))).To(BeTrue(), v)
+			}
+
+			for _, v := range tc.notMatching {
+				g.Expect(satisfiesConstraint(constraint, versionutil.MustParseSemantic(v))).To(BeFalse(), v)
+			}
+		})
+	}
+}
+
+func TestResolvePolicyVersion(t *testing.T) {
+	releases := []string{"v1.9.0", "v1.9.1", "v1.9.2", "v1.10.0", "v1.10.1-rc.0", "v1.10.1", "v2.0.0"}
+
+	testCases := []struct {
+		name            string
+		policy          *operatorv1.VersionPolicy
+		currentVersion  string
+		expectedVersion string
+		expectedErr     bool
+	}{
+		{
+			name:            "latest patch",
+			policy:          &operatorv1.VersionPolicy{LatestPatch: true},
+			currentVersion:  "v1.9.0",
+			expectedVersion: "v1.9.2",
+		},
+		{
+			name:            "latest patch skips pre-releases",
+			policy:          &operatorv1.VersionPolicy{LatestPatch: true},
+			currentVersion:  "v1.10.0",
+			expectedVersion: "v1.10.1",
+		},
+		{
+			name:        "latest patch without version",
+			policy:      &operatorv1.VersionPolicy{LatestPatch: true},
+			expectedErr: true,
+		},
+		{
+			name:            "constraint without version",
+			policy:          &operatorv1.VersionPolicy{Constraint: ">=v1.9 <v2"},
+			expectedVersion: "v1.10.1",
+		},
+		{
+			name:            "constraint keeps the current minor version",
+			policy:          &operatorv1.VersionPolicy{Constraint: ">=v1.9 <v2"},
+			currentVersion:  "v1.9.1",
+			expectedVersion: "v1.9.2",
+		},
+		{
+			name:            "constraint not satisfied by the current version",
+			policy:          &operatorv1.VersionPolicy{Constraint: "~v1.10"},
+			currentVersion:  "v1.9.1",
+			expectedVersion: "v1.10.1",
+		},
+		{
+			name:            "current version is newer than the releases",
+			policy:          &operatorv1.VersionPolicy{LatestPatch: true},
+			currentVersion:  "v1.11.0",
+			expectedVersion: "v1.11.0",
+		},
+		{
+			name:           "no release satisfies the constraint",
+			policy:         &operatorv1.VersionPolicy{Constraint: "~v3.1"},
+			currentVersion: "v1.9.1",
+			expectedErr:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			version, err := resolvePolicyVersion(tc.policy, tc.currentVersion, releases)
+			if tc.expectedErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(version).To(Equal(tc.expectedVersion))
+		})
+	}
+}
+
+func TestIsVersionPolicyCheckDue(t *testing.T) {
+	testCases := []struct {
+		name        string
+		policy      *operatorv1.VersionPolicy
+		lastCheck   *metav1.Time
+		specChanged bool
+		expected    bool
+	}{
+		{
+			name: "no policy",
+		},
+		{
+			name:     "never checked",
+			policy:   &operatorv1.VersionPolicy{LatestPatch: true},
+			expected: true,
+		},
+		{
+			name:      "checked recently",
+			policy:    &operatorv1.VersionPolicy{LatestPatch: true},
+			lastCheck: &metav1.Time{Time: time.Now().Add(-time.Minute)},
+		},
+		{
+			name:        "spec changed since the last check",
+			policy:      &operatorv1.VersionPolicy{LatestPatch: true},
+			lastCheck:   &metav1.Time{Time: time.Now().Add(-time.Minute)},
+			specChanged: true,
+			expected:    true,
+		},
+		{
+			name:      "default interval elapsed",
+			policy:    &operatorv1.VersionPolicy{LatestPatch: true},
+			lastCheck: &metav1.Time{Time: time.Now().Add(-2 * time.Hour)},
+			expected:  true,
+		},
+		{
+			name:      "custom interval elapsed",
+			policy:    &operatorv1.VersionPolicy{LatestPatch: true, Interval: &metav1.Duration{Duration: 30 * time.Second}},
+			lastCheck: &metav1.Time{Time: time.Now().Add(-time.Minute)},
+			expected:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			provider := &operatorv1.CoreProvider{
+				Spec: operatorv1.CoreProviderSpec{
+					ProviderSpec: operatorv1.ProviderSpec{Version: "v1.9.0", VersionPolicy: tc.policy},
+				},
+				Status: operatorv1.CoreProviderStatus{
+					ProviderStatus: operatorv1.ProviderStatus{LastVersionPolicyCheck: tc.lastCheck},
+				},
+			}
+
+			if tc.specChanged {
+				provider.Generation = 2
+				provider.Status.ObservedGeneration = 1
+			}
+
+			g.Expect(isVersionPolicyCheckDue(provider)).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestProviderVersion(t *testing.T) {
+	testCases := []struct {
+		name            string
+		policy          *operatorv1.VersionPolicy
+		resolvedVersion *string
+		expected        string
+	}{
+		{
+			name:     "no policy",
+			expected: "v1.9.0",
+		},
+		{
+			name:            "resolved version is ignored without policy",
+			resolvedVersion: ptr.To("v1.9.2"),
+			expected:        "v1.9.0",
+		},
+		{
+			name:     "policy not resolved yet",
+			policy:   &operatorv1.VersionPolicy{LatestPatch: true},
+			expected: "v1.9.0",
+		},
+		{
+			name:            "policy resolved",
+			policy:          &operatorv1.VersionPolicy{LatestPatch: true},
+			resolvedVersion: ptr.To("v1.9.2"),
+			expected:        "v1.9.2",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			provider := &operatorv1.CoreProvider{
+				Spec: operatorv1.CoreProviderSpec{
+					ProviderSpec: operatorv1.ProviderSpec{Version: "v1.9.0", VersionPolicy: tc.policy},
+				},
+				Status: operatorv1.CoreProviderStatus{
+					ProviderStatus: operatorv1.ProviderStatus{ResolvedVersion: tc.resolvedVersion},
+				},
+			}
+
+			g.Expect(providerVersion(provider)).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestResolveVersionPolicy(t *testing.T) {
+	server := newHTTPTestServer()
+	defer server.Close()
+
+	testCases := []struct {
+		name             string
+		version          string
+		policy           *operatorv1.VersionPolicy
+		installedVersion *string
+		unauthorized     bool
+		expectedErr      bool
+		expectedVersion  *string
+	}{
+		{
+			name:            "latest patch release",
+			version:         "v1.0.0",
+			policy:          &operatorv1.VersionPolicy{LatestPatch: true},
+			expectedVersion: ptr.To("v1.0.0"),
+		},
+		{
+			name:            "latest release satisfying the constraint",
+			policy:          &operatorv1.VersionPolicy{Constraint: ">=v1.0"},
+			expectedVersion: ptr.To("v1.1.0"),
+		},
+		{
+			name:             "releases can't be listed",
+			version:          "v1.0.0",
+			policy:           &operatorv1.VersionPolicy{Constraint: ">=v1.0"},
+			installedVersion: ptr.To("v1.0.0"),
+			unauthorized:     true,
+			expectedErr:      true,
+			expectedVersion:  ptr.To("v1.0.0"),
+		},
+		{
+			name:         "releases can't be listed before the installation",
+			policy:       &operatorv1.VersionPolicy{Constraint: ">=v1.0"},
+			unauthorized: true,
+			expectedErr:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			reader := configclient.NewMemoryReader()
+			if !tc.unauthorized {
+				reader.Set("HTTP_USERNAME", "user")
+				reader.Set("HTTP_PASSWORD", "secret")
+			}
+
+			configClient, err := configclient.New(context.Background(), "", configclient.InjectReader(reader))
+			g.Expect(err).NotTo(HaveOccurred())
+
+			provider := &operatorv1.CoreProvider{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-api", Namespace: "capi-system"},
+				Spec: operatorv1.CoreProviderSpec{ProviderSpec: operatorv1.ProviderSpec{
+					Version:       tc.version,
+					VersionPolicy: tc.policy,
+					FetchConfig: &operatorv1.FetchConfiguration{
+						HTTP: &operatorv1.HTTPConfiguration{URL: server.URL + "/providers/core"},
+					},
+				}},
+				Status: operatorv1.CoreProviderStatus{
+					ProviderStatus: operatorv1.ProviderStatus{InstalledVersion: tc.installedVersion},
+				},
+			}
+
+			p := &PhaseReconciler{provider: provider, configClient: configClient}
+
+			err = p.resolveVersionPolicy(context.Background())
+			if tc.expectedErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+
+			g.Expect(provider.Spec.Version).To(Equal(tc.version))
+			g.Expect(provider.Status.ResolvedVersion).To(Equal(tc.expectedVersion))
+			g.Expect(provider.Status.LastVersionPolicyCheck).NotTo(BeNil())
+			g.Expect(isVersionPolicyCheckDue(provider)).To(BeFalse())
+		})
+	}
+}
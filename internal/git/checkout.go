@@ -0,0 +1,324 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package git downloads the files of a single commit of a git repository over HTTP(S), without a git binary
+// or a local repository.
+package git
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/format/packfile"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/protocol/packp"
+	"github.com/go-git/go-git/v5/plumbing/protocol/packp/capability"
+	"github.com/go-git/go-git/v5/plumbing/protocol/packp/sideband"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+const (
+	defaultTimeout     = 5 * time.Minute
+	defaultMaxPackSize = 256 << 20
+)
+
+// Options defines the repository and the reference to check out.
+type Options struct {
+	// URL is the HTTP(S) URL of the repository, for example https://github.com/kubernetes-sigs/cluster-api.git.
+	URL string
+
+	// Ref is a branch, a tag or a full commit hash. If empty, the HEAD of the repository is checked out.
+	Ref string
+
+	// Paths are the slash-separated paths of the files and directories to read from the commit. If empty,
+	// all the files of the commit are read.
+	Paths []string
+
+	// Username and Password are used for basic authentication, if set. For token authentication,
+	// the token is set as the password.
+	Username string
+	Password string
+
+	// MaxPackSize is the maximum size of the pack downloaded from the repository, in bytes.
+	// A default of 256 MiB is used if zero.
+	MaxPackSize int64
+
+	// Client is the HTTP client used for the requests. A client with a default timeout is used if nil.
+	Client *http.Client
+}
+
+// Checkout is the content of a commit.
+type Checkout struct {
+	// Commit is the hash of the checked out commit.
+	Commit string
+
+	// Files are the contents of the regular files of the commit under the requested paths, keyed by their
+	// slash-separated path. Symbolic links and submodules are not included.
+	Files map[string][]byte
+}
+
+// Clone downloads the files of the reference, fetching only the referenced commit from the repository.
+// The repository must support shallow fetches.
+func Clone(ctx context.Context, opts Options) (*Checkout, error) {
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: defaultTimeout}
+	}
+
+	endpoint, err := transport.NewEndpoint(opts.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	var auth transport.AuthMethod
+	if opts.Username != "" || opts.Password != "" {
+		auth = &githttp.BasicAuth{Username: opts.Username, Password: opts.Password}
+	}
+
+	session, err := githttp.NewClient(opts.Client).NewUploadPackSession(endpoint, auth)
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+
+	refs, err := session.AdvertisedReferencesContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the references of %s: %w", opts.URL, err)
+	}
+
+	want, err := resolveRef(refs, opts.Ref)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the wanted commit is fetched: the request is rejected if the server doesn't support shallow fetches,
+	// rather than fetching the whole history.
+	req := packp.NewUploadPackRequestFromCapabilities(refs.Capabilities)
+	req.Wants = []plumbing.Hash{want}
+	req.Depth = packp.DepthCommits(1)
+
+	if refs.Capabilities.Supports(capability.Shallow) {
+		if err := req.Capabilities.Set(capability.Shallow); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := session.UploadPack(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s from %s: %w", want, opts.URL, err)
+	}
+	defer resp.Close()
+
+	var r io.Reader = resp
+
+	switch {
+	case req.Capabilities.Supports(capability.Sideband64k):
+		r = sideband.NewDemuxer(sideband.Sideband64k, resp)
+	case req.Capabilities.Supports(capability.Sideband):
+		r = sideband.NewDemuxer(sideband.Sideband, resp)
+	}
+
+	storage := memory.NewStorage()
+
+	pack := &limitedReader{r: r, limit: cmp.Or(opts.MaxPackSize, defaultMaxPackSize)}
+	if err := packfile.UpdateObjectStorage(storage, pack); err != nil {
+		return nil, fmt.Errorf("invalid pack received from %s: %w", opts.URL, err)
+	}
+
+	commit, err := peelToCommit(storage, want)
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := readFiles(tree, opts.Paths)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Checkout{Commit: commit.Hash.String(), Files: files}, nil
+}
+
+// resolveRef returns the hash of the object the reference points to. Annotated tags are resolved to
+// the tagged commit when the server advertises it.
+func resolveRef(refs *packp.AdvRefs, ref string) (plumbing.Hash, error) {
+	if plumbing.IsHash(ref) {
+		return plumbing.NewHash(ref), nil
+	}
+
+	if ref == "" {
+		if refs.Head == nil {
+			return plumbing.ZeroHash, fmt.Errorf("the repository has no HEAD")
+		}
+
+		return *refs.Head, nil
+	}
+
+	for _, name := range []string{ref, "refs/tags/" + ref, "refs/heads/" + ref} {
+		if hash, ok := refs.Peeled[name]; ok {
+			return hash, nil
+		}
+
+		if hash, ok := refs.References[name]; ok {
+			return hash, nil
+		}
+	}
+
+	return plumbing.ZeroHash, fmt.Errorf("reference %q not found in the repository", ref)
+}
+
+// peelToCommit returns the commit with the hash, or the commit the annotated tag with the hash points to.
+func peelToCommit(storage *memory.Storage, hash plumbing.Hash) (*object.Commit, error) {
+	obj, err := object.GetObject(storage, hash)
+	if err != nil {
+		return nil, fmt.Errorf("object %s not found in the pack: %w", hash, err)
+	}
+
+	switch obj := obj.(type) {
+	case *object.Commit:
+		return obj, nil
+	case *object.Tag:
+		return obj.Commit()
+	default:
+		return nil, fmt.Errorf("object %s is a %s, not a commit", hash, obj.Type())
+	}
+}
+
+// readFiles returns the contents of the regular files of the tree under the paths, or of all the files of
+// the tree if no path is set. Missing paths are ignored.
+func readFiles(tree *object.Tree, paths []string) (map[string][]byte, error) {
+	files := map[string][]byte{}
+
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+
+	for _, p := range paths {
+		p = path.Clean(p)
+
+		if p == "." {
+			if err := readTree(tree, "", files); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		entry, err := tree.FindEntry(p)
+		if errors.Is(err, object.ErrEntryNotFound) || errors.Is(err, object.ErrDirectoryNotFound) {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if entry.Mode == filemode.Dir {
+			subtree, err := tree.Tree(p)
+			if err != nil {
+				return nil, err
+			}
+
+			if err := readTree(subtree, p+"/", files); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		if entry.Mode.IsFile() && entry.Mode != filemode.Symlink {
+			file, err := tree.TreeEntryFile(entry)
+			if err != nil {
+				return nil, err
+			}
+
+			if files[p], err = readFile(file); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return files, nil
+}
+
+// readTree adds the contents of the regular files of the tree to the files, with the prefix added to their path.
+func readTree(tree *object.Tree, prefix string, files map[string][]byte) error {
+	return tree.Files().ForEach(func(file *object.File) error {
+		if file.Mode == filemode.Symlink {
+			return nil
+		}
+
+		data, err := readFile(file)
+		if err != nil {
+			return err
+		}
+
+		files[prefix+file.Name] = data
+
+		return nil
+	})
+}
+
+// readFile returns the content of the file.
+func readFile(file *object.File) ([]byte, error) {
+	r, err := file.Reader()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %q: %w", file.Name, err)
+	}
+
+	return data, nil
+}
+
+// limitedReader reads from the reader, and returns an error instead of reading more than the limit.
+type limitedReader struct {
+	r     io.Reader
+	limit int64
+	read  int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.read >= l.limit {
+		return 0, fmt.Errorf("the pack is larger than %d bytes", l.limit)
+	}
+
+	if int64(len(p)) > l.limit-l.read {
+		p = p[:l.limit-l.read]
+	}
+
+	n, err := l.r.Read(p)
+	l.read += int64(n)
+
+	return n, err
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package git
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/cgi"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+// newTestRepository creates a repository with two commits, the first one tagged v1.0.0 with an annotated tag,
+// and serves it with git http-backend. It returns the server and the hashes of the two commits.
+func newTestRepository(t *testing.T, username, password string) (*httptest.Server, string, string) {
+	t.Helper()
+
+	gitPath, err := exec.LookPath("git")
+	if err != nil {
+		t.Skip("git binary not available")
+	}
+
+	g := NewWithT(t)
+
+	root := t.TempDir()
+	work := filepath.Join(root, "work")
+
+	run := func(dir string, args ...string) string {
+		cmd := exec.Command(gitPath, args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_NOSYSTEM=1",
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+		)
+
+		out, err := cmd.CombinedOutput()
+		g.Expect(err).NotTo(HaveOccurred(), string(out))
+
+		return strings.TrimSpace(string(out))
+	}
+
+	write := func(name, content string) {
+		g.Expect(os.MkdirAll(filepath.Dir(filepath.Join(work, name)), 0o755)).To(Succeed())
+		g.Expect(os.WriteFile(filepath.Join(work, name), []byte(content), 0o600)).To(Succeed())
+	}
+
+	g.Expect(os.MkdirAll(work, 0o755)).To(Succeed())
+	run(work, "init", "-q", "-b", "main")
+
+	// Large files differing slightly from each other are stored as deltas in the pack.
+	large := strings.Repeat("line of a large manifest\n", 2000)
+
+	write("metadata.yaml", "kind: Metadata\n")
+	write("config/default/components.yaml", large)
+	write("config/base/components.yaml", large+"base\n")
+	run(work, "add", "-A")
+	run(work, "commit", "-q", "-m", "first")
+	run(work, "tag", "-a", "v1.0.0", "-m", "release")
+	first := run(work, "rev-parse", "HEAD")
+
+	write("config/default/components.yaml", large+"one more line\n")
+	write("config/default/extra.yaml", "kind: ConfigMap\n")
+	run(work, "add", "-A")
+	run(work, "commit", "-q", "-m", "second")
+	second := run(work, "rev-parse", "HEAD")
+
+	run(root, "clone", "-q", "--bare", work, "provider.git")
+	run(filepath.Join(root, "provider.git"), "config", "uploadpack.allowAnySHA1InWant", "true")
+	run(filepath.Join(root, "provider.git"), "repack", "-a", "-d", "-q")
+
+	backend := &cgi.Handler{
+		Path: gitPath,
+		Args: []string{"http-backend"},
+		Env:  []string{"GIT_PROJECT_ROOT=" + root, "GIT_HTTP_EXPORT_ALL=1", "GIT_CONFIG_NOSYSTEM=1"},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, _ := r.BasicAuth(); username != "" && (user != username || pass != password) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		backend.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, first, second
+}
+
+func TestClone(t *testing.T) {
+	server, first, second := newTestRepository(t, "user", "token")
+
+	testCases := []struct {
+		name               string
+		ref                string
+		paths              []string
+		maxPackSize        int64
+		password           string
+		expectedCommit     string
+		expectedComponents string
+		expectedExtra      bool
+		expectedErr        bool
+	}{
+		{
+			name:           "HEAD",
+			password:       "token",
+			expectedCommit: second,
+			expectedExtra:  true,
+		},
+		{
+			name:           "branch",
+			ref:            "main",
+			password:       "token",
+			expectedCommit: second,
+			expectedExtra:  true,
+		},
+		{
+			name:           "annotated tag",
+			ref:            "v1.0.0",
+			password:       "token",
+			expectedCommit: first,
+		},
+		{
+			name:           "commit hash",
+			ref:            first,
+			password:       "token",
+			expectedCommit: first,
+		},
+		{
+			name:           "paths",
+			ref:            "main",
+			paths:          []string{"metadata.yaml", "config/base", "missing"},
+			password:       "token",
+			expectedCommit: second,
+		},
+		{
+			name:        "pack too large",
+			ref:         "main",
+			maxPackSize: 100,
+			password:    "token",
+			expectedErr: true,
+		},
+		{
+			name:        "unknown reference",
+			ref:         "v2.0.0",
+			password:    "token",
+			expectedErr: true,
+		},
+		{
+			name:        "invalid credentials",
+			ref:         "main",
+			password:    "wrong",
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			checkout, err := Clone(context.Background(), Options{
+				URL:         server.URL + "/provider.git",
+				Ref:         tc.ref,
+				Paths:       tc.paths,
+				Username:    "user",
+				Password:    tc.password,
+				MaxPackSize: tc.maxPackSize,
+			})
+			if tc.expectedErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(checkout.Commit).To(Equal(tc.expectedCommit))
+			g.Expect(checkout.Files).To(HaveKeyWithValue("metadata.yaml", []byte("kind: Metadata\n")))
+			g.Expect(checkout.Files).To(HaveKeyWithValue("config/base/components.yaml", HaveSuffix("line of a large manifest\nbase\n")))
+
+			if tc.paths != nil {
+				g.Expect(checkout.Files).To(HaveLen(2))
+				return
+			}
+
+			g.Expect(bytes.HasSuffix(checkout.Files["config/default/components.yaml"], []byte("one more line\n"))).To(Equal(tc.expectedExtra))
+
+			if tc.expectedExtra {
+				g.Expect(checkout.Files).To(HaveKey("config/default/extra.yaml"))
+			} else {
+				g.Expect(checkout.Files).NotTo(HaveKey("config/default/extra.yaml"))
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package kustomize renders kustomizations held in memory with the kustomize API. The kustomizations are
+// rendered from the files they are given only: remote resources, bases and components are rejected.
+package kustomize
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilyaml "sigs.k8s.io/cluster-api/util/yaml"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+	"sigs.k8s.io/yaml"
+)
+
+// kustomizationFileNames are the file names a kustomization is looked up with in a directory.
+var kustomizationFileNames = []string{"kustomization.yaml", "kustomization.yml", "Kustomization"}
+
+// overlayBaseFileName is the name of the file the base objects of an overlay are written to.
+const overlayBaseFileName = "overlay-base.generated.yaml"
+
+// Files are the contents of the files a kustomization is rendered from, keyed by their slash-separated path.
+type Files map[string][]byte
+
+// Render renders the kustomization in the directory. If the directory has no kustomization, the manifests of
+// all the YAML and JSON files directly in the directory are returned.
+func Render(files Files, dir string) ([]unstructured.Unstructured, error) {
+	dir = path.Clean(dir)
+
+	if _, ok := files.kustomizationPath(dir); !ok {
+		return files.readDirectory(dir)
+	}
+
+	return files.build(dir)
+}
+
+// RenderOverlay renders the kustomization in the directory over the base objects, which are used as its first
+// resources. The directory must have a kustomization.
+func RenderOverlay(files Files, dir string, base []unstructured.Unstructured) ([]unstructured.Unstructured, error) {
+	dir = path.Clean(dir)
+
+	kustomizationPath, ok := files.kustomizationPath(dir)
+	if !ok {
+		return nil, fmt.Errorf("no kustomization found in %q", dir)
+	}
+
+	basePath := path.Join(dir, overlayBaseFileName)
+	if _, ok := files[basePath]; ok {
+		return nil, fmt.Errorf("%q is reserved for the base objects of the kustomization", basePath)
+	}
+
+	kustomization := map[string]any{}
+	if err := yaml.Unmarshal(files[kustomizationPath], &kustomization); err != nil {
+		return nil, fmt.Errorf("invalid kustomization %q: %w", kustomizationPath, err)
+	}
+
+	resources, _ := kustomization["resources"].([]any)
+	kustomization["resources"] = append([]any{overlayBaseFileName}, resources...)
+
+	kustomizationData, err := yaml.Marshal(kustomization)
+	if err != nil {
+		return nil, err
+	}
+
+	baseData, err := utilyaml.FromUnstructured(base)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize the base objects: %w", err)
+	}
+
+	overlay := make(Files, len(files)+1)
+	for name, data := range files {
+		overlay[name] = data
+	}
+
+	overlay[kustomizationPath] = kustomizationData
+	overlay[basePath] = baseData
+
+	return overlay.build(dir)
+}
+
+// build runs the kustomization in the directory on an in-memory copy of the files.
+func (f Files) build(dir string) ([]unstructured.Unstructured, error) {
+	fSys := filesys.MakeFsInMemory()
+
+	for name, data := range f {
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return nil, fmt.Errorf("file %q is outside of the root directory", name)
+		}
+
+		if slices.Contains(kustomizationFileNames, path.Base(name)) {
+			if err := f.validateKustomization(name); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := fSys.WriteFile(path.Join("/", name), data); err != nil {
+			return nil, err
+		}
+	}
+
+	resMap, err := krusty.MakeKustomizer(krusty.MakeDefaultOptions()).Run(fSys, path.Join("/", dir))
+	if err != nil {
+		return nil, fmt.Errorf("failed to run kustomization %q: %w", dir, err)
+	}
+
+	data, err := resMap.AsYaml()
+	if err != nil {
+		return nil, err
+	}
+
+	return utilyaml.ToUnstructured(data)
+}
+
+// validateKustomization returns an error if the resources, bases, components or patch files of the kustomization
+// are not in the files, which rejects remote references before kustomize fetches them, or if a JSON 6902 patch
+// has no target, which kustomize doesn't check.
+func (f Files) validateKustomization(kustomizationPath string) error {
+	kustomization := &types.Kustomization{}
+	if err := yaml.Unmarshal(f[kustomizationPath], kustomization); err != nil {
+		return fmt.Errorf("invalid kustomization %q: %w", kustomizationPath, err)
+	}
+
+	for i, p := range kustomization.PatchesJson6902 {
+		if p.Target == nil {
+			return fmt.Errorf("JSON 6902 patch %d of kustomization %q has no target", i, kustomizationPath)
+		}
+	}
+
+	refs := slices.Concat(kustomization.Resources, kustomization.Bases, kustomization.Components)
+
+	for _, p := range kustomization.Patches {
+		if p.Path != "" {
+			refs = append(refs, p.Path)
+		}
+	}
+
+	for _, ref := range refs {
+		if !f.exists(path.Join(path.Dir(kustomizationPath), ref)) {
+			return fmt.Errorf("%q referenced by kustomization %q not found, only local references are supported", ref, kustomizationPath)
+		}
+	}
+
+	return nil
+}
+
+// kustomizationPath returns the path of the kustomization in the directory, if any.
+func (f Files) kustomizationPath(dir string) (string, bool) {
+	for _, name := range kustomizationFileNames {
+		if _, ok := f[path.Join(dir, name)]; ok {
+			return path.Join(dir, name), true
+		}
+	}
+
+	return "", false
+}
+
+// exists returns true if there is a file, or files in a directory, at the path.
+func (f Files) exists(name string) bool {
+	if _, ok := f[name]; ok {
+		return true
+	}
+
+	for file := range f {
+		if name == "." || strings.HasPrefix(file, name+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// readDirectory returns the manifests of the YAML and JSON files directly in the directory, in file name order.
+func (f Files) readDirectory(dir string) ([]unstructured.Unstructured, error) {
+	names := []string{}
+
+	for name := range f {
+		if path.Dir(name) != dir {
+			continue
+		}
+
+		if ext := path.Ext(name); ext == ".yaml" || ext == ".yml" || ext == ".json" {
+			names = append(names, name)
+		}
+	}
+
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no manifests found in %q", dir)
+	}
+
+	slices.Sort(names)
+
+	objs := []unstructured.Unstructured{}
+
+	for _, name := range names {
+		fileObjs, err := utilyaml.ToUnstructured(f[name])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %q: %w", name, err)
+		}
+
+		objs = append(objs, fileObjs...)
+	}
+
+	return objs, nil
+}
@@ -0,0 +1,425 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kustomize
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilyaml "sigs.k8s.io/cluster-api/util/yaml"
+)
+
+const (
+	testDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: controller-manager
+  namespace: system
+spec:
+  selector:
+    matchLabels:
+      control-plane: controller-manager
+  template:
+    metadata:
+      labels:
+        control-plane: controller-manager
+    spec:
+      serviceAccountName: manager
+      containers:
+      - name: manager
+        image: gcr.io/k8s-staging-cluster-api/cluster-api-controller:main
+`
+	testRBAC = `apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: manager
+  namespace: system
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: manager-rolebinding
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: manager-role
+subjects:
+- kind: ServiceAccount
+  name: manager
+  namespace: system
+- kind: ServiceAccount
+  name: other
+  namespace: other-system
+`
+	testCRD = `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: clusters.cluster.x-k8s.io
+spec:
+  conversion:
+    strategy: Webhook
+    webhook:
+      clientConfig:
+        service:
+          name: webhook-service
+          namespace: system
+`
+)
+
+func TestRender(t *testing.T) {
+	testCases := []struct {
+		name        string
+		files       Files
+		dir         string
+		expectedErr bool
+		check       func(g *WithT, objs []unstructured.Unstructured)
+	}{
+		{
+			name: "plain manifest directory",
+			files: Files{
+				"manifests/b-rbac.yaml":      []byte(testRBAC),
+				"manifests/a-manager.yaml":   []byte(testDeployment),
+				"manifests/README.md":        []byte("not a manifest"),
+				"manifests/nested/crds.yaml": []byte(testCRD),
+			},
+			dir: "manifests/",
+			check: func(g *WithT, objs []unstructured.Unstructured) {
+				g.Expect(objs).To(HaveLen(3))
+				g.Expect(objs[0].GetKind()).To(Equal("Deployment"))
+				g.Expect(objs[1].GetKind()).To(Equal("ServiceAccount"))
+			},
+		},
+		{
+			name: "kustomization with bases, namespace, labels, patches and images",
+			files: Files{
+				"config/crd/kustomization.yaml":     []byte("resources:\n- crds.yaml\n"),
+				"config/crd/crds.yaml":              []byte(testCRD),
+				"config/manager/kustomization.yaml": []byte("resources:\n- manager.yaml\n"),
+				"config/manager/manager.yaml":       []byte(testDeployment),
+				"config/rbac/kustomization.yaml":    []byte("resources:\n- rbac.yaml\n"),
+				"config/rbac/rbac.yaml":             []byte(testRBAC),
+				"config/default/manager_patch.yaml": []byte("apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: controller-manager\n  namespace: system\nspec:\n  replicas: 2\n"),
+				"config/default/kustomization.yaml": []byte(`apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+namespace: capi-system
+resources:
+- ../crd
+- ../manager
+- ../rbac
+commonLabels:
+  cluster.x-k8s.io/provider: cluster-api
+commonAnnotations:
+  owner: platform
+patches:
+- path: manager_patch.yaml
+- patch: |-
+    - op: add
+      path: /spec/template/spec/containers/0/args
+      value: ["--leader-elect"]
+  target:
+    kind: Deployment
+images:
+- name: gcr.io/k8s-staging-cluster-api/cluster-api-controller
+  newName: registry.example.com/cluster-api-controller
+  newTag: v1.9.3
+`),
+			},
+			dir: "config/default",
+			check: func(g *WithT, objs []unstructured.Unstructured) {
+				g.Expect(objs).To(HaveLen(4))
+
+				crd, deployment, serviceAccount, binding := objs[0], objs[1], objs[2], objs[3]
+
+				g.Expect(crd.GetNamespace()).To(BeEmpty())
+				g.Expect(crd.GetLabels()).To(HaveKeyWithValue("cluster.x-k8s.io/provider", "cluster-api"))
+
+				webhookNamespace, _, _ := unstructured.NestedString(crd.Object, "spec", "conversion", "webhook", "clientConfig", "service", "namespace")
+				g.Expect(webhookNamespace).To(Equal("capi-system"))
+
+				g.Expect(deployment.GetNamespace()).To(Equal("capi-system"))
+				g.Expect(deployment.GetAnnotations()).To(HaveKeyWithValue("owner", "platform"))
+
+				replicas, _, _ := unstructured.NestedFieldNoCopy(deployment.Object, "spec", "replicas")
+				g.Expect(replicas).To(BeEquivalentTo(2))
+
+				selector, _, _ := unstructured.NestedStringMap(deployment.Object, "spec", "selector", "matchLabels")
+				g.Expect(selector).To(HaveKeyWithValue("cluster.x-k8s.io/provider", "cluster-api"))
+
+				templateLabels, _, _ := unstructured.NestedStringMap(deployment.Object, "spec", "template", "metadata", "labels")
+				g.Expect(templateLabels).To(HaveKeyWithValue("cluster.x-k8s.io/provider", "cluster-api"))
+
+				containers, _, _ := unstructured.NestedSlice(deployment.Object, "spec", "template", "spec", "containers")
+				g.Expect(containers[0]).To(HaveKeyWithValue("image", "registry.example.com/cluster-api-controller:v1.9.3"))
+				g.Expect(containers[0]).To(HaveKeyWithValue("args", ConsistOf("--leader-elect")))
+
+				g.Expect(serviceAccount.GetNamespace()).To(Equal("capi-system"))
+
+				subjects, _, _ := unstructured.NestedSlice(binding.Object, "subjects")
+				g.Expect(subjects[0]).To(HaveKeyWithValue("namespace", "capi-system"))
+				g.Expect(subjects[1]).To(HaveKeyWithValue("namespace", "other-system"))
+			},
+		},
+		{
+			name: "kustomization with components, legacy patch fields and transformers",
+			files: Files{
+				"base/manager.yaml":       []byte(testDeployment),
+				"base/kustomization.yaml": []byte("resources:\n- manager.yaml\n"),
+				"components/ha/kustomization.yaml": []byte(`apiVersion: kustomize.config.k8s.io/v1alpha1
+kind: Component
+resources:
+- pdb.yaml
+patches:
+- patch: |-
+    apiVersion: apps/v1
+    kind: Deployment
+    metadata:
+      name: controller-manager
+      namespace: system
+    spec:
+      replicas: 3
+`),
+				"components/ha/pdb.yaml":       []byte("apiVersion: policy/v1\nkind: PodDisruptionBudget\nmetadata:\n  name: controller-manager\n  namespace: system\n"),
+				"overlay/resources-patch.yaml": []byte("apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: controller-manager\n  namespace: system\nspec:\n  template:\n    spec:\n      priorityClassName: system-cluster-critical\n"),
+				"overlay/transformers.yaml": []byte(`apiVersion: builtin
+kind: LabelTransformer
+metadata:
+  name: team
+labels:
+  team: platform
+fieldSpecs:
+- path: metadata/labels
+  create: true
+- kind: Deployment
+  path: spec/template/metadata/labels
+  create: true
+---
+apiVersion: builtin
+kind: ImageTagTransformer
+metadata:
+  name: mirror
+imageTag:
+  name: gcr.io/k8s-staging-cluster-api/cluster-api-controller
+  newName: registry.example.com/cluster-api-controller
+`),
+				"overlay/kustomization.yaml": []byte(`resources:
+- ../base
+components:
+- ../components/ha
+patchesStrategicMerge:
+- resources-patch.yaml
+patchesJson6902:
+- target:
+    kind: Deployment
+    name: controller-manager
+  patch: |-
+    - op: add
+      path: /spec/template/spec/containers/0/args
+      value: ["--leader-elect"]
+transformers:
+- transformers.yaml
+`),
+			},
+			dir: "overlay",
+			check: func(g *WithT, objs []unstructured.Unstructured) {
+				g.Expect(objs).To(HaveLen(2))
+
+				deployment, pdb := objs[0], objs[1]
+
+				g.Expect(pdb.GetKind()).To(Equal("PodDisruptionBudget"))
+				g.Expect(pdb.GetLabels()).To(HaveKeyWithValue("team", "platform"))
+
+				replicas, _, _ := unstructured.NestedFieldNoCopy(deployment.Object, "spec", "replicas")
+				g.Expect(replicas).To(BeEquivalentTo(3))
+
+				priorityClassName, _, _ := unstructured.NestedString(deployment.Object, "spec", "template", "spec", "priorityClassName")
+				g.Expect(priorityClassName).To(Equal("system-cluster-critical"))
+
+				templateLabels, _, _ := unstructured.NestedStringMap(deployment.Object, "spec", "template", "metadata", "labels")
+				g.Expect(templateLabels).To(HaveKeyWithValue("team", "platform"))
+
+				selector, _, _ := unstructured.NestedStringMap(deployment.Object, "spec", "selector", "matchLabels")
+				g.Expect(selector).NotTo(HaveKey("team"))
+
+				containers, _, _ := unstructured.NestedSlice(deployment.Object, "spec", "template", "spec", "containers")
+				g.Expect(containers[0]).To(HaveKeyWithValue("image", "registry.example.com/cluster-api-controller:main"))
+				g.Expect(containers[0]).To(HaveKeyWithValue("args", ConsistOf("--leader-elect")))
+			},
+		},
+		{
+			name: "kustomization with name prefix, labels, replacements and transformers",
+			files: Files{
+				"manager.yaml": []byte(testDeployment),
+				"prefix.yaml":  []byte("apiVersion: builtin\nkind: PrefixSuffixTransformer\nmetadata:\n  name: suffix\nsuffix: -v2\nfieldSpecs:\n- path: metadata/name\n"),
+				"kustomization.yaml": []byte(`namePrefix: capi-
+labels:
+- pairs:
+    cluster.x-k8s.io/provider: cluster-api
+  includeSelectors: false
+replacements:
+- source:
+    kind: Deployment
+    fieldPath: metadata.name
+  targets:
+  - select:
+      kind: Deployment
+    fieldPaths:
+    - spec.template.spec.serviceAccountName
+resources:
+- manager.yaml
+transformers:
+- prefix.yaml
+`),
+			},
+			dir: ".",
+			check: func(g *WithT, objs []unstructured.Unstructured) {
+				g.Expect(objs).To(HaveLen(1))
+				g.Expect(objs[0].GetName()).To(Equal("capi-controller-manager-v2"))
+				g.Expect(objs[0].GetLabels()).To(HaveKeyWithValue("cluster.x-k8s.io/provider", "cluster-api"))
+
+				selector, _, _ := unstructured.NestedStringMap(objs[0].Object, "spec", "selector", "matchLabels")
+				g.Expect(selector).NotTo(HaveKey("cluster.x-k8s.io/provider"))
+
+				serviceAccountName, _, _ := unstructured.NestedString(objs[0].Object, "spec", "template", "spec", "serviceAccountName")
+				g.Expect(serviceAccountName).To(Equal("capi-controller-manager"))
+			},
+		},
+		{
+			name: "kustomization used as component",
+			files: Files{
+				"base/kustomization.yaml": []byte("resources:\n- manager.yaml\n"),
+				"base/manager.yaml":       []byte(testDeployment),
+				"kustomization.yaml":      []byte("components:\n- base\n"),
+			},
+			dir:         ".",
+			expectedErr: true,
+		},
+		{
+			name: "component used as resource",
+			files: Files{
+				"ha/kustomization.yaml": []byte("kind: Component\n"),
+				"kustomization.yaml":    []byte("resources:\n- ha\n"),
+			},
+			dir:         ".",
+			expectedErr: true,
+		},
+		{
+			name: "JSON 6902 patch without target",
+			files: Files{
+				"manager.yaml":       []byte(testDeployment),
+				"kustomization.yaml": []byte("resources:\n- manager.yaml\npatchesJson6902:\n- patch: '[{\"op\": \"remove\", \"path\": \"/spec/replicas\"}]'\n"),
+			},
+			dir:         ".",
+			expectedErr: true,
+		},
+		{
+			name: "unknown kustomization field",
+			files: Files{
+				"kustomization.yaml": []byte("resources:\n- manager.yaml\nnamePrefixes: capi-\n"),
+				"manager.yaml":       []byte(testDeployment),
+			},
+			dir:         ".",
+			expectedErr: true,
+		},
+		{
+			name: "remote resource",
+			files: Files{
+				"kustomization.yaml": []byte("resources:\n- https://github.com/kubernetes-sigs/cluster-api/config/default\n"),
+			},
+			dir:         ".",
+			expectedErr: true,
+		},
+		{
+			name: "resource outside of the root directory",
+			files: Files{
+				"kustomization.yaml": []byte("resources:\n- ../manager.yaml\n"),
+			},
+			dir:         ".",
+			expectedErr: true,
+		},
+		{
+			name: "missing resource",
+			files: Files{
+				"config/kustomization.yaml": []byte("resources:\n- manager.yaml\n"),
+			},
+			dir:         "config",
+			expectedErr: true,
+		},
+		{
+			name: "kustomization cycle",
+			files: Files{
+				"a/kustomization.yaml": []byte("resources:\n- ../b\n"),
+				"b/kustomization.yaml": []byte("resources:\n- ../a\n"),
+			},
+			dir:         "a",
+			expectedErr: true,
+		},
+		{
+			name: "JSON patch without target",
+			files: Files{
+				"kustomization.yaml": []byte("resources:\n- manager.yaml\npatches:\n- patch: '[{\"op\": \"remove\", \"path\": \"/spec/selector\"}]'\n"),
+				"manager.yaml":       []byte(testDeployment),
+			},
+			dir:         ".",
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			objs, err := Render(tc.files, tc.dir)
+			if tc.expectedErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+			tc.check(g, objs)
+		})
+	}
+}
+
+func TestRenderOverlay(t *testing.T) {
+	g := NewWithT(t)
+
+	base, err := utilyaml.ToUnstructured([]byte(testDeployment + "---\n" + testRBAC))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	files := Files{
+		"kustomization.yaml": []byte("namespace: capi-system\nresources:\n- service.yaml\ncommonLabels:\n  team: platform\n"),
+		"service.yaml":       []byte("apiVersion: v1\nkind: Service\nmetadata:\n  name: metrics\n  namespace: system\n"),
+	}
+
+	objs, err := RenderOverlay(files, ".", base)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(objs).To(HaveLen(4))
+
+	g.Expect(objs[0].GetKind()).To(Equal("Deployment"))
+	g.Expect(objs[3].GetKind()).To(Equal("Service"))
+
+	for _, obj := range objs {
+		g.Expect(obj.GetLabels()).To(HaveKeyWithValue("team", "platform"))
+	}
+
+	// The base objects are not modified.
+	g.Expect(base[0].GetNamespace()).To(Equal("system"))
+
+	_, err = RenderOverlay(Files{"service.yaml": files["service.yaml"]}, ".", base)
+	g.Expect(err).To(HaveOccurred())
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package processor
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/drone/envsubst/v2"
+	"github.com/drone/envsubst/v2/parse"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/yamlprocessor"
+)
+
+// defaultOperators are the envsubst operators substituting a default value, when the variable is unset or empty.
+var defaultOperators = map[string]bool{"=": true, ":=": true, "-": true, ":-": true}
+
+// EnvsubstProcessor is a yaml processor substituting the variables with the bash-like syntax of envsubst,
+// including defaults like ${VAR:=default} or ${VAR:-${OTHER_VAR}}, and string functions like ${VAR,,} or
+// ${VAR/old/new}. Unlike the simple processor, a variable is only required if its value is used: the variables
+// of a default value are required only when the default value is substituted, and a variable with a string
+// function but no default value is required.
+type EnvsubstProcessor struct {
+	*yamlprocessor.SimpleProcessor
+}
+
+var _ yamlprocessor.Processor = &EnvsubstProcessor{}
+
+// NewEnvsubstProcessor returns a new envsubst template processor.
+func NewEnvsubstProcessor() *EnvsubstProcessor {
+	return &EnvsubstProcessor{SimpleProcessor: yamlprocessor.NewSimpleProcessor()}
+}
+
+// GetVariables returns the sorted names of the variables of the template.
+func (p *EnvsubstProcessor) GetVariables(rawArtifact []byte) ([]string, error) {
+	variables, err := p.GetVariableMap(rawArtifact)
+	if err != nil {
+		return nil, err
+	}
+
+	return variableNames(variables), nil
+}
+
+// GetVariableMap returns the variables of the template, with their default value if they always have one.
+func (p *EnvsubstProcessor) GetVariableMap(rawArtifact []byte) (map[string]*string, error) {
+	tree, err := parse.Parse(string(rawArtifact))
+	if err != nil {
+		return nil, err
+	}
+
+	variables := map[string]*string{}
+	inspectEnvsubstNode(tree.Root, variables)
+
+	return variables, nil
+}
+
+// Process returns the template with the variables substituted. If required variables are not set, the raw
+// template is returned with a MissingVariablesError.
+func (p *EnvsubstProcessor) Process(rawArtifact []byte, variablesClient func(string) (string, error)) ([]byte, error) {
+	missing, err := p.missingVariables(rawArtifact, variablesClient)
+	if err != nil {
+		return rawArtifact, err
+	}
+
+	if len(missing) > 0 {
+		return rawArtifact, &MissingVariablesError{Missing: missing}
+	}
+
+	processed, err := envsubst.Eval(string(rawArtifact), func(name string) string {
+		value, _ := variablesClient(name)
+		return value
+	})
+	if err != nil {
+		return rawArtifact, err
+	}
+
+	return []byte(processed), nil
+}
+
+// missingVariables returns the sorted names of the variables whose value is used, but not set.
+func (p *EnvsubstProcessor) missingVariables(rawArtifact []byte, variablesClient func(string) (string, error)) ([]string, error) {
+	tree, err := parse.Parse(string(rawArtifact))
+	if err != nil {
+		return nil, err
+	}
+
+	missing := map[string]bool{}
+	findMissingEnvsubstVariables(tree.Root, variablesClient, missing)
+
+	names := make([]string, 0, len(missing))
+	for name := range missing {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// inspectEnvsubstNode adds the variables of the node to the map.
+func inspectEnvsubstNode(node parse.Node, variables map[string]*string) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		for _, child := range n.Nodes {
+			inspectEnvsubstNode(child, variables)
+		}
+	case *parse.FuncNode:
+		var defaultValue *string
+
+		if defaultOperators[n.Name] {
+			value := envsubstText(n.Args)
+			defaultValue = &value
+		}
+
+		addVariable(variables, n.Param, defaultValue)
+
+		for _, arg := range n.Args {
+			inspectEnvsubstNode(arg, variables)
+		}
+	}
+}
+
+// findMissingEnvsubstVariables adds the variables of the node whose value is used, but not set, to the missing set.
+func findMissingEnvsubstVariables(node parse.Node, variablesClient func(string) (string, error), missing map[string]bool) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		for _, child := range n.Nodes {
+			findMissingEnvsubstVariables(child, variablesClient, missing)
+		}
+	case *parse.FuncNode:
+		value, err := variablesClient(n.Param)
+
+		if defaultOperators[n.Name] {
+			// The default value is only substituted if the variable is unset or empty.
+			if err == nil && value != "" {
+				return
+			}
+		} else if err != nil {
+			missing[n.Param] = true
+		}
+
+		for _, arg := range n.Args {
+			findMissingEnvsubstVariables(arg, variablesClient, missing)
+		}
+	}
+}
+
+// envsubstText returns the text of the nodes, with the variables in the ${VAR} format.
+func envsubstText(nodes []parse.Node) string {
+	b := strings.Builder{}
+
+	for _, node := range nodes {
+		switch n := node.(type) {
+		case *parse.TextNode:
+			b.WriteString(n.Value)
+		case *parse.FuncNode:
+			b.WriteString("${" + n.Param + "}")
+		case *parse.ListNode:
+			b.WriteString(envsubstText(n.Nodes))
+		}
+	}
+
+	return b.String()
+}
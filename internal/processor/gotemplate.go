@@ -0,0 +1,209 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package processor
+
+import (
+	"bytes"
+	"errors"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/Masterminds/sprig/v3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/yamlprocessor"
+)
+
+// optionalFuncs are the template functions whose arguments are variables that don't need to be set.
+var optionalFuncs = map[string]bool{"default": true, "coalesce": true, "empty": true}
+
+// GoTemplateProcessor is a yaml processor rendering the template as a Go template, with the variables as fields
+// of the root data, like {{ .EXP_CLUSTER_RESOURCE_SET }}, and sprig helpers.
+//
+// A variable is required, unless it is only used as the condition of an if or a with action, or in a pipeline
+// with the default, coalesce or empty functions. Variables that are not set are empty strings.
+type GoTemplateProcessor struct {
+	*yamlprocessor.SimpleProcessor
+}
+
+var _ yamlprocessor.Processor = &GoTemplateProcessor{}
+
+// NewGoTemplateProcessor returns a new Go template processor.
+func NewGoTemplateProcessor() *GoTemplateProcessor {
+	return &GoTemplateProcessor{SimpleProcessor: yamlprocessor.NewSimpleProcessor()}
+}
+
+// GetVariables returns the sorted names of the variables of the template.
+func (p *GoTemplateProcessor) GetVariables(rawArtifact []byte) ([]string, error) {
+	variables, err := p.GetVariableMap(rawArtifact)
+	if err != nil {
+		return nil, err
+	}
+
+	return variableNames(variables), nil
+}
+
+// GetVariableMap returns the variables of the template, with their default value if they are optional.
+// The default value is the string literal passed to the default function, or an empty string.
+func (p *GoTemplateProcessor) GetVariableMap(rawArtifact []byte) (map[string]*string, error) {
+	tmpl, err := parseGoTemplate(rawArtifact)
+	if err != nil {
+		return nil, err
+	}
+
+	variables := map[string]*string{}
+	inspectTemplateNode(tmpl.Root, nil, variables)
+
+	return variables, nil
+}
+
+// Process returns the rendered template. If required variables are not set, the raw template is returned
+// with a MissingVariablesError.
+func (p *GoTemplateProcessor) Process(rawArtifact []byte, variablesClient func(string) (string, error)) ([]byte, error) {
+	tmpl, err := parseGoTemplate(rawArtifact)
+	if err != nil {
+		return rawArtifact, err
+	}
+
+	variables := map[string]*string{}
+	inspectTemplateNode(tmpl.Root, nil, variables)
+
+	if missing := missingRequiredVariables(variables, variablesClient); len(missing) > 0 {
+		return rawArtifact, &MissingVariablesError{Missing: missing}
+	}
+
+	data := map[string]string{}
+
+	for name := range variables {
+		if value, err := variablesClient(name); err == nil {
+			data[name] = value
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return rawArtifact, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// parseGoTemplate parses the template. Missing fields are rendered as empty strings.
+func parseGoTemplate(rawArtifact []byte) (*template.Template, error) {
+	return template.New("components").Option("missingkey=zero").Funcs(templateFuncs).Parse(string(rawArtifact))
+}
+
+// inspectTemplateNode adds the variables of the node to the map. The default value is set for the variables of
+// optional pipelines.
+func inspectTemplateNode(node parse.Node, defaultValue *string, variables map[string]*string) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+
+		for _, child := range n.Nodes {
+			inspectTemplateNode(child, nil, variables)
+		}
+	case *parse.ActionNode:
+		inspectTemplateNode(n.Pipe, nil, variables)
+	case *parse.IfNode:
+		inspectBranchNode(&n.BranchNode, variables)
+	case *parse.WithNode:
+		inspectBranchNode(&n.BranchNode, variables)
+	case *parse.RangeNode:
+		inspectTemplateNode(n.Pipe, nil, variables)
+		inspectTemplateNode(n.List, nil, variables)
+		inspectTemplateNode(n.ElseList, nil, variables)
+	case *parse.TemplateNode:
+		inspectTemplateNode(n.Pipe, nil, variables)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+
+		if defaultValue == nil {
+			defaultValue = pipelineDefault(n)
+		}
+
+		for _, cmd := range n.Cmds {
+			for _, arg := range cmd.Args {
+				inspectTemplateNode(arg, defaultValue, variables)
+			}
+		}
+	case *parse.FieldNode:
+		addVariable(variables, n.Ident[0], defaultValue)
+	case *parse.VariableNode:
+		// $.VAR refers to the variable from any scope.
+		if len(n.Ident) > 1 && n.Ident[0] == "$" {
+			addVariable(variables, n.Ident[1], defaultValue)
+		}
+	}
+}
+
+// inspectBranchNode adds the variables of an if or a with action, whose condition is optional.
+func inspectBranchNode(n *parse.BranchNode, variables map[string]*string) {
+	empty := ""
+
+	inspectTemplateNode(n.Pipe, &empty, variables)
+	inspectTemplateNode(n.List, nil, variables)
+	inspectTemplateNode(n.ElseList, nil, variables)
+}
+
+// pipelineDefault returns the default value of the variables of the pipeline if it calls one of the optional
+// functions, or nil.
+func pipelineDefault(pipe *parse.PipeNode) *string {
+	for _, cmd := range pipe.Cmds {
+		identifier, ok := cmd.Args[0].(*parse.IdentifierNode)
+		if !ok || !optionalFuncs[identifier.Ident] {
+			continue
+		}
+
+		defaultValue := ""
+
+		if identifier.Ident == "default" && len(cmd.Args) > 1 {
+			if value, ok := cmd.Args[1].(*parse.StringNode); ok {
+				defaultValue = value.Text
+			}
+		}
+
+		return &defaultValue
+	}
+
+	return nil
+}
+
+// templateFuncs are the sprig helpers of the Go templates, and the required function. The functions reading the
+// environment of the operator or resolving host names are removed.
+var templateFuncs = func() template.FuncMap {
+	funcs := sprig.TxtFuncMap()
+
+	for _, name := range []string{"env", "expandenv", "getHostByName"} {
+		delete(funcs, name)
+	}
+
+	funcs["required"] = required
+
+	return funcs
+}()
+
+// required returns the value, or an error with the message if the value is nil or an empty string, like in Helm.
+func required(message string, value any) (any, error) {
+	if value == nil || value == "" {
+		return nil, errors.New(message)
+	}
+
+	return value, nil
+}
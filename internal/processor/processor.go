@@ -0,0 +1,110 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package processor implements the template processors of the provider components, on top of the clusterctl
+// simple processor.
+package processor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/yamlprocessor"
+
+	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
+)
+
+// New returns the template processor of the kind. The clusterctl simple processor is used by default.
+func New(kind operatorv1.TemplateProcessor) (yamlprocessor.Processor, error) {
+	switch kind {
+	case "", operatorv1.SimpleTemplateProcessor:
+		return yamlprocessor.NewSimpleProcessor(), nil
+	case operatorv1.EnvsubstTemplateProcessor:
+		return NewEnvsubstProcessor(), nil
+	case operatorv1.GoTemplateProcessor:
+		return NewGoTemplateProcessor(), nil
+	default:
+		return nil, fmt.Errorf("unsupported template processor %q", kind)
+	}
+}
+
+// MissingVariablesError is returned when the values of variables required by a template are not set.
+type MissingVariablesError struct {
+	Missing []string
+}
+
+func (e *MissingVariablesError) Error() string {
+	sort.Strings(e.Missing)
+
+	return fmt.Sprintf("value for variables [%s] is not set. Please set the value in the provider configuration secret",
+		strings.Join(e.Missing, ", "))
+}
+
+// MissingVariables returns the sorted names of the variables required by the template of the processor whose
+// value is not set.
+func MissingVariables(p yamlprocessor.Processor, rawArtifact []byte, variablesClient func(string) (string, error)) ([]string, error) {
+	// The envsubst processor only requires the variables of the default values that are substituted.
+	if envsubstProcessor, ok := p.(*EnvsubstProcessor); ok {
+		return envsubstProcessor.missingVariables(rawArtifact, variablesClient)
+	}
+
+	variables, err := p.GetVariableMap(rawArtifact)
+	if err != nil {
+		return nil, err
+	}
+
+	return missingRequiredVariables(variables, variablesClient), nil
+}
+
+// missingRequiredVariables returns the sorted names of the variables without a default value that are not set.
+func missingRequiredVariables(variables map[string]*string, variablesClient func(string) (string, error)) []string {
+	missing := []string{}
+
+	for _, name := range variableNames(variables) {
+		if variables[name] != nil {
+			continue
+		}
+
+		if _, err := variablesClient(name); err != nil {
+			missing = append(missing, name)
+		}
+	}
+
+	return missing
+}
+
+// variableNames returns the sorted names of the variables of the map.
+func variableNames(variables map[string]*string) []string {
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// addVariable adds the variable to the map with its default value. A variable is required, with a nil default
+// value, if it is used at least once without a default value.
+func addVariable(variables map[string]*string, name string, defaultValue *string) {
+	if current, ok := variables[name]; ok && (current == nil || defaultValue != nil) {
+		return
+	}
+
+	variables[name] = defaultValue
+}
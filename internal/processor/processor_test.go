@@ -0,0 +1,259 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package processor
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/yamlprocessor"
+
+	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
+)
+
+// variablesGetter returns a variables client getter for the values.
+func variablesGetter(values map[string]string) func(string) (string, error) {
+	return func(name string) (string, error) {
+		value, ok := values[name]
+		if !ok {
+			return "", fmt.Errorf("variable %q not set", name)
+		}
+
+		return value, nil
+	}
+}
+
+func ptr(s string) *string {
+	return &s
+}
+
+func TestNew(t *testing.T) {
+	g := NewWithT(t)
+
+	p, err := New("")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(p).To(BeAssignableToTypeOf(&yamlprocessor.SimpleProcessor{}))
+
+	p, err = New(operatorv1.EnvsubstTemplateProcessor)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(p).To(BeAssignableToTypeOf(&EnvsubstProcessor{}))
+
+	p, err = New(operatorv1.GoTemplateProcessor)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(p).To(BeAssignableToTypeOf(&GoTemplateProcessor{}))
+
+	_, err = New("Jsonnet")
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestEnvsubstProcessor(t *testing.T) {
+	template := `image: ${REGISTRY:=registry.k8s.io}/capi:${TAG:-${DEFAULT_TAG}}
+mode: ${MODE,,}
+name: ${NAME/old/new}
+`
+
+	testCases := []struct {
+		name            string
+		values          map[string]string
+		expected        string
+		expectedMissing []string
+	}{
+		{
+			name:     "all variables set",
+			values:   map[string]string{"REGISTRY": "mirror.example.com", "TAG": "v1.9.3", "MODE": "HA", "NAME": "old-name"},
+			expected: "image: mirror.example.com/capi:v1.9.3\nmode: ha\nname: new-name\n",
+		},
+		{
+			name:     "defaults substituted",
+			values:   map[string]string{"DEFAULT_TAG": "v1.9.0", "MODE": "HA", "NAME": "name"},
+			expected: "image: registry.k8s.io/capi:v1.9.0\nmode: ha\nname: name\n",
+		},
+		{
+			name:            "variables of a used default value are required",
+			values:          map[string]string{"MODE": "HA"},
+			expectedMissing: []string{"DEFAULT_TAG", "NAME"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			p := NewEnvsubstProcessor()
+
+			processed, err := p.Process([]byte(template), variablesGetter(tc.values))
+			if tc.expectedMissing != nil {
+				missingErr := &MissingVariablesError{}
+
+				g.Expect(errors.As(err, &missingErr)).To(BeTrue())
+				g.Expect(missingErr.Missing).To(ConsistOf(tc.expectedMissing))
+				g.Expect(string(processed)).To(Equal(template))
+
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(string(processed)).To(Equal(tc.expected))
+		})
+	}
+
+	g := NewWithT(t)
+
+	variables, err := NewEnvsubstProcessor().GetVariableMap([]byte(template))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(variables).To(Equal(map[string]*string{
+		"REGISTRY":    ptr("registry.k8s.io"),
+		"TAG":         ptr("${DEFAULT_TAG}"),
+		"DEFAULT_TAG": nil,
+		"MODE":        nil,
+		"NAME":        nil,
+	}))
+}
+
+func TestGoTemplateProcessor(t *testing.T) {
+	template := `image: {{ .REGISTRY | default "registry.k8s.io" }}/capi:{{ required "TAG is required" .TAG }}
+{{- if eq .HA "true" }}
+replicas: 3
+{{- end }}
+args: [{{ splitList "," .FEATURES | join ", " }}]
+labels:{{ printf "team: %s" (lower .TEAM) | nindent 2 }}
+`
+
+	testCases := []struct {
+		name            string
+		values          map[string]string
+		expected        string
+		expectedMissing []string
+		expectedErr     bool
+	}{
+		{
+			name:     "all variables set",
+			values:   map[string]string{"REGISTRY": "mirror.example.com", "TAG": "v1.9.3", "HA": "true", "FEATURES": "a,b", "TEAM": "Platform"},
+			expected: "image: mirror.example.com/capi:v1.9.3\nreplicas: 3\nargs: [a, b]\nlabels:\n  team: platform\n",
+		},
+		{
+			name:     "optional variables not set",
+			values:   map[string]string{"TAG": "v1.9.3", "FEATURES": "a", "TEAM": "platform"},
+			expected: "image: registry.k8s.io/capi:v1.9.3\nargs: [a]\nlabels:\n  team: platform\n",
+		},
+		{
+			name:            "required variables not set",
+			values:          map[string]string{"TAG": "v1.9.3"},
+			expectedMissing: []string{"FEATURES", "TEAM"},
+		},
+		{
+			name:        "required function with an empty value",
+			values:      map[string]string{"TAG": "", "FEATURES": "a", "TEAM": "platform"},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			p := NewGoTemplateProcessor()
+
+			processed, err := p.Process([]byte(template), variablesGetter(tc.values))
+			if tc.expectedMissing != nil {
+				missingErr := &MissingVariablesError{}
+
+				g.Expect(errors.As(err, &missingErr)).To(BeTrue())
+				g.Expect(missingErr.Missing).To(ConsistOf(tc.expectedMissing))
+
+				return
+			}
+
+			if tc.expectedErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(string(processed)).To(Equal(tc.expected))
+		})
+	}
+
+	g := NewWithT(t)
+
+	variables, err := NewGoTemplateProcessor().GetVariables([]byte(template))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(variables).To(Equal([]string{"FEATURES", "HA", "REGISTRY", "TAG", "TEAM"}))
+
+	_, err = NewGoTemplateProcessor().GetVariables([]byte("{{ .UNCLOSED "))
+	g.Expect(err).To(HaveOccurred())
+
+	// The functions reading the operator environment or resolving host names are not available.
+	for _, fn := range []string{"env", "expandenv", "getHostByName"} {
+		_, err = NewGoTemplateProcessor().Process([]byte(fmt.Sprintf("{{ %s \"HOME\" }}", fn)), variablesGetter(nil))
+		g.Expect(err).To(MatchError(ContainSubstring("not defined")), fn)
+	}
+}
+
+func TestMissingVariables(t *testing.T) {
+	template := "image: ${REGISTRY:=registry.k8s.io}/capi:${TAG:-${DEFAULT_TAG}}\nregion: ${REGION}\n"
+
+	testCases := []struct {
+		name            string
+		kind            operatorv1.TemplateProcessor
+		values          map[string]string
+		expectedMissing []string
+	}{
+		{
+			name:            "simple processor",
+			expectedMissing: []string{"REGION"},
+		},
+		{
+			name:            "envsubst processor requires the variables of substituted defaults",
+			kind:            operatorv1.EnvsubstTemplateProcessor,
+			expectedMissing: []string{"DEFAULT_TAG", "REGION"},
+		},
+		{
+			name:            "envsubst processor doesn't require the variables of unused defaults",
+			kind:            operatorv1.EnvsubstTemplateProcessor,
+			values:          map[string]string{"TAG": "v1.9.3"},
+			expectedMissing: []string{"REGION"},
+		},
+		{
+			name:            "all variables set",
+			kind:            operatorv1.EnvsubstTemplateProcessor,
+			values:          map[string]string{"TAG": "v1.9.3", "REGION": "eu-west-1"},
+			expectedMissing: []string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			p, err := New(tc.kind)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			missing, err := MissingVariables(p, []byte(template), variablesGetter(tc.values))
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(missing).To(Equal(tc.expectedMissing))
+		})
+	}
+
+	g := NewWithT(t)
+
+	missing, err := MissingVariables(NewGoTemplateProcessor(), []byte(`{{ .REGION }}{{ .TAG | default "v1" }}`), variablesGetter(nil))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(missing).To(Equal([]string{"REGION"}))
+}
@@ -29,4 +29,8 @@ func setDefaultProviderSpec(providerSpec *operatorv1.ProviderSpec, providerNames
 	if providerSpec.AdditionalManifestsRef != nil && providerSpec.AdditionalManifestsRef.Namespace == "" {
 		providerSpec.AdditionalManifestsRef.Namespace = providerNamespace
 	}
+
+	if providerSpec.Kustomization != nil && providerSpec.Kustomization.Namespace == "" {
+		providerSpec.Kustomization.Namespace = providerNamespace
+	}
 }
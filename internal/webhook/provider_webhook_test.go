@@ -100,6 +100,21 @@ func TestSetDefaultProviderSpec(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "shoud default kustomization namespace if not specified",
+			providerSpec: &operatorv1.ProviderSpec{
+				Kustomization: &operatorv1.ConfigmapReference{
+					Name: "test-configmap",
+				},
+			},
+			namespace: testNamespaceName,
+			expectedProviderSpec: &operatorv1.ProviderSpec{
+				Kustomization: &operatorv1.ConfigmapReference{
+					Name:      "test-configmap",
+					Namespace: testNamespaceName,
+				},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
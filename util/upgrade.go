@@ -0,0 +1,317 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	versionutil "k8s.io/apimachinery/pkg/util/version"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	configclient "sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
+)
+
+// ProviderSourceType defines where the provider manifests are fetched from.
+type ProviderSourceType string
+
+const (
+	// ProviderSourceTypeBuiltin is a provider fetched from the URL of the clusterctl configuration.
+	ProviderSourceTypeBuiltin ProviderSourceType = "builtin"
+
+	// ProviderSourceTypeCustomURL is a provider fetched from the URL set in the provider FetchConfig.
+	ProviderSourceTypeCustomURL ProviderSourceType = "custom-url"
+
+	// ProviderSourceTypeConfigMap is a provider fetched from ConfigMaps.
+	ProviderSourceTypeConfigMap ProviderSourceType = "config-map"
+)
+
+// GetInstalledProviders returns the providers installed in the management cluster, starting with the core provider,
+// and the API Version of Cluster API (contract) of the core provider.
+func GetInstalledProviders(ctx context.Context, cl ctrlclient.Client) ([]operatorv1.GenericProvider, string, error) {
+	genericProviders := []operatorv1.GenericProvider{}
+
+	contract := clusterv1.GroupVersion.Version
+
+	// Get Core Providers.
+	var coreProviderList operatorv1.CoreProviderList
+
+	if err := cl.List(ctx, &coreProviderList); err != nil {
+		return nil, "", fmt.Errorf("cannot get a list of core providers from the server: %w", err)
+	}
+
+	if len(coreProviderList.Items) == 1 && coreProviderList.Items[0].Status.Contract != nil {
+		contract = *coreProviderList.Items[0].Status.Contract
+	}
+
+	for i := range coreProviderList.Items {
+		genericProviders = append(genericProviders, &coreProviderList.Items[i])
+	}
+
+	// Get Bootstrap Providers.
+	var bootstrapProviderList operatorv1.BootstrapProviderList
+
+	if err := cl.List(ctx, &bootstrapProviderList); err != nil {
+		return nil, "", fmt.Errorf("cannot get a list of bootstrap providers from the server: %w", err)
+	}
+
+	for i := range bootstrapProviderList.Items {
+		genericProviders = append(genericProviders, &bootstrapProviderList.Items[i])
+	}
+
+	// Get Control Plane Providers.
+	var controlPlaneProviderList operatorv1.ControlPlaneProviderList
+
+	if err := cl.List(ctx, &controlPlaneProviderList); err != nil {
+		return nil, "", fmt.Errorf("cannot get a list of control plane providers from the server: %w", err)
+	}
+
+	for i := range controlPlaneProviderList.Items {
+		genericProviders = append(genericProviders, &controlPlaneProviderList.Items[i])
+	}
+
+	// Get Infrastructure Providers.
+	var infrastructureProviderList operatorv1.InfrastructureProviderList
+
+	if err := cl.List(ctx, &infrastructureProviderList); err != nil {
+		return nil, "", fmt.Errorf("cannot get a list of infrastructure providers from the server: %w", err)
+	}
+
+	for i := range infrastructureProviderList.Items {
+		genericProviders = append(genericProviders, &infrastructureProviderList.Items[i])
+	}
+
+	// Get Addon Providers.
+	var addonProviderList operatorv1.AddonProviderList
+
+	if err := cl.List(ctx, &addonProviderList); err != nil {
+		return nil, "", fmt.Errorf("cannot get a list of addon providers from the server: %w", err)
+	}
+
+	for i := range addonProviderList.Items {
+		genericProviders = append(genericProviders, &addonProviderList.Items[i])
+	}
+
+	// Get IPAM Providers.
+	var ipamProviderList operatorv1.IPAMProviderList
+
+	if err := cl.List(ctx, &ipamProviderList); err != nil {
+		return nil, "", fmt.Errorf("cannot get a list of ipam providers from the server: %w", err)
+	}
+
+	for i := range ipamProviderList.Items {
+		genericProviders = append(genericProviders, &ipamProviderList.Items[i])
+	}
+
+	// Get Runtime Extension Providers.
+	var runtimeExtensionProviderList operatorv1.RuntimeExtensionProviderList
+
+	if err := cl.List(ctx, &runtimeExtensionProviderList); err != nil {
+		return nil, "", fmt.Errorf("cannot get a list of runtime extension providers from the server: %w", err)
+	}
+
+	for i := range runtimeExtensionProviderList.Items {
+		genericProviders = append(genericProviders, &runtimeExtensionProviderList.Items[i])
+	}
+
+	return genericProviders, contract, nil
+}
+
+// GetProviderFetchURL returns the URL the provider manifests are fetched from, and the type of the source.
+// The URL is empty for providers fetched from ConfigMaps.
+func GetProviderFetchURL(ctx context.Context, genericProvider operatorv1.GenericProvider) (string, ProviderSourceType, error) {
+	// Check that fetch url was provider by user.
+	spec := genericProvider.GetSpec()
+	if spec.FetchConfig != nil && spec.FetchConfig.URL != "" {
+		return spec.FetchConfig.URL, ProviderSourceTypeCustomURL, nil
+	}
+
+	// Get fetch url from clusterctl configuration.
+	// TODO: support custom clusterctl configuration.
+	configClient, err := configclient.New(ctx, "")
+	if err != nil {
+		return "", "", err
+	}
+
+	providerConfig, err := configClient.Providers().Get(genericProvider.ProviderName(), ClusterctlProviderType(genericProvider))
+	if err != nil {
+		// TODO: implement support of fetching data from config maps
+		// This is a temporary fix for providers installed from config maps
+		if strings.Contains(err.Error(), "failed to get configuration") {
+			return "", ProviderSourceTypeConfigMap, nil
+		}
+
+		return "", "", err
+	}
+
+	return providerConfig.URL(), ProviderSourceTypeBuiltin, nil
+}
+
+// GetLatestProviderVersion returns the latest release for the current API Version of Cluster API (contract)
+// available in the provider repository.
+func GetLatestProviderVersion(ctx context.Context, providerName, fetchURL string) (string, error) {
+	configClient, err := configclient.New(ctx, "")
+	if err != nil {
+		return "", fmt.Errorf("cannot create config client: %w", err)
+	}
+
+	providerConfig := configclient.NewProvider(providerName, fetchURL, clusterctlv1.ProviderTypeUnknown)
+
+	repo, err := RepositoryFactory(ctx, providerConfig, configClient.Variables())
+	if err != nil {
+		return "", fmt.Errorf("cannot create repository: %w", err)
+	}
+
+	return repo.DefaultVersion(), nil
+}
+
+// GetLatestProviderVersionForContract returns the latest release of the provider implementing the API Version
+// of Cluster API (contract), according to the metadata of the latest release in the provider repository.
+// An empty string is returned if no release implements the contract.
+func GetLatestProviderVersionForContract(ctx context.Context, providerName, fetchURL, contract string) (string, error) {
+	configClient, err := configclient.New(ctx, "")
+	if err != nil {
+		return "", fmt.Errorf("cannot create config client: %w", err)
+	}
+
+	providerConfig := configclient.NewProvider(providerName, fetchURL, clusterctlv1.ProviderTypeUnknown)
+
+	repo, err := RepositoryFactory(ctx, providerConfig, configClient.Variables())
+	if err != nil {
+		return "", fmt.Errorf("cannot create repository: %w", err)
+	}
+
+	versions, err := repo.GetVersions(ctx)
+	if err != nil {
+		return "", fmt.Errorf("cannot list the versions of the provider: %w", err)
+	}
+
+	file, err := repo.GetFile(ctx, repo.DefaultVersion(), "metadata.yaml")
+	if err != nil {
+		return "", fmt.Errorf("cannot read the metadata of version %s: %w", repo.DefaultVersion(), err)
+	}
+
+	metadata := &clusterctlv1.Metadata{}
+	if err := yaml.Unmarshal(file, metadata); err != nil {
+		return "", fmt.Errorf("cannot decode the metadata of version %s: %w", repo.DefaultVersion(), err)
+	}
+
+	return latestVersionForContract(metadata, versions, contract), nil
+}
+
+// latestVersionForContract returns the latest release, excluding pre-releases, whose release series
+// implements the contract, or an empty string if there is none.
+func latestVersionForContract(metadata *clusterctlv1.Metadata, versions []string, contract string) string {
+	var latest *versionutil.Version
+
+	for _, version := range versions {
+		parsed, err := versionutil.ParseSemantic(version)
+		if err != nil || parsed.PreRelease() != "" {
+			continue
+		}
+
+		releaseSeries := metadata.GetReleaseSeriesForVersion(parsed)
+		if releaseSeries == nil || releaseSeries.Contract != contract {
+			continue
+		}
+
+		if latest == nil || latest.LessThan(parsed) {
+			latest = parsed
+		}
+	}
+
+	if latest == nil {
+		return ""
+	}
+
+	return "v" + latest.String()
+}
+
+// GetProviderVersionContract returns the API Version of Cluster API (contract) of the provider version,
+// read from the metadata in the provider repository.
+func GetProviderVersionContract(ctx context.Context, providerName, fetchURL, version string) (string, error) {
+	targetVersion, err := versionutil.ParseSemantic(version)
+	if err != nil {
+		return "", fmt.Errorf("invalid version %q: %w", version, err)
+	}
+
+	configClient, err := configclient.New(ctx, "")
+	if err != nil {
+		return "", fmt.Errorf("cannot create config client: %w", err)
+	}
+
+	providerConfig := configclient.NewProvider(providerName, fetchURL, clusterctlv1.ProviderTypeUnknown)
+
+	repo, err := RepositoryFactory(ctx, providerConfig, configClient.Variables())
+	if err != nil {
+		return "", fmt.Errorf("cannot create repository: %w", err)
+	}
+
+	file, err := repo.GetFile(ctx, version, "metadata.yaml")
+	if err != nil {
+		return "", fmt.Errorf("cannot read the metadata of version %s: %w", version, err)
+	}
+
+	metadata := &clusterctlv1.Metadata{}
+	if err := yaml.Unmarshal(file, metadata); err != nil {
+		return "", fmt.Errorf("cannot decode the metadata of version %s: %w", version, err)
+	}
+
+	releaseSeries := metadata.GetReleaseSeriesForVersion(targetVersion)
+	if releaseSeries == nil {
+		return "", fmt.Errorf("version %s does not match any release series of the provider metadata", version)
+	}
+
+	return releaseSeries.Contract, nil
+}
+
+// ValidateUpgradeContract returns an error if the providers can't be upgraded to the given
+// API Version of Cluster API (contract).
+func ValidateUpgradeContract(contract string) error {
+	if contract != clusterv1.GroupVersion.Version {
+		return fmt.Errorf("current version of capioperator could not upgrade to %s contract (only %s supported)", contract, clusterv1.GroupVersion.Version)
+	}
+
+	return nil
+}
+
+// IsProviderUpgraded checks the ProviderUpgraded and Ready conditions of the provider. An error is returned
+// if the operator failed to upgrade the current generation of the provider.
+func IsProviderUpgraded(provider operatorv1.GenericProvider, version string) (bool, error) {
+	upgraded := conditions.Get(provider, operatorv1.ProviderUpgradedCondition)
+	if upgraded == nil || upgraded.ObservedGeneration < provider.GetGeneration() {
+		return false, nil
+	}
+
+	if upgraded.Status == metav1.ConditionFalse {
+		return false, fmt.Errorf("upgrade failed: %s", upgraded.Message)
+	}
+
+	installedVersion := provider.GetStatus().InstalledVersion
+	if installedVersion == nil || *installedVersion != version {
+		return false, nil
+	}
+
+	return upgraded.Status == metav1.ConditionTrue && conditions.IsTrue(provider, clusterv1.ReadyCondition), nil
+}
@@ -23,6 +23,7 @@ import (
 	"regexp"
 	"strings"
 
+	"k8s.io/apimachinery/pkg/util/sets"
 	operatorv1 "sigs.k8s.io/cluster-api-operator/api/v1alpha2"
 	"sigs.k8s.io/cluster-api-operator/internal/controller/genericprovider"
 	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
@@ -70,6 +71,17 @@ func ClusterctlProviderType(genericProvider operatorv1.GenericProvider) clusterc
 	return clusterctlv1.ProviderTypeUnknown
 }
 
+// CompatibleContracts returns the provider contracts compatible with the given core provider contract.
+// Like clusterctl, the v1beta2 contract is compatible with v1beta1 providers until v1beta1 is EOL.
+func CompatibleContracts(coreContract string) sets.Set[string] {
+	contracts := sets.New(coreContract)
+	if coreContract == "v1beta2" {
+		contracts.Insert("v1beta1")
+	}
+
+	return contracts
+}
+
 // GetCustomProviders retrieves all custom providers using `FetchConfig` that aren't the current provider name / type.
 func GetCustomProviders(ctx context.Context, cl ctrlclient.Client, currProvider genericprovider.GenericProvider) ([]operatorv1.GenericProvider, error) {
 	customProviders := []operatorv1.GenericProvider{}